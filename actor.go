@@ -24,4 +24,37 @@ type ContextPreStarter interface {
 	PreStart(context ActorContext) (err error)
 }
 
+type PostStopper interface {
+	PostStop() (err error)
+}
+
+type PreRestarter interface {
+	PreRestart(cause error, message interface{}) (err error)
+}
+
+type PostRestarter interface {
+	PostRestart(cause error) (err error)
+}
+
+// ChildrenPreserver keeps the children of an actor and its watches on them
+// across its restarts, by default PreRestart stops them and the new instance
+// creates them again
+type ChildrenPreserver interface {
+	PreserveChildrenOnRestart() bool
+}
+
+// Resetter is called on a stopped instance of pooled props before it is
+// zeroed and pooled, to let go of what it holds beyond its own fields
+type Resetter interface {
+	Reset()
+}
+
+// AroundReceiver wraps the handling of every user message, like for logging
+// or metrics. An actor overrides it by declaring the method itself, calling
+// the AroundReceive it embeds handles message with receive, the current
+// behavior, and publishes it if it is unhandled
+type AroundReceiver interface {
+	AroundReceive(receive ReceiveFunc, message interface{}) (handled bool, err error)
+}
+
 type InitFunc func() error
@@ -4,8 +4,11 @@ import (
 	"github.com/go-akka/akka"
 )
 
-func (p *ActorBase) AroundPreReStart(cause error, message interface{}) {
-	p.PreRestart(cause, message)
+func (p *ActorBase) AroundPreReStart(cause error, message interface{}) (err error) {
+	if preRestarter, ok := p.actor.(akka.PreRestarter); ok {
+		return preRestarter.PreRestart(cause, message)
+	}
+	return p.PreRestart(cause, message)
 }
 
 func (p *ActorBase) AroundPreStart() (err error) {
@@ -15,26 +18,35 @@ func (p *ActorBase) AroundPreStart() (err error) {
 	return
 }
 
-func (p *ActorBase) AroundPostRestart(cause error, message interface{}) {
-	p.PostRestart(cause)
+func (p *ActorBase) AroundPostRestart(cause error, message interface{}) (err error) {
+	if postRestarter, ok := p.actor.(akka.PostRestarter); ok {
+		return postRestarter.PostRestart(cause)
+	}
+	return p.PostRestart(cause)
 }
 
-func (p *ActorBase) PreRestart(cause error, message interface{}) {
-	for _, child := range p.Context().Children() {
-		p.Context().Unwatch(child)
-		p.Context().StopChild(child)
+// PreRestart is the default behavior before the failed instance is replaced,
+// it stops all children unless the actor is a ChildrenPreserver and runs
+// PostStop, message is the one that failed
+func (p *ActorBase) PreRestart(cause error, message interface{}) (err error) {
+	if preserver, ok := p.actor.(akka.ChildrenPreserver); !ok || !preserver.PreserveChildrenOnRestart() {
+		for _, child := range p.Context().Children() {
+			p.Context().Unwatch(child)
+			p.Context().StopChild(child)
+		}
 	}
-	p.PostStop()
+	return p.AroundPostStop()
 }
 
-func (p *ActorBase) PostRestart(cause error) {
-	if preStarter, ok := p.actor.(akka.PreStarter); ok {
-		preStarter.PreStart()
-	}
+func (p *ActorBase) PostRestart(cause error) (err error) {
+	return p.AroundPreStart()
 }
 
-func (p *ActorBase) AroundPostStop() {
-	p.PostStop()
+func (p *ActorBase) AroundPostStop() (err error) {
+	if postStopper, ok := p.actor.(akka.PostStopper); ok {
+		return postStopper.PostStop()
+	}
+	return p.PostStop()
 }
 
 func (p *ActorBase) PostStop() (err error) {
@@ -0,0 +1,83 @@
+package actor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+)
+
+// InterceptingActor records every message around its receive
+type InterceptingActor struct {
+	*UntypedActor
+
+	trace chan string
+}
+
+func (p *InterceptingActor) InterceptingActor(trace chan string) {
+	p.trace = trace
+}
+
+func (p *InterceptingActor) AroundReceive(receive akka.ReceiveFunc, message interface{}) (handled bool, err error) {
+	p.trace <- "before"
+	handled, err = p.UntypedActor.AroundReceive(receive, message)
+	p.trace <- "after"
+	return
+}
+
+func (p *InterceptingActor) Receive(message interface{}) (handled bool, err error) {
+	if msg, ok := message.(string); ok {
+		p.trace <- msg
+		return true, nil
+	}
+	return false, nil
+}
+
+func TestAroundReceiveWrapsEveryUserMessage(t *testing.T) {
+	system := newTestActorSystem(t, "AroundReceive")
+	collector := newEventCollector(system, akka.UnhandledMessage{})
+
+	trace := make(chan string, 20)
+
+	interceptingProps, err := props.Create((*InterceptingActor)(nil), trace)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(interceptingProps, "intercepting")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	ref.Tell("first")
+	ref.Tell("second")
+	ref.Tell(42)
+
+	expected := []string{"before", "first", "after", "before", "second", "after", "before", "after"}
+	for i, want := range expected {
+		select {
+		case got := <-trace:
+			if got != want {
+				t.Fatalf("step %d should be %s, but got %s", i, want, got)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("step %d (%s) was not traced", i, want)
+		}
+	}
+
+	select {
+	case e := <-collector.events:
+		if unhandled, ok := e.(*akka.UnhandledMessage); !ok || unhandled.Message != 42 {
+			t.Fatalf("the unhandled message should still be published, but got %v", e)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("unhandled message was not published")
+	}
+
+	select {
+	case got := <-trace:
+		t.Fatalf("only user messages should be intercepted, but also got %s", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -1,10 +1,15 @@
 package actor
 
 import (
+	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/dispatch"
 	"github.com/go-akka/akka/dispatch/sysmsg"
+	"github.com/go-akka/akka/event"
 )
 
 var (
@@ -24,9 +29,33 @@ type ActorCell struct {
 	currentMsg interface{}
 	mailbox    akka.Mailbox
 
+	// awaitingSupervisor is set once the actor failed, until the supervisor
+	// resumes or restarts it
+	awaitingSupervisor bool
+
+	// suspendReasons are the reasons of the Suspend messages not yet matched
+	// by a Resume, the suspension caused by a failure is tracked by
+	// awaitingSupervisor and failureReason
+	suspendReasons []string
+	failureReason  string
+	// terminating is set once the actor stopped its children on Terminate,
+	// it terminates when the last of them did
+	terminating bool
+	// suspensionReason is read by the introspection outside of the mailbox
+	suspensionReason atomic.Value
+
 	behaviorStack *BehaviorStack
 
 	actor *ActorBase
+
+	watching  *akka.ActorRefSet
+	watchedBy *akka.ActorRefSet
+
+	timers map[*Cancelable]bool
+
+	receiveTimeout     time.Duration
+	receiveTimeoutTask *Cancelable
+
 	IChildren
 	IDispatch
 }
@@ -46,6 +75,9 @@ func newActorCell(
 		dispitcher:    dispatcher,
 		parent:        parent,
 		behaviorStack: NewBehaviorStack(),
+		watching:      akka.NewActorRefSet(),
+		watchedBy:     akka.NewActorRefSet(),
+		timers:        make(map[*Cancelable]bool),
 	}
 
 	cell.IDispatch = newActorCellDispatch(cell)
@@ -78,6 +110,57 @@ func (p *ActorCell) Sender() (sender akka.ActorRef) {
 	return p.sender
 }
 
+func (p *ActorCell) Headers() akka.Headers {
+	if envelope, ok := p.currentMsg.(akka.Envelope); ok {
+		return envelope.Headers
+	}
+	return nil
+}
+
+// Tell adds the propagated-headers of the message being received to the
+// headers of message, the headers of message win. With tracing on the span of
+// the message being received becomes the parent of the span of message. It
+// reads the current message, so it is only called from within the receive
+func (p *ActorCell) Tell(target akka.ActorRef, message interface{}) error {
+	current := p.Headers()
+	envelope := akka.NewEnvelope(message, p.self)
+
+	headers := make(akka.Headers, len(envelope.Headers)+len(p.system.settings.PropagatedHeaders)+2)
+	for _, key := range p.system.settings.PropagatedHeaders {
+		// every message told has an id of its own, never the one received
+		if key == DeduplicationIdHeader {
+			continue
+		}
+
+		if value, exist := current[key]; exist {
+			headers[key] = value
+		}
+	}
+
+	if p.system.settings.Tracing {
+		if spanId := current.Get(SpanIdHeader); spanId != "" {
+			headers[TraceIdHeader] = current.Get(TraceIdHeader)
+			headers[SpanIdHeader] = spanId
+		}
+	}
+
+	if len(headers) == 0 {
+		return target.Tell(message, p.self)
+	}
+
+	for k, v := range envelope.Headers {
+		headers[k] = v
+	}
+	return target.Tell(&akka.MessageWithHeaders{Message: envelope.Message, Headers: headers}, p.self)
+}
+
+func (p *ActorCell) Forward(target akka.ActorRef, message interface{}) error {
+	if headers := p.Headers(); len(headers) > 0 {
+		return target.Tell(akka.WithHeaders(message, headers), p.Sender())
+	}
+	return target.Tell(message, p.Sender())
+}
+
 func (p *ActorCell) System() akka.ActorSystem {
 	return p.system
 }
@@ -87,15 +170,24 @@ func (p *ActorCell) Start() {
 }
 
 func (p *ActorCell) Suspend() {
-	return
+	p.SuspendWithReason(SuspendedBySupervisor)
+}
+
+func (p *ActorCell) SuspendWithReason(reason string) {
+	p.SendSystemMessage(&sysmsg.Suspend{Reason: reason})
+}
+
+func (p *ActorCell) SuspensionReason() string {
+	reason, _ := p.suspensionReason.Load().(string)
+	return reason
 }
 
 func (p *ActorCell) Resume(causedByFailure error) {
-	return
+	p.SendSystemMessage(&sysmsg.Resume{CausedByFailure: causedByFailure})
 }
 
-func (p *ActorCell) Restart(cause error) {
-	return
+func (p *ActorCell) Restart(cause error, failed *akka.Envelope) {
+	p.SendSystemMessage(&sysmsg.Recreate{Cause: cause, Envelope: failed})
 }
 
 func (p *ActorCell) Stop() (err error) {
@@ -122,7 +214,13 @@ func (p *ActorCell) NumberOfMessages() int {
 	return p.Mailbox().NumberOfMessages()
 }
 
+// SendMessage dispatches msg to the actor, once the cell is terminated msg
+// goes to the dead letters and ErrActorTerminated is returned
 func (p *ActorCell) SendMessage(msg akka.Envelope) (err error) {
+	if p.IsTerminated() {
+		p.system.deadLetter(msg.Message, msg.Sender, p.self)
+		return fmt.Errorf("%w: %s", ErrActorTerminated, p.self.Path())
+	}
 	return p.dispitcher.Dispatch(p, msg)
 }
 
@@ -139,50 +237,78 @@ func (p *ActorCell) ChildrenRefs() akka.ChildrenContainer {
 }
 
 func (p *ActorCell) GetSingleChild(name string) akka.ActorRef {
+	stats, exist := p.ChildrenRefs().GetByName(name)
+	if !exist {
+		return nil
+	}
+
+	if childStats, ok := stats.(akka.ChildRestartStats); ok {
+		return childStats.Child()
+	}
+
 	return nil
 }
 
 func (p *ActorCell) GetChildByName(name string) (stats akka.ChildStats, exist bool) {
-	return
+	return p.ChildrenRefs().GetByName(name)
 }
 
-func (p *ActorCell) ActorSelection(path akka.ActorPath) (selection akka.ActorSelection, err error) {
-	return
+func (p *ActorCell) ActorSelection(path string) (selection akka.ActorSelection, err error) {
+	return p.system.cachedSelection(newActorSelection(p.system.provider.RootGuardian(), p.self, path, p)), nil
 }
 
+// Become replaces the current behavior with receive if discardOld, else it
+// pushes receive, refusing with a warning beyond behavior-stack-max-depth
 func (p *ActorCell) Become(receive akka.ReceiveFunc, discardOld bool) (err error) {
 	if discardOld && p.behaviorStack.Len() > 0 {
 		p.behaviorStack.Pop()
+	} else if max := p.system.settings.BehaviorStackMaxDepth; max > 0 && p.behaviorStack.Len() >= max {
+		err = fmt.Errorf("%s: %d behaviors of %s", ErrBehaviorStackTooDeep, p.behaviorStack.Len(), p.self.Path())
+		p.publish(event.NewWarningEvent(p.self.Path().String(), p.actor, err.Error()))
+		return
 	}
-	p.behaviorStack.Push(p.actor.Receive)
+
+	p.behaviorStack.Push(receive)
 	return
 }
 
+// Unbecome reverts to the previous behavior, the initial behavior is never
+// popped
 func (p *ActorCell) Unbecome() {
-	p.behaviorStack.Pop()
-	if p.behaviorStack.Len() == 0 {
-		p.behaviorStack.Push(p.actor.Receive)
+	if p.behaviorStack.Len() > 1 {
+		p.behaviorStack.Pop()
 	}
 	return
 }
 
-func (p *ActorCell) ReceiveTimeout() (timeout time.Duration) {
-	return
+// HandleBlockingCall warns that handling envelope held a thread of the
+// dispatcher for elapsed, longer than its blocking-call-warning-threshold
+func (p *ActorCell) HandleBlockingCall(envelope akka.Envelope, elapsed time.Duration) {
+	p.publish(event.NewWarningEvent(p.self.Path().String(), p.actor,
+		fmt.Sprintf("handling [%T] took %s, beyond the blocking-call-warning-threshold of dispatcher [%s], blocking calls should run on a dedicated dispatcher like [%s]",
+			envelope.Message, elapsed, p.props.Dispatcher(), dispatch.DefaultBlockingIODispatcherId)))
 }
 
-func (p *ActorCell) SetReceiveTimeout(timeout time.Duration) {
+func (p *ActorCell) publish(e akka.LogEvent) {
+	p.system.EventStream().Publish(e)
 	return
 }
 
-func (p *ActorCell) Watch(subject akka.ActorRef) (err error) {
-	return
-}
+// newActorSelection anchors absolute paths, with or without an address, at
+// the root guardian and relative paths at anchor
+func newActorSelection(root, anchor akka.InternalActorRef, path string, context akka.ActorContext) akka.ActorSelection {
+	if i := strings.Index(path, "://"); i >= 0 {
+		path = path[i+3:]
+		if j := strings.Index(path, "/"); j >= 0 {
+			path = path[j:]
+		} else {
+			path = "/"
+		}
+	}
 
-func (p *ActorCell) Unwatch(subject akka.ActorRef) {
-	return
-}
+	if strings.HasPrefix(path, "/") {
+		return akka.NewActorSelection(root, path, context)
+	}
 
-func (p *ActorCell) publish(e akka.LogEvent) {
-	p.system.EventStream().Publish(e)
-	return
+	return akka.NewActorSelection(anchor, path, context)
 }
@@ -1,10 +1,13 @@
 package actor
 
 import (
+	"fmt"
 	"github.com/go-akka/akka"
 	"github.com/go-akka/akka/actor/internal"
+	"github.com/go-akka/akka/dispatch"
 	"math/rand"
 	"sync"
+	"sync/atomic"
 )
 
 type IChildren interface {
@@ -15,7 +18,8 @@ type IChildren interface {
 	StopChild(actor akka.ActorRef)
 
 	ReserveChild(name string) bool
-	InitChild(ref akka.ActorRef) *akka.ChildRestartStats
+	InitChild(ref akka.ActorRef) akka.ChildRestartStats
+	RemoveChild(ref akka.ActorRef) bool
 
 	AttachChild(props akka.Props, name string, systemService bool) (akka.ActorRef, error)
 	ChildrenRefs() akka.ChildrenContainer
@@ -31,6 +35,8 @@ type ActorCellChildren struct {
 	childrenContainer akka.ChildrenContainer
 
 	containerLocker sync.Mutex
+
+	nextNameSequence int64
 }
 
 func newActorCellChildren(cell *ActorCell) IChildren {
@@ -38,15 +44,21 @@ func newActorCellChildren(cell *ActorCell) IChildren {
 }
 
 func (p *ActorCellChildren) Children() []akka.ActorRef {
-	return p.childrenContainer.Children()
+	return p.ChildrenRefs().Children()
 }
 
 func (p *ActorCellChildren) ChildrenRefs() akka.ChildrenContainer {
+	p.containerLocker.Lock()
+	defer p.containerLocker.Unlock()
+
 	return p.childrenContainer
 }
 
 func (p *ActorCellChildren) Child(name string) (ref akka.ActorRef, exist bool) {
-	return
+	if ref = p.GetSingleChild(name); ref == nil {
+		return nil, false
+	}
+	return ref, true
 }
 
 func (p *ActorCellChildren) ActorOf(props akka.Props, name string) (ref akka.ActorRef, err error) {
@@ -55,24 +67,38 @@ func (p *ActorCellChildren) ActorOf(props akka.Props, name string) (ref akka.Act
 }
 
 func (p *ActorCellChildren) StopChild(actor akka.ActorRef) {
-	_, exist := p.childrenContainer.GetByRef(actor)
-	if exist {
-		// TODO
+	if stats, exist := p.ChildrenRefs().GetByRef(actor); exist {
+		if recorder, ok := stats.(childStatsRecorder); ok {
+			recorder.RecordStop()
+		}
 	}
 
 	(actor.(akka.InternalActorRef)).Stop()
 }
 
+// ReserveChild reserves name unless a child or reservation already holds it
 func (p *ActorCellChildren) ReserveChild(name string) bool {
-	return p.updateChildrenRefs(p.childrenContainer.Reserve(name))
+	return p.reserveChildIfAbsent(name)
 }
 
 func (p *ActorCellChildren) UnreserveChild(name string) bool {
-	return p.updateChildrenRefs(p.childrenContainer.Unreserve(name))
+	return p.updateChildrenRefs(func(c akka.ChildrenContainer) akka.ChildrenContainer { return c.Unreserve(name) })
 }
 
-func (p *ActorCellChildren) InitChild(ref akka.ActorRef) *akka.ChildRestartStats {
-	return nil
+func (p *ActorCellChildren) InitChild(ref akka.ActorRef) akka.ChildRestartStats {
+	child, ok := ref.(akka.InternalActorRef)
+	if !ok {
+		return nil
+	}
+
+	stats := internal.NewChildRestartStats(child, 0, 0)
+	p.updateChildrenRefs(func(c akka.ChildrenContainer) akka.ChildrenContainer { return c.Add(ref.Path().Name(), stats) })
+
+	return stats
+}
+
+func (p *ActorCellChildren) RemoveChild(ref akka.ActorRef) bool {
+	return p.updateChildrenRefs(func(c akka.ChildrenContainer) akka.ChildrenContainer { return c.Remove(ref) })
 }
 
 func (p *ActorCellChildren) AttachChild(props akka.Props, name string, systemService bool) (ref akka.ActorRef, err error) {
@@ -87,13 +113,42 @@ func (p *ActorCellChildren) NewUID() int {
 	return int(uid)
 }
 
+// makeChild fails with ErrDispatcherNotConfigured if neither the dispatcher
+// of props nor the one of the deployment of the child is configured
 func (p *ActorCellChildren) makeChild(props akka.Props, name string, async bool, systemService bool) (ref akka.ActorRef, err error) {
 
-	p.ReserveChild(name)
+	if !p.system.dispatchers.HasDispatcher(props.Dispatcher()) {
+		err = fmt.Errorf("%s: %s", dispatch.ErrDispatcherNotConfigured, props.Dispatcher())
+		return
+	}
+
+	if p.system.settings.StrictInitFuncs && missingInitFunc(props) {
+		err = fmt.Errorf("%w: %s", ErrActorInitFuncNotFound, props.Type())
+		return
+	}
+
+	if len(name) == 0 {
+		name = p.reserveRandomName()
+	} else if !validNameRegexp.MatchString(name) {
+		err = ErrInvalidActorName
+		return
+	} else if !p.ReserveChild(name) {
+		err = fmt.Errorf("%s: %s", ErrActorNameExists, name)
+		return
+	}
+
 	var actor akka.InternalActorRef
 
 	childPath := akka.NewChildActorPath(p.Self().Path(), name, p.NewUID())
 
+	if deployed, exist := p.system.provider.Deployer().Lookup(childPath); exist && len(deployed.Dispatcher()) > 0 {
+		if !p.system.dispatchers.HasDispatcher(deployed.Dispatcher()) {
+			p.UnreserveChild(name)
+			err = fmt.Errorf("%s: %s", dispatch.ErrDispatcherNotConfigured, deployed.Dispatcher())
+			return
+		}
+	}
+
 	actor = p.system.provider.ActorOf(p.system, props, p.self, childPath, systemService, nil, true, async)
 
 	// if p.Mailbox() != nil {
@@ -108,10 +163,49 @@ func (p *ActorCellChildren) makeChild(props akka.Props, name string, async bool,
 	return
 }
 
-func (p *ActorCellChildren) updateChildrenRefs(newRef akka.ChildrenContainer) bool {
+// reserveRandomName reserves the next generated name like $a, $b... for this
+// parent, they start with '$' so they never clash with validated explicit names
+func (p *ActorCellChildren) reserveRandomName() string {
+	for {
+		name := "$" + base64Name(atomic.AddInt64(&p.nextNameSequence, 1)-1)
+		if p.reserveChildIfAbsent(name) {
+			return name
+		}
+	}
+}
+
+func (p *ActorCellChildren) reserveChildIfAbsent(name string) bool {
+	p.containerLocker.Lock()
+	defer p.containerLocker.Unlock()
+
+	if _, exist := p.childrenContainer.GetByName(name); exist {
+		return false
+	}
+
+	p.childrenContainer = p.childrenContainer.Reserve(name)
+
+	return true
+}
+
+const base64NameChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789+~"
+
+func base64Name(n int64) string {
+	var buf []byte
+	for {
+		buf = append(buf, base64NameChars[n&63])
+		if n >>= 6; n == 0 {
+			break
+		}
+	}
+	return string(buf)
+}
+
+func (p *ActorCellChildren) updateChildrenRefs(update func(akka.ChildrenContainer) akka.ChildrenContainer) bool {
 	p.containerLocker.Lock()
 	defer p.containerLocker.Unlock()
 
+	newRef := update(p.childrenContainer)
+
 	if p.childrenContainer == newRef {
 		return false
 	}
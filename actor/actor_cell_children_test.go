@@ -0,0 +1,229 @@
+package actor
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+)
+
+type anonymousActor struct {
+	*UntypedActor
+}
+
+func (p *anonymousActor) Receive(message interface{}) (handled bool, err error) {
+	return true, nil
+}
+
+func TestAnonymousChildrenGetUniqueNames(t *testing.T) {
+	system := newTestActorSystem(t, "AnonymousChildren")
+
+	anonymousProps, err := props.Create((*anonymousActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	explicit, err := system.ActorOf(anonymousProps, "b")
+	if err != nil {
+		t.Fatalf("create named actor failure: %s", err.Error())
+	}
+
+	const workers, perWorker = 8, 50
+
+	refs := make(chan akka.ActorRef, workers*perWorker)
+	wg := &sync.WaitGroup{}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				ref, err := system.ActorOf(anonymousProps, "")
+				if err != nil {
+					t.Errorf("create anonymous actor failure: %s", err.Error())
+					return
+				}
+				refs <- ref
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(refs)
+
+	names := map[string]bool{explicit.Path().Name(): true}
+	for ref := range refs {
+		name := ref.Path().Name()
+		if !strings.HasPrefix(name, "$") {
+			t.Fatalf("generated name should start with '$', but got %s", name)
+		}
+		if names[name] {
+			t.Fatalf("generated name %s is not unique", name)
+		}
+		names[name] = true
+	}
+
+	if len(names) != workers*perWorker+1 {
+		t.Fatalf("expected %d unique names, but got %d", workers*perWorker+1, len(names))
+	}
+
+	if !names["$a"] || !names["$b"] {
+		t.Fatalf("generated names should be sequential starting at $a")
+	}
+}
+
+func TestExplicitActorNameIsValidated(t *testing.T) {
+	system := newTestActorSystem(t, "ExplicitActorName")
+
+	anonymousProps, err := props.Create((*anonymousActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	for _, name := range []string{"$a", "-leading", "with space"} {
+		if _, err := system.ActorOf(anonymousProps, name); err != ErrInvalidActorName {
+			t.Fatalf("name %q should be rejected with ErrInvalidActorName, but got %v", name, err)
+		}
+	}
+}
+
+func TestConcurrentAttachOfTheSameNameCreatesOneChild(t *testing.T) {
+	system := newTestActorSystem(t, "NameCollision")
+
+	echoProps, err := props.Create((*echoActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	for round := 0; round < 20; round++ {
+		name := fmt.Sprintf("contended-%d", round)
+
+		refs := make([]akka.ActorRef, 2)
+		errs := make([]error, 2)
+
+		start := make(chan struct{})
+		wg := sync.WaitGroup{}
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				<-start
+				refs[i], errs[i] = system.ActorOf(echoProps, name)
+			}(i)
+		}
+		close(start)
+		wg.Wait()
+
+		winner := -1
+		for i, err := range errs {
+			if err == nil {
+				if winner >= 0 {
+					t.Fatalf("only one attach of %s should succeed", name)
+				}
+				winner = i
+			} else if !strings.HasPrefix(err.Error(), ErrActorNameExists.Error()) {
+				t.Fatalf("losing attach of %s should fail with ErrActorNameExists, but got %v", name, err)
+			}
+		}
+
+		if winner < 0 {
+			t.Fatalf("one attach of %s should succeed, but got %v", name, errs)
+		}
+
+		child, exist := system.Guardian().Underlying().(*ActorCell).Child(name)
+		if !exist || child != refs[winner] {
+			t.Fatalf("existing child %s should be kept, but got %v", name, child)
+		}
+	}
+
+	inbox := NewInbox(system)
+	defer inbox.Stop()
+
+	child, _ := system.Guardian().Underlying().(*ActorCell).Child("contended-0")
+	inbox.Send(child, "still-there")
+
+	if msg, err := inbox.Receive(3 * time.Second); err != nil || msg != "still-there" {
+		t.Fatalf("kept child should still reply, but got %v, %v", msg, err)
+	}
+}
+
+func TestBase64Name(t *testing.T) {
+	expected := map[int64]string{0: "a", 1: "b", 25: "z", 26: "A", 63: "~", 64: "ab", 65: "bb"}
+	for n, want := range expected {
+		if got := base64Name(n); got != want {
+			t.Fatalf("base64Name(%d) should be %s, but got %s", n, want, got)
+		}
+	}
+}
+
+func TestStaleIncarnationIsNotResolvedToItsSuccessor(t *testing.T) {
+	system := newTestActorSystem(t, "StaleIncarnation")
+	collector := newEventCollector(system, akka.DeadLetter{})
+
+	echoProps, err := props.Create((*echoActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	old, err := system.ActorOf(echoProps, "worker")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	inbox := NewInbox(system)
+	defer inbox.Stop()
+
+	inbox.Watch(old)
+	old.Tell(&PoisonPill{})
+
+	if message, err := inbox.Receive(3 * time.Second); err != nil {
+		t.Fatalf("old incarnation was not terminated: %v, %v", message, err)
+	}
+
+	successor, err := system.ActorOf(echoProps, "worker")
+	if err != nil {
+		t.Fatalf("recreate actor failure: %s", err.Error())
+	}
+
+	if old.Path().Uid() == 0 || old.Path().Uid() == successor.Path().Uid() {
+		t.Fatalf("incarnations should get distinct uids, but got %d and %d", old.Path().Uid(), successor.Path().Uid())
+	}
+
+	staleSelection, _ := system.ActorSelection(fmt.Sprintf("/user/worker#%d", old.Path().Uid()))
+	if ref, ok := staleSelection.Resolve(); ok {
+		t.Fatalf("selection of the old uid should not resolve, but got %s", ref.Path())
+	}
+
+	currentSelection, _ := system.ActorSelection(fmt.Sprintf("/user/worker#%d", successor.Path().Uid()))
+	if ref, ok := currentSelection.Resolve(); !ok || ref.CompareTo(successor) != 0 {
+		t.Fatalf("selection of the new uid should resolve to the successor, but got %v", ref)
+	}
+
+	if err = inbox.Send(old, "stale"); err != nil {
+		t.Fatalf("send failure: %s", err.Error())
+	}
+
+	select {
+	case e := <-collector.events:
+		{
+			deadLetter := e.(akka.DeadLetter)
+			if deadLetter.Message() != "stale" || deadLetter.Recipient().CompareTo(old) != 0 {
+				t.Fatalf("expected a dead letter of the stale message to the old incarnation, but got %#v", e)
+			}
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("message to the old incarnation was not dead lettered")
+	}
+
+	if reply, err := inbox.Receive(50 * time.Millisecond); err != ErrInboxReceiveTimeout {
+		t.Fatalf("the successor should not get the stale message, but replied %v", reply)
+	}
+
+	if child := system.Guardian().(*LocalActorRef).GetSingleChild("worker"); child == nil || child.CompareTo(successor) != 0 {
+		t.Fatalf("termination of the old incarnation should not remove the successor")
+	}
+}
@@ -1,3 +1,89 @@
 package actor
 
-func (p *ActorCell) ReceivedTerminated(t *Terminated) {}
+import (
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/dispatch/sysmsg"
+)
+
+func (p *ActorCell) Watch(subject akka.ActorRef) (err error) {
+	if subject == nil || p.self.Equals(subject) || p.watching.Contains(subject) {
+		return
+	}
+
+	watchee, ok := subject.(akka.InternalActorRef)
+	if !ok {
+		return
+	}
+
+	p.watching.Add(subject)
+
+	return watchee.SendSystemMessage(&sysmsg.Watch{Watchee: subject, Watcher: p.self})
+}
+
+func (p *ActorCell) Unwatch(subject akka.ActorRef) {
+	if subject == nil || p.self.Equals(subject) || !p.watching.Contains(subject) {
+		return
+	}
+
+	p.watching.Remove(subject)
+
+	if watchee, ok := subject.(akka.InternalActorRef); ok {
+		watchee.SendSystemMessage(&sysmsg.Unwatch{Watchee: subject, Watcher: p.self})
+	}
+}
+
+func (p *ActorCell) ReceivedTerminated(t *Terminated) {
+	p.ReceiveMessage(t)
+}
+
+func (p *ActorCell) watchedActorTerminated(actor akka.ActorRef, existenceConfirmed bool) {
+	if p.watching.Remove(actor) {
+		if !p.IsTerminated() {
+			p.self.Tell(&Terminated{Actor: actor, ExistenceConfirmed: existenceConfirmed}, actor)
+		}
+	}
+
+	if p.ChildrenRefs().Contains(actor) {
+		p.handleChildTerminated(actor)
+	}
+}
+
+func (p *ActorCell) addWatcher(watchee, watcher akka.ActorRef) {
+	if !p.self.Equals(watchee) || p.self.Equals(watcher) {
+		return
+	}
+
+	p.watchedBy.Add(watcher)
+}
+
+func (p *ActorCell) remWatcher(watchee, watcher akka.ActorRef) {
+	if !p.self.Equals(watchee) || p.self.Equals(watcher) {
+		return
+	}
+
+	p.watchedBy.Remove(watcher)
+}
+
+func (p *ActorCell) tellWatchersWeDied() {
+	for _, watcher := range p.watchedBy.Refs() {
+		if p.parent != nil && p.parent.Equals(watcher) {
+			continue
+		}
+
+		if ref, ok := watcher.(akka.InternalActorRef); ok {
+			ref.SendSystemMessage(&sysmsg.DeathWatchNotification{Actor: p.self, ExistenceConfirmed: true})
+		}
+	}
+
+	p.watchedBy.Clear()
+}
+
+func (p *ActorCell) unwatchWatchedActors() {
+	for _, watchee := range p.watching.Refs() {
+		if ref, ok := watchee.(akka.InternalActorRef); ok {
+			ref.SendSystemMessage(&sysmsg.Unwatch{Watchee: watchee, Watcher: p.self})
+		}
+	}
+
+	p.watching.Clear()
+}
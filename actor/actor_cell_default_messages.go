@@ -8,18 +8,38 @@ import (
 )
 
 func (p *ActorCell) Invoke(msg akka.Envelope) (wasHandled bool, err error) {
+	if scheduled, ok := msg.Message.(*scheduledSelfMessage); ok {
+		if !p.takeScheduled(scheduled) {
+			return true, nil
+		}
+		msg.Message = scheduled.message
+	}
 
 	p.currentMsg = msg
-	p.sender = msg.Sender
+	p.sender = p.matchSender(msg)
+
+	if p.system.settings.Tracing {
+		defer p.finishSpan(p.beginSpan(msg))
+	}
 
 	switch message := msg.Message.(type) {
 	case akka.AutoReceivedMessage:
 		{
-			return p.AutoReceiveMessage(msg)
+			wasHandled, err = p.AutoReceiveMessage(msg)
 		}
 	default:
-		return p.ReceiveMessage(message)
+		wasHandled, err = p.ReceiveMessage(message)
+	}
+
+	if err != nil {
+		p.handleInvokeFailure(err)
 	}
+
+	if _, ok := msg.Message.(akka.NotInfluenceReceiveTimeout); !ok && p.receiveTimeout > 0 {
+		p.rescheduleReceiveTimeout()
+	}
+
+	return
 }
 
 func (p *ActorCell) SystemInvoke(msg akka.SystemMessage) (wasHandled bool, err error) {
@@ -32,6 +52,34 @@ func (p *ActorCell) SystemInvoke(msg akka.SystemMessage) (wasHandled bool, err e
 		{
 			p.terminate()
 		}
+	case *sysmsg.Suspend:
+		{
+			p.faultSuspend(v.Reason)
+		}
+	case *sysmsg.Resume:
+		{
+			p.faultResume(v.CausedByFailure)
+		}
+	case *sysmsg.Recreate:
+		{
+			p.faultRecreate(v.Cause, v.Envelope)
+		}
+	case *sysmsg.Failed:
+		{
+			p.handleFailure(v)
+		}
+	case *sysmsg.DeathWatchNotification:
+		{
+			p.watchedActorTerminated(v.Actor, v.ExistenceConfirmed)
+		}
+	case *sysmsg.Watch:
+		{
+			p.addWatcher(v.Watchee, v.Watcher)
+		}
+	case *sysmsg.Unwatch:
+		{
+			p.remWatcher(v.Watchee, v.Watcher)
+		}
 	}
 	return
 }
@@ -42,6 +90,12 @@ func (p *ActorCell) ReceiveMessage(message interface{}) (wasHandled bool, err er
 		// TODO:Create
 		// retrun error
 	}
+
+	// the actor embeds the ActorBase, so this is either its own override or
+	// the default of the ActorBase
+	if receiver, ok := p.actor.actor.(akka.AroundReceiver); ok {
+		return receiver.AroundReceive(fn, message)
+	}
 	return p.actor.AroundReceive(fn, message)
 }
 
@@ -84,45 +138,74 @@ func (p *ActorCell) AutoReceiveMessage(msg akka.Envelope) (wasHandled bool, err
 func (p *ActorCell) create(failure error) {
 
 	if failure != nil {
-		panic(failure)
+		p.failCreation(p.creationError(failure))
+		return
 	}
 
-	created, err := p.props.NewActor()
+	actor, err := p.newActor()
 	if err != nil {
-		panic(err)
+		p.failCreation(err)
+		return
 	}
 
-	actor := NewActorBase(created, p)
+	if err = actor.AroundPreStart(); err != nil {
+		p.failCreation(p.creationError(err))
+		return
+	}
+
+	if p.system.settings.DebugLifecycle {
+		p.publish(event.NewDebugEvent(p.self.Path().String(), actor, "started ("+actor.Self().String()+")"))
+	}
+}
+
+func (p *ActorCell) newActor() (actor *ActorBase, err error) {
+	var created akka.Actor
+	if created, err = p.props.NewActor(); err != nil {
+		err = p.creationError(err)
+		return
+	}
+
+	actor = NewActorBase(created, p)
 
 	if setter, ok := created.(actorBaseSetter); ok {
 		setter.SetActorBase(actor)
 	}
 
 	if constructer, ok := created.(constructer); ok {
-		constructer.construct()
-	}
-
-	if err != nil {
-		panic(err)
+		if err = constructer.construct(); err != nil {
+			err = p.creationError(err)
+			return
+		}
 	}
 
 	p.actor = actor
+	p.behaviorStack = NewBehaviorStack()
+	p.actor.Become(p.actor.Receive, false)
 
-	if err = actor.AroundPreStart(); err != nil {
-		panic(err)
+	return
+}
+
+func (p *ActorCell) creationError(cause error) error {
+	return &ActorCreationError{
+		Path:         p.self.Path(),
+		ActorType:    p.props.Type(),
+		ProducerType: p.props.ProducerType(),
+		Cause:        cause,
 	}
+}
 
-	p.actor.Become(p.actor.Receive, false)
+// failCreation publishes the creation failure and reports it to the
+// supervisor as an ActorInitializationError, the mailbox stays suspended until
+// it decided, by default the actor is stopped
+func (p *ActorCell) failCreation(err error) {
+	p.publish(event.NewErrorEvent(err, p.self.Path().String(), p.props.Type(), "error while creating actor"))
+	p.failInitialization(err)
+}
 
-	if p.system.settings.DebugLifecycle {
-		p.publish(event.NewDebugEvent(p.self.Path().String(), actor, "started ("+actor.Self().String()+")"))
-	}
+func (p *ActorCell) failInitialization(cause error) {
+	p.reportFailure(&ActorInitializationError{Actor: p.self, Cause: cause}, nil, false)
 }
 
 func (p *ActorCell) matchSender(envelope akka.Envelope) akka.ActorRef {
-	sender := envelope.Sender
-	if sender == nil {
-		sender = p.system.deadletters
-	}
-	return sender
+	return p.system.senderOrDeadLetters(envelope.Sender)
 }
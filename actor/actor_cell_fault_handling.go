@@ -1,4 +1,276 @@
 package actor
 
+import (
+	"fmt"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/dispatch/sysmsg"
+	"github.com/go-akka/akka/event"
+)
+
+const (
+	// SuspendedBySupervisor is the reason of a Suspend given none
+	SuspendedBySupervisor = "suspended by the supervisor"
+	// SuspendedAwaitingSupervisor prefixes the reason of an actor that failed,
+	// followed by the failure
+	SuspendedAwaitingSupervisor = "awaiting the decision of the supervisor"
+)
+
+// terminate stops the children and finishes once they terminated, the user
+// messages wait in the suspended mailbox in the meantime and end up in the
+// dead letters
 func (p *ActorCell) terminate() {
+	if p.IsTerminated() || p.terminating {
+		return
+	}
+
+	p.unwatchWatchedActors()
+
+	children := p.Children()
+	if len(children) == 0 {
+		p.finishTerminate()
+		return
+	}
+
+	p.terminating = true
+	p.mailbox.Suspend()
+
+	for _, child := range children {
+		p.StopChild(child)
+	}
+}
+
+func (p *ActorCell) finishTerminate() {
+	p.cancelTimers()
+
+	if p.actor != nil {
+		if err := p.actor.AroundPostStop(); err != nil {
+			p.publish(event.NewErrorEvent(err, p.self.Path().String(), p.actor, "error while executing PostStop"))
+		}
+	}
+
+	p.mailbox.BecomeClosed()
+	p.mailbox.CleanUp(p.self, deadLetterQueue{system: p.system})
+	p.dispitcher.Detach(p)
+
+	if provider, ok := p.system.provider.(*LocalActorRefProvider); ok {
+		provider.unregisterActor(p.self)
+	}
+
+	p.tellWatchersWeDied()
+	p.parent.SendSystemMessage(&sysmsg.DeathWatchNotification{Actor: p.self, ExistenceConfirmed: true})
+
+	if p.system.settings.DebugLifecycle {
+		p.publish(event.NewDebugEvent(p.self.Path().String(), p.actor, "stopped"))
+	}
+
+	p.releaseActor(p.actor)
+}
+
+// releaseActor hands a stopped or replaced instance back to pooled props
+func (p *ActorCell) releaseActor(actor *ActorBase) {
+	if actor == nil || !p.props.Pooling() {
+		return
+	}
+
+	p.props.ReleaseActor(actor.actor)
+
+	if p.actor == actor {
+		p.actor = nil
+	}
+}
+
+// removeStoppingChildren gives up the names of the children stopped before a
+// restart, so the new instance can create them again while they terminate
+func (p *ActorCell) removeStoppingChildren() {
+	for _, stats := range p.ChildrenRefs().Stats() {
+		if stats.State() == akka.ChildStopping {
+			p.RemoveChild(stats.Child())
+		}
+	}
+}
+
+func (p *ActorCell) handleChildTerminated(child akka.ActorRef) {
+	p.RemoveChild(child)
+
+	if p.terminating && len(p.Children()) == 0 && !p.IsTerminated() {
+		p.finishTerminate()
+	}
+}
+
+func (p *ActorCell) supervisorStrategy() akka.SupervisorStrategy {
+	if p.actor != nil {
+		if provider, ok := p.actor.actor.(akka.SupervisorStrategyProvider); ok {
+			if strategy := provider.SupervisorStrategy(); strategy != nil {
+				return strategy
+			}
+		}
+	}
+	return DefaultSupervisorStrategy
+}
+
+func (p *ActorCell) handleInvokeFailure(cause error) {
+	var failed *akka.Envelope
+	if envelope, ok := p.currentMsg.(akka.Envelope); ok {
+		failed = &envelope
+	}
+	p.reportFailure(cause, failed, false)
+}
+
+// escalate reports a failure of a child the strategy did not handle as a
+// failure of this actor to its parent, there is no message to resend then
+func (p *ActorCell) escalate(cause error) {
+	p.reportFailure(cause, nil, true)
+}
+
+// reportFailure hands the failed envelope to the parent, which passes it
+// back with Restart
+func (p *ActorCell) reportFailure(cause error, failed *akka.Envelope, escalated bool) {
+	// the actor stays suspended, keeping the rest of its mailbox in order,
+	// until the supervisor decides to resume, restart or stop it
+	if !p.awaitingSupervisor {
+		p.mailbox.Suspend()
+		p.awaitingSupervisor = true
+		p.failureReason = fmt.Sprintf("%s: %s", SuspendedAwaitingSupervisor, cause)
+		p.updateSuspensionReason()
+	}
+
+	p.parent.SendSystemMessage(&sysmsg.Failed{Child: p.self, Cause: cause, Uid: p.self.Path().Uid(), Envelope: failed, Escalated: escalated})
+}
+
+// HandlePanic is called by the mailbox after it recovered a panic of this
+// actor, the panic is logged and reported to the parent as a failure
+func (p *ActorCell) HandlePanic(recovered interface{}, stack []byte) {
+	cause := &ActorPanicError{Path: p.self.Path(), Value: recovered, Stack: stack}
+
+	p.publish(event.NewErrorEvent(cause, p.self.Path().String(), p.actor, fmt.Sprintf("%s\n%s", cause.Error(), stack)))
+
+	if !p.IsTerminated() {
+		p.handleInvokeFailure(cause)
+	}
+}
+
+func (p *ActorCell) faultSuspend(reason string) {
+	if reason == "" {
+		reason = SuspendedBySupervisor
+	}
+
+	p.suspendReasons = append(p.suspendReasons, reason)
+	p.updateSuspensionReason()
+	p.mailbox.Suspend()
+}
+
+// faultResume only removes the suspension it is matched with, so a resume
+// without a suspend can not release an actor that waits for its supervisor
+func (p *ActorCell) faultResume(causedByFailure error) {
+	if causedByFailure != nil {
+		if !p.awaitingSupervisor {
+			return
+		}
+		p.awaitingSupervisor = false
+		p.failureReason = ""
+	} else {
+		if len(p.suspendReasons) == 0 {
+			return
+		}
+		p.suspendReasons = p.suspendReasons[:len(p.suspendReasons)-1]
+	}
+
+	p.updateSuspensionReason()
+	p.mailbox.Resume()
+}
+
+// updateSuspensionReason publishes the reason of the failure while the
+// supervisor decides, else the reason of the latest Suspend
+func (p *ActorCell) updateSuspensionReason() {
+	reason := p.failureReason
+	if reason == "" && len(p.suspendReasons) > 0 {
+		reason = p.suspendReasons[len(p.suspendReasons)-1]
+	}
+	p.suspensionReason.Store(reason)
+}
+
+// faultRecreate replaces the failed instance with a new one from the props,
+// the mailbox is untouched so the pending messages keep their order, with
+// restart-resends-failed-message the failed message is processed again first
+func (p *ActorCell) faultRecreate(cause error, failed *akka.Envelope) {
+	if p.IsTerminated() {
+		return
+	}
+
+	awaitingSupervisor := p.awaitingSupervisor
+	p.awaitingSupervisor = false
+	p.failureReason = ""
+	p.updateSuspensionReason()
+
+	p.cancelTimers()
+
+	var failedMessage interface{}
+	if failed != nil {
+		failedMessage = failed.Message
+	}
+
+	if p.actor != nil {
+		if err := p.actor.AroundPreReStart(cause, failedMessage); err != nil {
+			p.publish(event.NewErrorEvent(err, p.self.Path().String(), p.actor, "error while executing PreRestart"))
+		}
+	}
+
+	p.removeStoppingChildren()
+
+	failedActor := p.actor
+
+	actor, err := p.newActor()
+	if err != nil {
+		p.failInitialization(err)
+		p.resumeAfterFailure(awaitingSupervisor)
+		return
+	}
+
+	p.releaseActor(failedActor)
+
+	if err = actor.AroundPostRestart(cause, failedMessage); err != nil {
+		p.failInitialization(err)
+		p.resumeAfterFailure(awaitingSupervisor)
+		return
+	}
+
+	if p.system.settings.DebugLifecycle {
+		p.publish(event.NewDebugEvent(p.self.Path().String(), actor, "restarted ("+actor.Self().String()+")"))
+	}
+
+	if failed != nil && failed.Message != nil && p.system.settings.RestartResendsFailedMessage {
+		p.Invoke(*failed)
+	}
+
+	p.resumeAfterFailure(awaitingSupervisor)
+}
+
+func (p *ActorCell) resumeAfterFailure(awaitingSupervisor bool) {
+	if awaitingSupervisor {
+		p.mailbox.Resume()
+	}
+}
+
+func (p *ActorCell) handleFailure(failed *sysmsg.Failed) {
+	stats, exist := p.ChildrenRefs().GetByRef(failed.Child)
+	if !exist {
+		return
+	}
+
+	if recorder, ok := stats.(childStatsRecorder); ok {
+		recorder.RecordFailure(failed.Cause, failed.Envelope)
+	}
+
+	strategy := p.supervisorStrategy()
+
+	// a guardian ends the escalation chain, it stops the failing branch
+	if guardian, ok := strategy.(*guardianSupervisorStrategy); ok && failed.Escalated {
+		guardian.HandleEscalatedFailure(p, failed.Child, failed.Cause)
+		return
+	}
+
+	if !strategy.HandleFailure(p, failed.Child, failed.Cause, stats, p.ChildrenRefs().Stats()) {
+		p.escalate(failed.Cause)
+	}
 }
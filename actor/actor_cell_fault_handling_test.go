@@ -0,0 +1,1004 @@
+package actor
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/event"
+	"github.com/go-akka/configuration"
+)
+
+type restartProbe struct {
+	received   chan interface{}
+	preRestart chan interface{}
+	failures   int32
+}
+
+type RestartingActor struct {
+	*UntypedActor
+
+	probe *restartProbe
+}
+
+func (p *RestartingActor) RestartingActor(probe *restartProbe) {
+	p.probe = probe
+}
+
+func (p *RestartingActor) PreRestart(cause error, message interface{}) (err error) {
+	p.probe.preRestart <- message
+	return
+}
+
+func (p *RestartingActor) Receive(message interface{}) (handled bool, err error) {
+	if message == "boom" && atomic.AddInt32(&p.probe.failures, 1) == 1 {
+		return true, errors.New("boom")
+	}
+
+	p.probe.received <- message
+	return true, nil
+}
+
+// RestartSupervisorActor restarts its failing child with the default strategy
+// and forwards every message to it
+type RestartSupervisorActor struct {
+	*UntypedActor
+
+	probe *restartProbe
+	child akka.ActorRef
+}
+
+func (p *RestartSupervisorActor) RestartSupervisorActor(probe *restartProbe) {
+	p.probe = probe
+}
+
+func (p *RestartSupervisorActor) PreStart() (err error) {
+	var childProps akka.Props
+	if childProps, err = props.Create((*RestartingActor)(nil), p.probe); err != nil {
+		return
+	}
+
+	p.child, err = p.Context().ActorOf(childProps, "restarting")
+	return
+}
+
+func (p *RestartSupervisorActor) SupervisorStrategy() akka.SupervisorStrategy {
+	return DefaultSupervisorStrategy
+}
+
+func (p *RestartSupervisorActor) Receive(message interface{}) (handled bool, err error) {
+	p.child.Tell(message, p.Sender())
+	return true, nil
+}
+
+func runRestartScenario(t *testing.T, name string, resend bool) (received []interface{}, failedMessage interface{}) {
+	config := testConfig
+	if resend {
+		config = strings.Replace(config, "actor {", "actor {\n\t\trestart-resends-failed-message = on", 1)
+	}
+
+	system, err := NewActorSystem(name, configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	probe := &restartProbe{
+		received:   make(chan interface{}, 10),
+		preRestart: make(chan interface{}, 1),
+	}
+
+	supervisorProps, err := props.Create((*RestartSupervisorActor)(nil), probe)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(supervisorProps, "supervisor")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	for _, msg := range []interface{}{1, 2, "boom", 3, 4, 5} {
+		ref.Tell(msg)
+	}
+
+	expected := 5
+	if resend {
+		expected = 6
+	}
+
+	for len(received) < expected {
+		select {
+		case msg := <-probe.received:
+			received = append(received, msg)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("expected %d messages, but only received %v", expected, received)
+		}
+	}
+
+	select {
+	case failedMessage = <-probe.preRestart:
+	case <-time.After(time.Second):
+		t.Fatalf("PreRestart was not called")
+	}
+
+	return
+}
+
+func TestRestartPreservesMailboxOrder(t *testing.T) {
+	received, failedMessage := runRestartScenario(t, "RestartPreservesOrder", false)
+
+	if failedMessage != "boom" {
+		t.Fatalf("PreRestart should see the failed message, but got %v", failedMessage)
+	}
+
+	if expected := []interface{}{1, 2, 3, 4, 5}; !reflect.DeepEqual(received, expected) {
+		t.Fatalf("mailbox order should survive the restart, expected %v, but got %v", expected, received)
+	}
+}
+
+func TestRestartResendsFailedMessage(t *testing.T) {
+	received, failedMessage := runRestartScenario(t, "RestartResendsFailed", true)
+
+	if failedMessage != "boom" {
+		t.Fatalf("PreRestart should see the failed message, but got %v", failedMessage)
+	}
+
+	if expected := []interface{}{1, 2, "boom", 3, 4, 5}; !reflect.DeepEqual(received, expected) {
+		t.Fatalf("failed message should be reprocessed before the rest of the mailbox, expected %v, but got %v", expected, received)
+	}
+}
+
+func TestRestartHandsItsEnvelopeToPreRestart(t *testing.T) {
+	system := newTestActorSystem(t, "RestartHandsEnvelope")
+
+	probe := &restartProbe{
+		received:   make(chan interface{}, 10),
+		preRestart: make(chan interface{}, 1),
+	}
+
+	restartingProps, err := props.Create((*RestartingActor)(nil), probe)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(restartingProps, "restarting")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	ref.(akka.InternalActorRef).Restart(errors.New("restart"), &akka.Envelope{Message: "given"})
+
+	select {
+	case message := <-probe.preRestart:
+		if message != "given" {
+			t.Fatalf("PreRestart should see the envelope passed to Restart, but got %v", message)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("PreRestart was not called")
+	}
+}
+
+// newRestartingGuardianSystem creates a system whose top-level actors are
+// restarted when they fail
+func newRestartingGuardianSystem(t *testing.T, name string) *ActorSystemImpl {
+	config := strings.Replace(testConfig, "actor {", `actor {
+		guardian-supervisor-strategy = "akka.actor.DefaultSupervisorStrategy"`, 1)
+
+	system, err := NewActorSystem(name, configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	return system
+}
+
+func TestGuardianStopsFailingTopLevelActorsByDefault(t *testing.T) {
+	for _, restarting := range []bool{false, true} {
+		var system *ActorSystemImpl
+		if restarting {
+			system = newRestartingGuardianSystem(t, "GuardianRestarts")
+		} else {
+			system = newTestActorSystem(t, "GuardianStops")
+		}
+
+		probe := &restartProbe{received: make(chan interface{}, 10), preRestart: make(chan interface{}, 1)}
+		restartingProps, err := props.Create((*RestartingActor)(nil), probe)
+		if err != nil {
+			t.Fatalf("create props failure: %s", err.Error())
+		}
+
+		ref, err := system.ActorOf(restartingProps, "top-level")
+		if err != nil {
+			t.Fatalf("create actor failure: %s", err.Error())
+		}
+
+		inbox := NewInbox(system)
+		inbox.Watch(ref)
+
+		ref.Tell("boom")
+
+		if restarting {
+			select {
+			case <-probe.preRestart:
+			case <-time.After(3 * time.Second):
+				t.Fatalf("the failing top-level actor should be restarted")
+			}
+
+			ref.Tell("after")
+			select {
+			case msg := <-probe.received:
+				if msg != "after" {
+					t.Fatalf("the restarted actor should process the next message, but got %v", msg)
+				}
+			case <-time.After(3 * time.Second):
+				t.Fatalf("the restarted actor did not process the next message")
+			}
+		} else {
+			msg, err := inbox.Receive(3 * time.Second)
+			if term, ok := msg.(*Terminated); err != nil || !ok || term.Actor.CompareTo(ref) != 0 {
+				t.Fatalf("the failing top-level actor should be stopped, but got %v, %v", msg, err)
+			}
+
+			if len(probe.preRestart) > 0 {
+				t.Fatalf("the stopped actor should not be restarted")
+			}
+		}
+
+		inbox.Stop()
+	}
+
+	config := strings.Replace(testConfig, "actor {", `actor {
+		guardian-supervisor-strategy = "akka.event.default-logger"`, 1)
+
+	if _, err := NewActorSystem("GuardianBadStrategy", configuration.ParseString(config)); err == nil || !strings.HasPrefix(err.Error(), akka.ErrBadTypeOfSupervisorConfigurator.Error()) {
+		t.Fatalf("a class that is no configurator should fail the system start, but got %v", err)
+	}
+}
+
+func TestRestartedTopLevelActorIsNotAnUncaughtFailure(t *testing.T) {
+	system := newRestartingGuardianSystem(t, "GuardianRestartsQuietly")
+
+	failures := make(chan akka.ActorPath, 1)
+	system.OnUncaughtFailure(func(err error, path akka.ActorPath) {
+		select {
+		case failures <- path:
+		default:
+		}
+	})
+
+	probe := &restartProbe{received: make(chan interface{}, 10), preRestart: make(chan interface{}, 1)}
+	restartingProps, err := props.Create((*RestartingActor)(nil), probe)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(restartingProps, "top-level")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	ref.Tell("boom")
+
+	select {
+	case <-probe.preRestart:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("the failing top-level actor should be restarted")
+	}
+
+	ref.Tell("after")
+	select {
+	case <-probe.received:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("the restarted actor did not process the next message")
+	}
+
+	select {
+	case path := <-failures:
+		t.Fatalf("a restarted top-level actor should not be reported as uncaught, but got %s", path)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// ParentingActor creates and watches its child "kid" unless it still has it
+type ParentingActor struct {
+	*UntypedActor
+
+	preserve   bool
+	terminated chan akka.ActorRef
+}
+
+func (p *ParentingActor) ParentingActor(preserve bool, terminated chan akka.ActorRef) {
+	p.preserve = preserve
+	p.terminated = terminated
+}
+
+func (p *ParentingActor) PreserveChildrenOnRestart() bool {
+	return p.preserve
+}
+
+func (p *ParentingActor) PreStart() (err error) {
+	if _, exist := p.Context().Child("kid"); exist {
+		return
+	}
+
+	var kidProps akka.Props
+	if kidProps, err = props.Create((*echoActor)(nil)); err != nil {
+		return
+	}
+
+	var kid akka.ActorRef
+	if kid, err = p.Context().ActorOf(kidProps, "kid"); err != nil {
+		return
+	}
+
+	p.Context().Watch(kid)
+	return
+}
+
+func (p *ParentingActor) Receive(message interface{}) (handled bool, err error) {
+	switch msg := message.(type) {
+	case *Terminated:
+		{
+			p.terminated <- msg.Actor
+		}
+	case string:
+		{
+			if msg == "fail" {
+				return true, errors.New("fail")
+			}
+			kid, _ := p.Context().Child("kid")
+			p.Sender().Tell(kid, p.Self())
+		}
+	}
+	return true, nil
+}
+
+func restartParent(t *testing.T, name string, preserve bool) (before, after akka.ActorRef, terminated chan akka.ActorRef, inbox *Inbox) {
+	system := newRestartingGuardianSystem(t, name)
+
+	terminated = make(chan akka.ActorRef, 1)
+	parentProps, err := props.Create((*ParentingActor)(nil), preserve, terminated)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	parent, err := system.ActorOf(parentProps, "parent")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	inbox = NewInbox(system)
+
+	kid := func() akka.ActorRef {
+		inbox.Send(parent, "kid")
+		msg, err := inbox.Receive(3 * time.Second)
+		if err != nil {
+			t.Fatalf("receive failure: %s", err.Error())
+		}
+		return msg.(akka.ActorRef)
+	}
+
+	before = kid()
+	inbox.Watch(before)
+	inbox.Send(parent, "fail")
+	after = kid()
+
+	return
+}
+
+func TestRestartStopsChildrenByDefault(t *testing.T) {
+	before, after, terminated, inbox := restartParent(t, "RestartStopsChildren", false)
+	defer inbox.Stop()
+
+	if before.CompareTo(after) == 0 {
+		t.Fatalf("the restarted parent should have created a new child, but kept %s", before)
+	}
+
+	msg, err := inbox.Receive(3 * time.Second)
+	if term, ok := msg.(*Terminated); err != nil || !ok || term.Actor.CompareTo(before) != 0 {
+		t.Fatalf("the old child should be stopped, but got %v, %v", msg, err)
+	}
+
+	select {
+	case ref := <-terminated:
+		t.Fatalf("the parent unwatched the stopped child, but was told %s terminated", ref)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestRestartPreservesChildrenWhenOptedIn(t *testing.T) {
+	before, after, terminated, inbox := restartParent(t, "RestartPreservesChildren", true)
+	defer inbox.Stop()
+
+	if before.CompareTo(after) != 0 {
+		t.Fatalf("the restarted parent should keep its child %s, but got %s", before, after)
+	}
+
+	inbox.Send(after, "still-there")
+	if msg, err := inbox.Receive(3 * time.Second); err != nil || msg != "still-there" {
+		t.Fatalf("the preserved child should still reply, but got %v, %v", msg, err)
+	}
+
+	after.Tell(&PoisonPill{})
+
+	select {
+	case ref := <-terminated:
+		if ref.CompareTo(after) != 0 {
+			t.Fatalf("the parent should be told its child terminated, but got %s", ref)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("the watch on the preserved child did not survive the restart")
+	}
+}
+
+type RecordingActor struct {
+	*UntypedActor
+
+	received chan interface{}
+}
+
+func (p *RecordingActor) RecordingActor(received chan interface{}) {
+	p.received = received
+}
+
+func (p *RecordingActor) Receive(message interface{}) (handled bool, err error) {
+	p.received <- message
+	return true, nil
+}
+
+func TestSuspendedActorHoldsMessagesUntilResume(t *testing.T) {
+	system := newTestActorSystem(t, "SuspendHoldsMessages")
+
+	received := make(chan interface{}, 10)
+
+	recordingProps, err := props.Create((*RecordingActor)(nil), received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(recordingProps, "recording")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	cell := ref.(*LocalActorRef).Cell()
+	cell.Suspend()
+
+	for i := 0; i < 5; i++ {
+		ref.Tell(i)
+	}
+
+	select {
+	case msg := <-received:
+		t.Fatalf("suspended actor should not process %v", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if n := cell.Mailbox().NumberOfMessages(); n != 5 {
+		t.Fatalf("mailbox should hold 5 messages while suspended, but holds %d", n)
+	}
+
+	cell.Resume(nil)
+
+	for i := 0; i < 5; i++ {
+		select {
+		case msg := <-received:
+			if msg != i {
+				t.Fatalf("message %d should be delivered in arrival order, but got %v", i, msg)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("message %d was not delivered after resume", i)
+		}
+	}
+}
+
+func TestNestedSuspendsNeedMatchingResumes(t *testing.T) {
+	system := newTestActorSystem(t, "NestedSuspends")
+
+	received := make(chan interface{}, 10)
+
+	recordingProps, err := props.Create((*RecordingActor)(nil), received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(recordingProps, "recording")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	cell := ref.(*LocalActorRef).Cell()
+
+	// an unmatched resume must not cancel one of the suspends below
+	cell.Resume(nil)
+	cell.Suspend()
+	cell.Suspend()
+	cell.Resume(nil)
+
+	ref.Tell("held")
+
+	select {
+	case msg := <-received:
+		t.Fatalf("actor should stay suspended until the second resume, but processed %v", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cell.Resume(nil)
+
+	select {
+	case msg := <-received:
+		if msg != "held" {
+			t.Fatalf("held message should be delivered after the second resume, but got %v", msg)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("message was not delivered after the second resume")
+	}
+}
+
+func TestChildStatsCountRestarts(t *testing.T) {
+	system := newTestActorSystem(t, "ChildStatsRestarts")
+
+	probe := &restartProbe{
+		received:   make(chan interface{}, 10),
+		preRestart: make(chan interface{}, 1),
+	}
+
+	supervisorProps, err := props.Create((*RestartSupervisorActor)(nil), probe)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(supervisorProps, "supervisor")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	ref.Tell(1)
+	select {
+	case <-probe.received:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("child did not start")
+	}
+
+	childStats, exist := ref.(*LocalActorRef).Cell().GetChildByName("restarting")
+	if !exist {
+		t.Fatalf("stats of the child should be found by its name")
+	}
+
+	stats, ok := childStats.(akka.ChildRestartStats)
+	if !ok {
+		t.Fatalf("child stats should be ChildRestartStats, but got %T", childStats)
+	}
+
+	if stats.RestartCount() != 0 || stats.State() != akka.ChildRunning {
+		t.Fatalf("fresh child should be running without restarts, but got %d restarts in state %s", stats.RestartCount(), stats.State())
+	}
+
+	ref.Tell("boom")
+	ref.Tell(2)
+
+	select {
+	case <-probe.received:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("child did not process a message after its restart")
+	}
+
+	if count := stats.RestartCount(); count != 1 {
+		t.Fatalf("child should be restarted once, but restart count is %d", count)
+	}
+
+	cause, failedAt := stats.LastFailure()
+	if cause == nil || cause.Error() != "boom" || failedAt.IsZero() {
+		t.Fatalf("last failure should be boom with its time, but got %v at %s", cause, failedAt)
+	}
+
+	if stats.State() != akka.ChildRunning {
+		t.Fatalf("restarted child should be running, but is %s", stats.State())
+	}
+
+	if failed := stats.FailedEnvelope(); failed != nil {
+		t.Fatalf("restarted child should not keep the message it failed on, but got %#v", failed)
+	}
+
+	if stats.CreatedAt().After(failedAt) || stats.Uptime() > time.Since(failedAt) {
+		t.Fatalf("uptime should be counted from the restart, created at %s, failed at %s, uptime %s", stats.CreatedAt(), failedAt, stats.Uptime())
+	}
+}
+
+type PanickingActor struct {
+	*UntypedActor
+}
+
+func (p *PanickingActor) Receive(message interface{}) (handled bool, err error) {
+	panic(fmt.Sprintf("panic on %v", message))
+}
+
+func TestPanicInHandlerKeepsDispatcherServing(t *testing.T) {
+	system := newRestartingGuardianSystem(t, "PanicRecovery")
+
+	collector := &errorCollector{
+		MinimalActorRef: akka.NewMinimalActorRef(akka.NewRootActorPath(akka.NewAddress("akka", "test", "", 0), "/collector"), nil),
+		errors:          make(chan *event.Error, 10),
+	}
+	system.EventStream().Subscribe(collector, reflect.TypeOf((*event.Error)(nil)).Elem())
+
+	panickingProps, err := props.Create((*PanickingActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	panicking, err := system.ActorOf(panickingProps, "panicking")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	received := make(chan interface{}, 10)
+	recordingProps, err := props.Create((*RecordingActor)(nil), received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	recording, err := system.ActorOf(recordingProps, "recording")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	panicking.Tell("boom")
+
+	select {
+	case e := <-collector.errors:
+		{
+			panicErr, ok := e.Cause().(*ActorPanicError)
+			if !ok {
+				t.Fatalf("cause should be an ActorPanicError, but got %T: %v", e.Cause(), e.Cause())
+			}
+
+			if panicErr.Value != "panic on boom" || len(panicErr.Stack) == 0 {
+				t.Fatalf("panic error should carry the recovered value and stack, but got %v", panicErr.Value)
+			}
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("panic was not published as an error event")
+	}
+
+	for i := 0; i < 3; i++ {
+		recording.Tell(i)
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case msg := <-received:
+			if msg != i {
+				t.Fatalf("expected message %d, but got %v", i, msg)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("dispatcher stopped serving other actors after a panic")
+		}
+	}
+
+	// the user guardian restarts failing children, so the failure reached it
+	childStats, exist := system.Guardian().(*LocalActorRef).Cell().GetChildByName("panicking")
+	if !exist {
+		t.Fatalf("panicking actor should be a child of the user guardian")
+	}
+
+	stats := childStats.(akka.ChildRestartStats)
+	for deadline := time.Now().Add(3 * time.Second); stats.RestartCount() == 0 && time.Now().Before(deadline); {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if stats.RestartCount() == 0 {
+		t.Fatalf("panicking actor should be restarted by its supervisor")
+	}
+}
+
+// EscalatingChainActor creates a chain of depth descendants, each escalating
+// the failures of its child, the leaf fails on every message
+type EscalatingChainActor struct {
+	*UntypedActor
+
+	depth int
+	refs  chan akka.ActorRef
+}
+
+func (p *EscalatingChainActor) EscalatingChainActor(depth int, refs chan akka.ActorRef) {
+	p.depth = depth
+	p.refs = refs
+}
+
+func (p *EscalatingChainActor) PreStart() (err error) {
+	p.refs <- p.Self()
+
+	if p.depth == 0 {
+		return
+	}
+
+	var childProps akka.Props
+	if childProps, err = props.Create((*EscalatingChainActor)(nil), p.depth-1, p.refs); err != nil {
+		return
+	}
+
+	_, err = p.Context().ActorOf(childProps, fmt.Sprintf("level-%d", p.depth-1))
+	return
+}
+
+func (p *EscalatingChainActor) Receive(message interface{}) (handled bool, err error) {
+	return true, errors.New("leaf failure")
+}
+
+func (p *EscalatingChainActor) SupervisorStrategy() akka.SupervisorStrategy {
+	return NewOneForOneStrategy(-1, 0, func(cause error) akka.Directive {
+		return akka.EscalateDirective
+	})
+}
+
+func TestEscalationToTheGuardianStopsTheFailingBranch(t *testing.T) {
+	system := newTestActorSystem(t, "EscalationChain")
+
+	failures := make(chan akka.ActorPath, 1)
+	system.OnUncaughtFailure(func(err error, path akka.ActorPath) {
+		select {
+		case failures <- path:
+		default:
+		}
+	})
+
+	refs := make(chan akka.ActorRef, 3)
+
+	chainProps, err := props.Create((*EscalatingChainActor)(nil), 2, refs)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	if _, err = system.ActorOf(chainProps, "top"); err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	siblingProps, err := props.Create((*echoActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	sibling, err := system.ActorOf(siblingProps, "sibling")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	inbox := NewInbox(system)
+	defer inbox.Stop()
+
+	var chain []akka.ActorRef
+	for i := 0; i < 3; i++ {
+		select {
+		case ref := <-refs:
+			chain = append(chain, ref)
+			if err = inbox.Watch(ref); err != nil {
+				t.Fatalf("watch failure: %s", err.Error())
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("chain level %d was not started", i)
+		}
+	}
+
+	chain[2].Tell("fail")
+
+	select {
+	case path := <-failures:
+		if path.String() != chain[0].Path().String() {
+			t.Fatalf("escalation should end at the guardian with the top of the branch, but got %s", path)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("escalated failure did not reach the guardian")
+	}
+
+	stopped := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		message, err := inbox.Receive(3 * time.Second)
+		if err != nil {
+			t.Fatalf("branch should be stopped, but only %v were: %s", stopped, err.Error())
+		}
+		stopped[message.(*Terminated).Actor.Path().String()] = true
+	}
+
+	for _, ref := range chain {
+		if !stopped[ref.Path().String()] {
+			t.Fatalf("%s should be stopped with its branch", ref.Path())
+		}
+	}
+
+	inbox.Send(sibling, "still alive")
+	if reply, err := inbox.Receive(3 * time.Second); err != nil || reply != "still alive" {
+		t.Fatalf("the other branches should keep running, but got %v, %v", reply, err)
+	}
+}
+
+func TestDumpMailboxShowsQueuedMessagesOfSuspendedActor(t *testing.T) {
+	config := strings.Replace(testConfig, "actor {", "actor {\n\t\tdebug.mailbox-dump = on", 1)
+
+	system, err := NewActorSystem("DumpMailbox", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	received := make(chan interface{}, 10)
+
+	recordingProps, err := props.Create((*RecordingActor)(nil), received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(recordingProps, "recording")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	cell := ref.(*LocalActorRef).Cell()
+	cell.Suspend()
+
+	ref.Tell("a")
+	ref.Tell(1)
+	ref.Tell("b")
+
+	dump := system.DumpMailbox(ref)
+	if len(dump) != 3 || dump[0] != "a" || dump[1] != 1 || dump[2] != "b" {
+		t.Fatalf("dump should list the queued messages in order, but got %v", dump)
+	}
+
+	if n := cell.Mailbox().NumberOfMessages(); n != 3 {
+		t.Fatalf("dump should not consume the messages, but the mailbox holds %d", n)
+	}
+
+	cell.Resume(nil)
+
+	for _, want := range []interface{}{"a", 1, "b"} {
+		select {
+		case msg := <-received:
+			if msg != want {
+				t.Fatalf("message %v should be delivered after the dump, but got %v", want, msg)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("message %v was not delivered after resume", want)
+		}
+	}
+
+	if dump := newTestActorSystem(t, "NoDumpMailbox").DumpMailbox(ref); dump != nil {
+		t.Fatalf("dump should be disabled by default, but got %v", dump)
+	}
+}
+
+// DecidingSupervisorActor holds the decision on a failure of its child until
+// the test releases one
+type DecidingSupervisorActor struct {
+	*UntypedActor
+
+	probe    *restartProbe
+	deciding chan error
+	decision chan akka.Directive
+	child    akka.ActorRef
+}
+
+func (p *DecidingSupervisorActor) DecidingSupervisorActor(probe *restartProbe, deciding chan error, decision chan akka.Directive) {
+	p.probe = probe
+	p.deciding = deciding
+	p.decision = decision
+}
+
+func (p *DecidingSupervisorActor) PreStart() (err error) {
+	var childProps akka.Props
+	if childProps, err = props.Create((*RestartingActor)(nil), p.probe); err != nil {
+		return
+	}
+
+	p.child, err = p.Context().ActorOf(childProps, "deciding")
+	return
+}
+
+func (p *DecidingSupervisorActor) SupervisorStrategy() akka.SupervisorStrategy {
+	return NewOneForOneStrategy(-1, 0, func(cause error) akka.Directive {
+		p.deciding <- cause
+		return <-p.decision
+	})
+}
+
+func (p *DecidingSupervisorActor) Receive(message interface{}) (handled bool, err error) {
+	p.child.Tell(message, p.Sender())
+	return true, nil
+}
+
+func TestSuspensionReasonShowsTheFailureAwaitingADecision(t *testing.T) {
+	system := newTestActorSystem(t, "SuspensionReason")
+
+	probe := &restartProbe{
+		received:   make(chan interface{}, 10),
+		preRestart: make(chan interface{}, 1),
+	}
+	deciding := make(chan error, 1)
+	decision := make(chan akka.Directive, 1)
+
+	supervisorProps, err := props.Create((*DecidingSupervisorActor)(nil), probe, deciding, decision)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(supervisorProps, "supervisor")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	ref.Tell(1)
+	select {
+	case <-probe.received:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("child did not start")
+	}
+
+	childStats, _ := ref.(*LocalActorRef).Cell().GetChildByName("deciding")
+	stats := childStats.(akka.ChildRestartStats)
+
+	if reason := stats.SuspensionReason(); reason != "" {
+		t.Fatalf("running child should have no suspension reason, but got %q", reason)
+	}
+
+	ref.Tell("boom")
+
+	select {
+	case <-deciding:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("supervisor was not asked to decide")
+	}
+
+	if reason := stats.SuspensionReason(); !strings.HasPrefix(reason, SuspendedAwaitingSupervisor) || !strings.Contains(reason, "boom") {
+		t.Fatalf("failed child should await the supervisor on boom, but got %q", reason)
+	}
+
+	decision <- akka.ResumeDirective
+
+	ref.Tell(2)
+	select {
+	case <-probe.received:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("child did not process a message after its resume")
+	}
+
+	if reason := stats.SuspensionReason(); reason != "" {
+		t.Fatalf("resumed child should have no suspension reason, but got %q", reason)
+	}
+
+	cell := stats.Child().(*LocalActorRef).Cell()
+	cell.SuspendWithReason("throttled")
+	cell.Suspend()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for cell.SuspensionReason() != SuspendedBySupervisor {
+		if time.Now().After(deadline) {
+			t.Fatalf("latest suspend should give the reason, but got %q", cell.SuspensionReason())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cell.Resume(nil)
+
+	deadline = time.Now().Add(3 * time.Second)
+	for cell.SuspensionReason() != "throttled" {
+		if time.Now().After(deadline) {
+			t.Fatalf("resume should go back to the earlier reason, but got %q", cell.SuspensionReason())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cell.Resume(nil)
+
+	deadline = time.Now().Add(3 * time.Second)
+	for cell.SuspensionReason() != "" {
+		if time.Now().After(deadline) {
+			t.Fatalf("last resume should clear the reason, but got %q", cell.SuspensionReason())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,70 @@
+package actor
+
+import (
+	"time"
+
+	"github.com/go-akka/akka"
+)
+
+// scheduledSelfMessage carries a message scheduled with Context().Schedule, it
+// is dropped on arrival if its timer was cancelled after it had been enqueued
+type scheduledSelfMessage struct {
+	cancelable *Cancelable
+	message    interface{}
+}
+
+// Schedule sends message to self after delay. Unlike the system scheduler the
+// send belongs to the current incarnation, it is cancelled once the actor is
+// stopped or restarted
+func (p *ActorCell) Schedule(delay time.Duration, message interface{}) akka.Cancelable {
+	cancelable := NewCancelable()
+	p.timers[cancelable] = true
+
+	p.System().Scheduler().ScheduleTellOnce(delay, p.self, &scheduledSelfMessage{cancelable: cancelable, message: message}, p.self, cancelable)
+
+	return cancelable
+}
+
+// takeScheduled reports whether the scheduled message is still to be received
+func (p *ActorCell) takeScheduled(scheduled *scheduledSelfMessage) bool {
+	delete(p.timers, scheduled.cancelable)
+	return !scheduled.cancelable.IsCancellationRequested()
+}
+
+func (p *ActorCell) cancelTimers() {
+	for cancelable := range p.timers {
+		cancelable.Cancel(false)
+	}
+	p.timers = make(map[*Cancelable]bool)
+	p.receiveTimeoutTask = nil
+}
+
+// ReceiveTimeout is received by an actor with a receive timeout that got no
+// other message within it, it is received again after every further timeout
+type ReceiveTimeout struct{}
+
+func (p *ReceiveTimeout) String() string {
+	return "<ReceiveTimeout>"
+}
+
+func (p *ActorCell) ReceiveTimeout() (timeout time.Duration) {
+	return p.receiveTimeout
+}
+
+// SetReceiveTimeout starts the receive timeout over, zero disables it
+func (p *ActorCell) SetReceiveTimeout(timeout time.Duration) {
+	p.receiveTimeout = timeout
+	p.rescheduleReceiveTimeout()
+}
+
+func (p *ActorCell) rescheduleReceiveTimeout() {
+	if p.receiveTimeoutTask != nil {
+		p.receiveTimeoutTask.Cancel(false)
+		delete(p.timers, p.receiveTimeoutTask)
+		p.receiveTimeoutTask = nil
+	}
+
+	if p.receiveTimeout > 0 {
+		p.receiveTimeoutTask = p.Schedule(p.receiveTimeout, &ReceiveTimeout{}).(*Cancelable)
+	}
+}
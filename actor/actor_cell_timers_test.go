@@ -0,0 +1,187 @@
+package actor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+)
+
+type SchedulingActor struct {
+	*UntypedActor
+
+	scheduled chan akka.Cancelable
+	received  chan interface{}
+}
+
+func (p *SchedulingActor) SchedulingActor(scheduled chan akka.Cancelable, received chan interface{}) {
+	p.scheduled = scheduled
+	p.received = received
+}
+
+func (p *SchedulingActor) Receive(message interface{}) (handled bool, err error) {
+	if delay, ok := message.(time.Duration); ok {
+		p.scheduled <- p.Context().Schedule(delay, "tick")
+		return true, nil
+	}
+
+	p.received <- message
+	return true, nil
+}
+
+func newSchedulingActor(t *testing.T, system akka.ActorSystem, name string) (ref akka.ActorRef, scheduled chan akka.Cancelable, received chan interface{}) {
+	scheduled = make(chan akka.Cancelable, 1)
+	received = make(chan interface{}, 1)
+
+	actorProps, err := props.Create((*SchedulingActor)(nil), scheduled, received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	if ref, err = system.ActorOf(actorProps, name); err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	return
+}
+
+func TestContextScheduleDeliversToSelf(t *testing.T) {
+	system := newTestActorSystem(t, "ContextSchedule")
+
+	ref, _, received := newSchedulingActor(t, system, "scheduler")
+	ref.Tell(20 * time.Millisecond)
+
+	select {
+	case msg := <-received:
+		if msg != "tick" {
+			t.Fatalf("expected the scheduled tick, but got %v", msg)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("scheduled message was not delivered")
+	}
+}
+
+func TestContextScheduleCancelledWhenActorStops(t *testing.T) {
+	system := newTestActorSystem(t, "ContextScheduleStop")
+
+	ref, scheduled, received := newSchedulingActor(t, system, "scheduler")
+	ref.Tell(200 * time.Millisecond)
+
+	var cancelable akka.Cancelable
+	select {
+	case cancelable = <-scheduled:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("message was not scheduled")
+	}
+
+	ref.Tell(&PoisonPill{})
+
+	for deadline := time.Now().Add(time.Second); !cancelable.IsCancellationRequested() && time.Now().Before(deadline); {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if !cancelable.IsCancellationRequested() {
+		t.Fatalf("scheduled message should be cancelled once the actor stopped")
+	}
+
+	select {
+	case msg := <-received:
+		t.Fatalf("stopped actor should never receive the scheduled message, but got %v", msg)
+	case <-time.After(400 * time.Millisecond):
+	}
+}
+
+type IdleActor struct {
+	*UntypedActor
+
+	timeout  time.Duration
+	timeouts chan time.Time
+}
+
+func (p *IdleActor) IdleActor(timeout time.Duration, timeouts chan time.Time) {
+	p.timeout = timeout
+	p.timeouts = timeouts
+}
+
+func (p *IdleActor) PreStart() (err error) {
+	p.SetReceiveTimeout(p.timeout)
+	return
+}
+
+func (p *IdleActor) Receive(message interface{}) (handled bool, err error) {
+	switch message.(type) {
+	case *ReceiveTimeout:
+		{
+			p.timeouts <- time.Now()
+		}
+	case string:
+		{
+			if message == "disable" {
+				p.SetReceiveTimeout(0)
+			}
+		}
+	}
+	return true, nil
+}
+
+func newIdleActor(t *testing.T, system akka.ActorSystem, name string, timeout time.Duration) (ref akka.ActorRef, timeouts chan time.Time) {
+	timeouts = make(chan time.Time, 10)
+
+	actorProps, err := props.Create((*IdleActor)(nil), timeout, timeouts)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	if ref, err = system.ActorOf(actorProps, name); err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	return
+}
+
+func TestReceiveTimeoutIsReceivedOnlyWhenIdle(t *testing.T) {
+	system := newTestActorSystem(t, "ReceiveTimeout")
+
+	created := time.Now()
+	quiet, quietTimeouts := newIdleActor(t, system, "quiet", 100*time.Millisecond)
+	active, activeTimeouts := newIdleActor(t, system, "active", 200*time.Millisecond)
+
+	select {
+	case at := <-quietTimeouts:
+		if at.Sub(created) < 100*time.Millisecond {
+			t.Fatalf("receive timeout should not fire before the timeout, but fired after %s", at.Sub(created))
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("quiet actor did not receive ReceiveTimeout")
+	}
+
+	for i := 0; i < 12; i++ {
+		active.Tell("busy")
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	active.Tell(&PoisonPill{})
+
+	select {
+	case <-activeTimeouts:
+		t.Fatalf("active actor should never receive ReceiveTimeout")
+	default:
+	}
+
+	quiet.Tell("disable")
+	time.Sleep(20 * time.Millisecond)
+	for len(quietTimeouts) > 0 {
+		<-quietTimeouts
+	}
+
+	select {
+	case <-quietTimeouts:
+		t.Fatalf("zero receive timeout should disable it")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	if len(activeTimeouts) > 0 {
+		t.Fatalf("receive timeout should be cancelled when the actor stops")
+	}
+}
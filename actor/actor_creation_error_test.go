@@ -0,0 +1,201 @@
+package actor
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/event"
+)
+
+var errFailingInit = errors.New("failing init")
+
+type FailingInitActor struct {
+	*UntypedActor
+}
+
+func (p *FailingInitActor) FailingInitActor(fail bool) error {
+	if fail {
+		return errFailingInit
+	}
+	return nil
+}
+
+func (p *FailingInitActor) Receive(message interface{}) (handled bool, err error) {
+	return true, nil
+}
+
+type errorCollector struct {
+	*akka.MinimalActorRef
+
+	errors chan *event.Error
+}
+
+func (p *errorCollector) Tell(message interface{}, sender ...akka.ActorRef) (err error) {
+	if e, ok := message.(*event.Error); ok {
+		p.errors <- e
+	}
+	return
+}
+
+func TestActorCreationFailurePublishesTypedError(t *testing.T) {
+	system := newTestActorSystem(t, "ActorCreationError")
+
+	collector := &errorCollector{
+		MinimalActorRef: akka.NewMinimalActorRef(akka.NewRootActorPath(akka.NewAddress("akka", "test", "", 0), "/collector"), nil),
+		errors:          make(chan *event.Error, 10),
+	}
+	system.EventStream().Subscribe(collector, reflect.TypeOf((*event.Error)(nil)).Elem())
+
+	failingProps, err := props.Create((*FailingInitActor)(nil), true)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(failingProps, "failing")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	select {
+	case e := <-collector.errors:
+		{
+			creationErr, ok := e.Cause().(*ActorCreationError)
+			if !ok {
+				t.Fatalf("cause should be an ActorCreationError, but got %T: %v", e.Cause(), e.Cause())
+			}
+
+			if creationErr.Path.String() != ref.Path().String() {
+				t.Fatalf("error path should be %s, but got %s", ref.Path(), creationErr.Path)
+			}
+
+			if !errors.Is(creationErr, errFailingInit) {
+				t.Fatalf("error cause should be %v, but got %v", errFailingInit, creationErr.Cause)
+			}
+
+			if creationErr.ProducerType == nil {
+				t.Fatalf("error should carry the producer type")
+			}
+
+			msg := creationErr.Error()
+			if !strings.Contains(msg, "/user/failing") || !strings.Contains(msg, errFailingInit.Error()) {
+				t.Fatalf("error message should contain the path and the cause, but got %q", msg)
+			}
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("creation failure was not published")
+	}
+}
+
+type CrashLoopActor struct {
+	*UntypedActor
+}
+
+func (p *CrashLoopActor) CrashLoopActor(attempts *int32) error {
+	atomic.AddInt32(attempts, 1)
+	return errFailingInit
+}
+
+func (p *CrashLoopActor) Receive(message interface{}) (handled bool, err error) {
+	return true, nil
+}
+
+// InitSupervisorActor creates a child failing in its constructor, it reports
+// the failures to causes and the termination of the child to terminated
+type InitSupervisorActor struct {
+	*UntypedActor
+
+	attempts   *int32
+	causes     chan error
+	terminated chan akka.ActorRef
+}
+
+func (p *InitSupervisorActor) InitSupervisorActor(attempts *int32, causes chan error, terminated chan akka.ActorRef) {
+	p.attempts = attempts
+	p.causes = causes
+	p.terminated = terminated
+}
+
+func (p *InitSupervisorActor) PreStart() (err error) {
+	var childProps akka.Props
+	if childProps, err = props.Create((*CrashLoopActor)(nil), p.attempts); err != nil {
+		return
+	}
+
+	var child akka.ActorRef
+	if child, err = p.Context().ActorOf(childProps, "crash-loop"); err != nil {
+		return
+	}
+
+	p.Context().Watch(child)
+	return
+}
+
+func (p *InitSupervisorActor) SupervisorStrategy() akka.SupervisorStrategy {
+	return NewOneForOneStrategy(-1, 0, func(cause error) akka.Directive {
+		p.causes <- cause
+		return DefaultDecider(cause)
+	})
+}
+
+func (p *InitSupervisorActor) Receive(message interface{}) (handled bool, err error) {
+	if terminated, ok := message.(*Terminated); ok {
+		p.terminated <- terminated.Actor
+	}
+	return true, nil
+}
+
+func TestFailingConstructorStopsTheActorInsteadOfRestarting(t *testing.T) {
+	system := newTestActorSystem(t, "ActorInitialization")
+
+	attempts := new(int32)
+	causes := make(chan error, 10)
+	terminated := make(chan akka.ActorRef, 1)
+
+	supervisorProps, err := props.Create((*InitSupervisorActor)(nil), attempts, causes, terminated)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	if _, err = system.ActorOf(supervisorProps, "supervisor"); err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	select {
+	case cause := <-causes:
+		{
+			initErr, ok := cause.(*ActorInitializationError)
+			if !ok {
+				t.Fatalf("supervisor should get an ActorInitializationError, but got %T: %v", cause, cause)
+			}
+
+			if initErr.Actor.Path().ToStringWithoutAddress() != "/user/supervisor/crash-loop" || !errors.Is(initErr, errFailingInit) {
+				t.Fatalf("error should carry the failing ref and the cause, but got %v", initErr)
+			}
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("initialization failure was not reported to the supervisor")
+	}
+
+	select {
+	case ref := <-terminated:
+		if ref.Path().Name() != "crash-loop" {
+			t.Fatalf("the failing child should be stopped, but got %s", ref)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("the failing child was not stopped")
+	}
+
+	if n := atomic.LoadInt32(attempts); n != 1 {
+		t.Fatalf("the failing child should be created once, but was created %d times", n)
+	}
+
+	if len(causes) > 0 {
+		t.Fatalf("the stopped child should not fail again, but got %v", <-causes)
+	}
+}
@@ -0,0 +1,252 @@
+package actor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/configuration"
+)
+
+type SelectingActor struct {
+	*UntypedActor
+
+	path string
+}
+
+func (p *SelectingActor) SelectingActor(path string) {
+	p.path = path
+}
+
+func (p *SelectingActor) Receive(message interface{}) (handled bool, err error) {
+	selection, err := p.Context().ActorSelection(p.path)
+	if err != nil {
+		return
+	}
+	return true, selection.Tell(message, p.Self())
+}
+
+type RecordingParentActor struct {
+	*UntypedActor
+
+	received chan interface{}
+}
+
+func (p *RecordingParentActor) RecordingParentActor(received chan interface{}) {
+	p.received = received
+}
+
+func (p *RecordingParentActor) PreStart() (err error) {
+	var childProps akka.Props
+	if childProps, err = props.Create((*RecordingActor)(nil), p.received); err != nil {
+		return
+	}
+
+	_, err = p.Context().ActorOf(childProps, "child")
+	return
+}
+
+func (p *RecordingParentActor) Receive(message interface{}) (handled bool, err error) {
+	return false, nil
+}
+
+func newSelectingActor(t *testing.T, system akka.ActorSystem, name, path string) akka.ActorRef {
+	selectingProps, err := props.Create((*SelectingActor)(nil), path)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(selectingProps, name)
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	return ref
+}
+
+func expectReceived(t *testing.T, received chan interface{}, expected interface{}) {
+	select {
+	case msg := <-received:
+		if msg != expected {
+			t.Fatalf("expected %v, but got %v", expected, msg)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("%v was not delivered through the selection", expected)
+	}
+}
+
+func TestActorSelectionOfSiblingByRelativePath(t *testing.T) {
+	system := newTestActorSystem(t, "RelativeSelection")
+
+	received := make(chan interface{}, 10)
+
+	recordingProps, err := props.Create((*RecordingActor)(nil), received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	if _, err = system.ActorOf(recordingProps, "sibling"); err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	newSelectingActor(t, system, "selecting", "../sibling").Tell("hello sibling")
+	expectReceived(t, received, "hello sibling")
+}
+
+func TestActorSelectionOfDescendants(t *testing.T) {
+	system := newTestActorSystem(t, "DescendantSelection")
+
+	received := make(chan interface{}, 10)
+
+	parentProps, err := props.Create((*RecordingParentActor)(nil), received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	if _, err = system.ActorOf(parentProps, "parent"); err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	newSelectingActor(t, system, "selecting", "../parent/child").Tell("hello child")
+	expectReceived(t, received, "hello child")
+
+	selection, err := system.ActorSelection("/user/parent/child")
+	if err != nil {
+		t.Fatalf("actor selection failure: %s", err.Error())
+	}
+
+	selection.Tell("from the system", nil)
+	expectReceived(t, received, "from the system")
+
+	missing, _ := system.ActorSelection("/user/parent/missing/child")
+	if _, ok := missing.Resolve(); ok {
+		t.Fatalf("selection of a missing actor should not resolve")
+	}
+
+	if err = missing.Tell("lost", nil); err != nil {
+		t.Fatalf("unresolved selection should not fail the sender, but got %s", err.Error())
+	}
+}
+
+func TestActorSelectionByGlobMatchesAllChildren(t *testing.T) {
+	system := newTestActorSystem(t, "GlobSelection")
+
+	received := make(chan interface{}, 10)
+
+	recordingProps, err := props.Create((*RecordingActor)(nil), received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	for _, name := range []string{"worker-1", "worker-2", "worker-10", "Worker-3", "manager", "a-worker-4"} {
+		if _, err = system.ActorOf(recordingProps, name); err != nil {
+			t.Fatalf("create actor failure: %s", err.Error())
+		}
+	}
+
+	selection, err := system.ActorSelection("/user/worker-*")
+	if err != nil {
+		t.Fatalf("actor selection failure: %s", err.Error())
+	}
+
+	var names []string
+	for _, ref := range selection.ResolveAll() {
+		names = append(names, ref.Path().Name())
+	}
+
+	if strings.Join(names, ",") != "worker-1,worker-10,worker-2" {
+		t.Fatalf("glob should match the whole name case-sensitive, but matched %v", names)
+	}
+
+	if err = selection.Tell("broadcast", nil); err != nil {
+		t.Fatalf("tell failure: %s", err.Error())
+	}
+
+	for i := 0; i < 3; i++ {
+		expectReceived(t, received, "broadcast")
+	}
+
+	select {
+	case msg := <-received:
+		t.Fatalf("only the matched workers should receive, but also got %v", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	single, _ := system.ActorSelection("/user/worker-?")
+	if refs := single.ResolveAll(); len(refs) != 2 {
+		t.Fatalf("worker-? should match worker-1 and worker-2, but got %v", refs)
+	}
+
+	none, _ := system.ActorSelection("/user/nobody-*")
+	if _, ok := none.Resolve(); ok {
+		t.Fatalf("glob without matches should not resolve")
+	}
+}
+
+func TestSelectionCacheIsInvalidatedByTheTerminationOfItsTarget(t *testing.T) {
+	config := strings.Replace(testConfig, `provider = "LocalActorRefProvider"`, `provider = "LocalActorRefProvider"
+		selection-cache = on`, 1)
+
+	system, err := NewActorSystem("SelectionCache", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	echoProps, err := props.Create((*echoActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	old, err := system.ActorOf(echoProps, "cached")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	selection, _ := system.ActorSelection("/user/cached")
+	for i := 0; i < 2; i++ {
+		if ref, ok := selection.ResolveOne(); !ok || ref.CompareTo(old) != 0 {
+			t.Fatalf("selection should resolve to the target, but got %v", ref)
+		}
+	}
+
+	if system.selections.Len() != 1 {
+		t.Fatalf("resolved selection should be cached once, but got %d", system.selections.Len())
+	}
+
+	inbox := NewInbox(system)
+	defer inbox.Stop()
+
+	inbox.Watch(old)
+	old.Tell(&PoisonPill{})
+
+	if message, err := inbox.Receive(3 * time.Second); err != nil {
+		t.Fatalf("target was not terminated: %v, %v", message, err)
+	}
+
+	restarted, err := system.ActorOf(echoProps, "cached")
+	if err != nil {
+		t.Fatalf("recreate actor failure: %s", err.Error())
+	}
+
+	again, _ := system.ActorSelection("/user/cached")
+	if ref, ok := again.ResolveOne(); !ok || ref.Path().Uid() != restarted.Path().Uid() {
+		t.Fatalf("cached ref of the old uid %d should be invalidated, but got %v", old.Path().Uid(), ref)
+	}
+
+	again.Tell("after restart", inbox.Self())
+	if reply, err := inbox.Receive(3 * time.Second); err != nil || reply != "after restart" {
+		t.Fatalf("restarted target should reply through the selection, but got %v, %v", reply, err)
+	}
+
+	restarted.Tell(&PoisonPill{})
+
+	deadline := time.Now().Add(3 * time.Second)
+	for system.selections.Len() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("terminated target should be forgotten by the watch, but %d paths are cached", system.selections.Len())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
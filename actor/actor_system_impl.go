@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"regexp"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/orcaman/concurrent-map"
@@ -14,6 +15,7 @@ import (
 	"github.com/go-akka/akka/event"
 	"github.com/go-akka/akka/pkg/class_loader"
 	"github.com/go-akka/akka/pkg/dynamic_access"
+	"github.com/go-akka/akka/serialization"
 	"github.com/go-akka/configuration"
 )
 
@@ -29,28 +31,63 @@ type ActorSystemImpl struct {
 	dynamicAccess dynamic_access.DynamicAccess
 	eventStream   akka.EventStream
 	scheduler     akka.Scheduler
+	schedulerOnce sync.Once
 	mailboxes     akka.Mailboxes
 	deadletters   akka.ActorRef
+	selections    *selectionCache
 	dispatchers   akka.Dispatchers
+	serialization *serialization.Serialization
 
 	provider         akka.ActorRefProvider
 	extensions       cmap.ConcurrentMap
 	extensionsLocker sync.Mutex
 
 	log akka.LoggingAdapter
+
+	uncaughtFailureHandler akka.UncaughtFailureHandler
+	uncaughtFailureLocker  sync.RWMutex
+
+	deadlockDetector *deadlockDetector
+
+	pubSubMediator akka.ActorRef
+	pubSubErr      error
+	pubSubOnce     sync.Once
+
+	terminationCallbacks terminationCallbacks
+	terminating          int32
 }
 
+var (
+	validNameRegexp = regexp.MustCompile("^[a-zA-Z0-9][a-zA-Z0-9-_]*$")
+)
+
+const (
+	startRollbackTimeout = 5 * time.Second
+)
+
 func AkkaClassLoader() class_loader.ClassLoader {
 	return class_loader.Default
 }
 
 func NewActorSystem(name string, config ...*configuration.Config) (system *ActorSystemImpl, err error) {
-	if match, _ := regexp.MatchString("^[a-zA-Z0-9][a-zA-Z0-9-_]*$", name); !match {
+	return NewActorSystemWithClassLoader(name, nil, config...)
+}
+
+// NewActorSystemWithClassLoader creates a system which looks up the provider,
+// scheduler, loggers and the other configured types in loader, so plugins can
+// register their types without touching class_loader.Default. The loader
+// should have AkkaClassLoader() in its parent chain, nil stands for it
+func NewActorSystemWithClassLoader(name string, loader class_loader.ClassLoader, config ...*configuration.Config) (system *ActorSystemImpl, err error) {
+	if !validNameRegexp.MatchString(name) {
 		err = akka.ErrInvalidActorSystemName
 		return
 	}
 
-	classLoader := class_loader.NewClassicClassLoader(class_loader.Default)
+	if loader == nil {
+		loader = AkkaClassLoader()
+	}
+
+	classLoader := class_loader.NewClassicClassLoader(loader)
 
 	sys := &ActorSystemImpl{
 		name:          name,
@@ -69,6 +106,10 @@ func NewActorSystem(name string, config ...*configuration.Config) (system *Actor
 		return
 	}
 
+	if err = sys.configureSerialization(); err != nil {
+		return
+	}
+
 	if err = sys.configureEventStream(); err != nil {
 		return
 	}
@@ -77,11 +118,13 @@ func NewActorSystem(name string, config ...*configuration.Config) (system *Actor
 		return
 	}
 
-	// if err = sys.configureScheduler(); err != nil {
-	// 	return
-	// }
-	sys.configureProvider()
-	// sys.configureTerminationCallbacks()
+	if err = sys.configureScheduler(); err != nil {
+		return
+	}
+	if err = sys.configureProvider(); err != nil {
+		return
+	}
+
 	sys.configureMailboxes()
 	sys.configureDispatchers()
 
@@ -96,6 +139,10 @@ func (p *ActorSystemImpl) Settings() *akka.Settings {
 	return p.settings
 }
 
+func (p *ActorSystemImpl) Serialization() *serialization.Serialization {
+	return p.serialization
+}
+
 func (p *ActorSystemImpl) Child(child string) (path akka.ActorPath, err error) {
 	return
 }
@@ -104,6 +151,10 @@ func (p *ActorSystemImpl) Descendant(names ...string) (path akka.ActorPath, err
 	return
 }
 
+func (p *ActorSystemImpl) ClassLoader() class_loader.ClassLoader {
+	return p.classLoader
+}
+
 func (p *ActorSystemImpl) DynamicAccess() dynamic_access.DynamicAccess {
 	return p.dynamicAccess
 }
@@ -125,12 +176,62 @@ func (p *ActorSystemImpl) SystemGuardian() akka.LocalActorRef {
 	return p.provider.SystemGuardian()
 }
 
+// Terminate stops the user guardian and its actors, then runs the termination
+// callbacks, the last ones flush the loggers, stop the system guardian and
+// shut the dispatchers down. An actor that does not stop within the
+// shutdown-timeout of the default dispatcher is not waited for any longer
 func (p *ActorSystemImpl) Terminate() (wg sync.WaitGroup) {
+	if !atomic.CompareAndSwapInt32(&p.terminating, 0, 1) {
+		<-p.WhenTerminated()
+		return
+	}
+
+	if p.deadlockDetector != nil {
+		p.deadlockDetector.Stop()
+	}
+
+	if p.provider != nil && p.provider.Guardian() != nil {
+		p.stopAndAwait(p.provider.Guardian())
+	}
+
+	p.terminationCallbacks.run()
+
 	return
 }
 
+// WhenTerminated is closed once Terminate has stopped the actors and run every
+// termination callback, it can be waited on before or after the system
+// terminated
+func (p *ActorSystemImpl) WhenTerminated() <-chan struct{} {
+	return p.terminationCallbacks.whenFinished()
+}
+
+// RegisterOnTermination callbacks run in reverse order of registration when
+// the system terminates, a callback registered after that runs right away
 func (p *ActorSystemImpl) RegisterOnTermination(fn func()) {
-	return
+	if !p.terminationCallbacks.add(fn) {
+		fn()
+	}
+}
+
+func (p *ActorSystemImpl) OnUncaughtFailure(fn akka.UncaughtFailureHandler) {
+	p.uncaughtFailureLocker.Lock()
+	defer p.uncaughtFailureLocker.Unlock()
+
+	p.uncaughtFailureHandler = fn
+}
+
+func (p *ActorSystemImpl) uncaughtFailure(err error, path akka.ActorPath) {
+	p.uncaughtFailureLocker.RLock()
+	handler := p.uncaughtFailureHandler
+	p.uncaughtFailureLocker.RUnlock()
+
+	if handler == nil {
+		p.log.Error(err, "Uncaught failure of [%s] escalated to the guardian", path)
+		return
+	}
+
+	handler(err, path)
 }
 
 func (p *ActorSystemImpl) Name() string {
@@ -145,10 +246,140 @@ func (p *ActorSystemImpl) DeadLetters() akka.ActorRef {
 	return p.deadletters
 }
 
+// senderOrDeadLetters replaces a missing sender by the dead letters, so a
+// reply never goes to nil
+func (p *ActorSystemImpl) senderOrDeadLetters(sender akka.ActorRef) akka.ActorRef {
+	if sender != nil {
+		return sender
+	}
+
+	if p.deadletters != nil {
+		return p.deadletters
+	}
+
+	return (*akka.NoSender)(nil)
+}
+
+// rejectMessage hands a message rejected by Tell to the dead letters, it is
+// published on the event stream directly if there are none
+func (p *ActorSystemImpl) rejectMessage(invalid *akka.InvalidMessage) {
+	p.toDeadLetters(invalid, invalid.Sender)
+}
+
+// verifySerializable fails if message does not survive a round trip through
+// its serializer unchanged, the unexported fields it would lose count as well
+func (p *ActorSystemImpl) verifySerializable(message interface{}) (err error) {
+	switch message.(type) {
+	case akka.SystemMessage, akka.AutoReceivedMessage, akka.NoSerializationVerificationNeeded:
+		{
+			return
+		}
+	}
+
+	var copy interface{}
+	if copy, err = p.serialization.SerializeAndDeserialize(message); err != nil {
+		err = fmt.Errorf("%s: %T: %s", ErrMessageNotSerializable, message, err)
+		return
+	}
+
+	if !reflect.DeepEqual(copy, message) {
+		err = fmt.Errorf("%s: %T: the round trip changed the message", ErrMessageNotSerializable, message)
+	}
+
+	return
+}
+
+// deadLetter hands a message no actor handles to the dead letters
+func (p *ActorSystemImpl) deadLetter(message interface{}, sender, recipient akka.ActorRef) {
+	p.toDeadLetters(akka.NewDeadLetter(message, sender, recipient), sender)
+}
+
+func (p *ActorSystemImpl) toDeadLetters(message interface{}, sender akka.ActorRef) {
+	if p.deadletters != nil {
+		p.deadletters.Tell(message, sender)
+		return
+	}
+
+	if p.eventStream != nil {
+		p.eventStream.Publish(message)
+	}
+}
+
 func (p *ActorSystemImpl) EventStream() akka.EventStream {
 	return p.eventStream
 }
 
+// Scheduler is the scheduler of akka.scheduler.implementation, without one
+// a DefaultScheduler is created on first use
+func (p *ActorSystemImpl) Scheduler() akka.Scheduler {
+	p.schedulerOnce.Do(func() {
+		if p.scheduler == nil {
+			p.scheduler = NewDefaultScheduler()
+		}
+	})
+	return p.scheduler
+}
+
+func (p *ActorSystemImpl) SystemMetrics() (metrics akka.SystemMetrics) {
+	metrics.Dispatchers = p.dispatchers.Metrics()
+
+	for _, dispatcherMetrics := range metrics.Dispatchers {
+		metrics.Mailboxes += dispatcherMetrics.Mailboxes
+		metrics.RunningMailboxes += dispatcherMetrics.RunningMailboxes
+		metrics.QueuedMessages += dispatcherMetrics.QueuedMessages
+		metrics.ProcessedMessages += dispatcherMetrics.ProcessedMessages
+		metrics.MessageDwellTime += dispatcherMetrics.MessageDwellTime
+	}
+
+	return
+}
+
+// SystemStatus samples the metrics of the system together with the number of
+// actors alive below the root guardian
+func (p *ActorSystemImpl) SystemStatus() (status akka.SystemStatus) {
+	status.StartTime = p.StartTime()
+	status.Uptime = time.Now().Sub(p.startedTime)
+	status.Metrics = p.SystemMetrics()
+
+	if root, ok := p.provider.RootGuardian().(*LocalActorRef); ok {
+		status.Actors = countActors(root)
+	}
+
+	return
+}
+
+// DumpMailbox returns the user messages queued for ref without dequeuing
+// them, it is a best-effort snapshot and nil unless akka.actor.debug.mailbox-dump
+// is on or the mailbox of ref can not be inspected
+func (p *ActorSystemImpl) DumpMailbox(ref akka.ActorRef) (messages []interface{}) {
+	if !p.settings.DebugMailboxDump {
+		return
+	}
+
+	localRef, ok := ref.(*LocalActorRef)
+	if !ok || localRef.Cell() == nil {
+		return
+	}
+
+	mailbox, ok := localRef.Cell().Mailbox().(interface {
+		MessageQueue() akka.MessageQueue
+	})
+	if !ok {
+		return
+	}
+
+	queue, ok := mailbox.MessageQueue().(akka.QueueBasedMessageQueue)
+	if !ok {
+		return
+	}
+
+	for _, envelope := range queue.Queue() {
+		messages = append(messages, envelope.Message)
+	}
+
+	return
+}
+
 func (p *ActorSystemImpl) StartTime() int64 {
 	return p.startedTime.Unix()
 }
@@ -161,8 +392,22 @@ func (p *ActorSystemImpl) Forward(message interface{}) {
 	return
 }
 
+// Equals compares the system by its path with refs and paths, a system is
+// only equal to itself though, as systems of the same name share their paths
 func (p *ActorSystemImpl) Equals(that interface{}) bool {
+	if p.path == nil || that == nil {
+		return false
+	}
+
 	switch other := that.(type) {
+	case *ActorSystemImpl:
+		{
+			return p == other
+		}
+	case akka.ActorRef:
+		{
+			return other.Path() != nil && p.path.CompareTo(other.Path()) == 0
+		}
 	case akka.ActorPath:
 		{
 			return p.path.CompareTo(other) == 0
@@ -171,6 +416,8 @@ func (p *ActorSystemImpl) Equals(that interface{}) bool {
 	return false
 }
 
+// Path is the path of the user guardian, the system tells its messages to
+// it, it is nil until the system is started
 func (p *ActorSystemImpl) Path() akka.ActorPath {
 	return p.path
 }
@@ -179,8 +426,10 @@ func (p *ActorSystemImpl) String() string {
 	return p.LookupRoot().Path().Root().Address().String()
 }
 
+// Tell delivers message to the user guardian, which handles StopChild and
+// hands every other message to the dead letters
 func (p *ActorSystemImpl) Tell(message interface{}, sender akka.ActorRef) {
-	return
+	p.Guardian().Tell(message, sender)
 }
 
 func (p *ActorSystemImpl) ActorOf(props akka.Props, name string) (ref akka.ActorRef, err error) {
@@ -199,8 +448,18 @@ func (p *ActorSystemImpl) Stop(actor akka.ActorRef) (err error) {
 	return nil
 }
 
-func (p *ActorSystemImpl) ActorSelection(path akka.ActorPath) (selection akka.ActorSelection, err error) {
-	return
+func (p *ActorSystemImpl) ActorSelection(path string) (selection akka.ActorSelection, err error) {
+	root := p.provider.RootGuardian()
+	return p.cachedSelection(newActorSelection(root, root, path, nil)), nil
+}
+
+// cachedSelection makes selection resolve through the selection-cache once
+// it is turned on
+func (p *ActorSystemImpl) cachedSelection(selection akka.ActorSelection) akka.ActorSelection {
+	if p.selections == nil {
+		return selection
+	}
+	return selection.WithCache(p.selections)
 }
 
 func (p *ActorSystemImpl) createDynamicAccess() dynamic_access.DynamicAccess {
@@ -215,7 +474,12 @@ func (p *ActorSystemImpl) configureSettings(config *configuration.Config) (err e
 
 	p.settings = settings
 
-	return err
+	return p.validateSettings()
+}
+
+func (p *ActorSystemImpl) configureSerialization() (err error) {
+	p.serialization, err = serialization.NewSerialization(p.settings, p.classLoader, p.dynamicAccess)
+	return
 }
 
 func (p *ActorSystemImpl) configureEventStream() (err error) {
@@ -231,6 +495,11 @@ func (p *ActorSystemImpl) configureLoggers() (err error) {
 }
 
 func (p *ActorSystemImpl) configureScheduler() (err error) {
+	// the DefaultScheduler is created by Scheduler on first use
+	if len(p.settings.SchedulerClass) == 0 {
+		return
+	}
+
 	schedulerType, exist := p.classLoader.ClassNameOf(p.settings.SchedulerClass)
 	if !exist {
 		err = fmt.Errorf("type not in class loader, %s: %s", "akka.scheduler.implementation", p.settings.SchedulerClass)
@@ -238,7 +507,7 @@ func (p *ActorSystemImpl) configureScheduler() (err error) {
 	}
 
 	var ins interface{}
-	ins, err = p.dynamicAccess.CreateInstanceByType(schedulerType, p.settings.Config(), nil)
+	ins, err = p.dynamicAccess.CreateInstanceByType(schedulerType, p.settings.Config())
 	if err != nil {
 		return
 	}
@@ -266,6 +535,7 @@ func (p *ActorSystemImpl) configureProvider() (err error) {
 		return
 	} else {
 		p.provider = provider
+		p.deadletters = provider.DeadLetters()
 	}
 
 	return
@@ -277,19 +547,78 @@ func (p *ActorSystemImpl) configureMailboxes() (err error) {
 }
 
 func (p *ActorSystemImpl) configureDispatchers() {
-	p.dispatchers = dispatch.NewDispatchers(p.settings, dispatch.NewDefaultDispatcherPrerequisites(p.eventStream, p.scheduler, p.dynamicAccess, p.settings, p.mailboxes))
+	p.dispatchers = dispatch.NewDispatchers(p.settings, dispatch.NewDefaultDispatcherPrerequisites(p.eventStream, p.Scheduler, p.dynamicAccess, p.settings, p.mailboxes, p.log))
 }
 
+// Start initializes the provider, if that fails the loggers and dispatchers
+// it already started are stopped again, so a failed system leaves nothing running
 func (p *ActorSystemImpl) Start() (err error) {
 	if err = p.provider.Init(p); err != nil {
+		p.rollbackStart()
 		return
 	}
 
+	p.path = p.provider.Guardian().Path()
+
+	// registered first so it runs last, the loggers run on the dispatchers
+	// and have to be flushed before
+	dispatch.ShutdownOnTermination(p, p.dispatchers)
+
+	// the loggers are children of the system guardian, it is stopped once
+	// they are flushed
+	p.RegisterOnTermination(func() {
+		p.stopAndAwait(p.provider.SystemGuardian())
+	})
+
+	// registered before the other callbacks so they can still log
+	p.RegisterOnTermination(func() {
+		p.eventStream.StopDefaultLoggers(p)
+	})
+
+	if deadLetters, ok := p.deadletters.(*DeadLetterActorRef); ok {
+		p.RegisterOnTermination(deadLetters.flushSummary)
+	}
+
+	if p.settings.SelectionCache {
+		p.selections = newSelectionCache(p.provider)
+	}
+
 	p.loadExtensions()
 
+	if p.settings.DebugDeadlockDetection {
+		p.deadlockDetector = newDeadlockDetector(p, p.settings.DeadlockDetectionInterval, p.settings.DeadlockDetectionThreshold)
+		p.deadlockDetector.Start()
+	}
+
+	if p.settings.LogDeadLetters > 0 {
+		if err = p.startDeadLetterListener(p.settings.LogDeadLetters, p.settings.LogDeadLettersSummaryInterval); err != nil {
+			p.rollbackStart()
+			return
+		}
+	}
+
+	if p.settings.SystemStatusInterval > 0 {
+		if err = p.startSystemStatusPublisher(p.settings.SystemStatusInterval); err != nil {
+			p.rollbackStart()
+			return
+		}
+	}
+
 	return
 }
 
+func (p *ActorSystemImpl) rollbackStart() {
+	if p.deadlockDetector != nil {
+		p.deadlockDetector.Stop()
+	}
+
+	p.eventStream.StopDefaultLoggers(p)
+
+	if !p.dispatchers.Shutdown(startRollbackTimeout) {
+		p.eventStream.Publish(event.NewWarningEvent(p.name, p, fmt.Sprintf("dispatchers did not shut down within %s after the failed start", startRollbackTimeout)))
+	}
+}
+
 func (p *ActorSystemImpl) LookupRoot() akka.InternalActorRef {
 	return p.provider.RootGuardian()
 }
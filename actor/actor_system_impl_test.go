@@ -0,0 +1,558 @@
+package actor
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/dispatch"
+	"github.com/go-akka/akka/dispatch/sysmsg"
+	"github.com/go-akka/configuration"
+)
+
+const testConfig = `
+akka {
+	loglevel = "ERROR"
+	stdout-loglevel = "ERROR"
+	loggers = []
+	logger-startup-timeout = 5s
+
+	actor {
+		provider = "LocalActorRefProvider"
+
+		default-dispatcher {
+			type = "dispatcher"
+			throughput = 5
+		}
+
+		default-mailbox {
+			mailbox-type = "akka.dispatch.unbounded-mailbox"
+		}
+	}
+}
+`
+
+func newTestActorSystem(t *testing.T, name string) *ActorSystemImpl {
+	system, err := NewActorSystem(name, configuration.ParseString(testConfig))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+	return system
+}
+
+type failingChildActor struct {
+	*UntypedActor
+}
+
+func (p *failingChildActor) Receive(message interface{}) (handled bool, err error) {
+	return true, errors.New("child failure")
+}
+
+type escalatingParentActor struct {
+	*UntypedActor
+}
+
+func (p *escalatingParentActor) Receive(message interface{}) (handled bool, err error) {
+	var childProps akka.Props
+	if childProps, err = props.Create((*failingChildActor)(nil)); err != nil {
+		return
+	}
+
+	var child akka.ActorRef
+	if child, err = p.Context().ActorOf(childProps, "child"); err != nil {
+		return
+	}
+
+	child.Tell(message, p.Self())
+	handled = true
+	return
+}
+
+func (p *escalatingParentActor) SupervisorStrategy() akka.SupervisorStrategy {
+	return NewOneForOneStrategy(-1, 0, func(cause error) akka.Directive {
+		return akka.EscalateDirective
+	})
+}
+
+func TestUncaughtFailureEscalatedToGuardian(t *testing.T) {
+	system := newTestActorSystem(t, "UncaughtFailure")
+
+	type uncaughtFailure struct {
+		err  error
+		path akka.ActorPath
+	}
+
+	failures := make(chan uncaughtFailure, 1)
+	system.OnUncaughtFailure(func(err error, path akka.ActorPath) {
+		select {
+		case failures <- uncaughtFailure{err, path}:
+		default:
+		}
+	})
+
+	parentProps, err := props.Create((*escalatingParentActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	parent, err := system.ActorOf(parentProps, "parent")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	parent.Tell("fail")
+
+	select {
+	case failure := <-failures:
+		{
+			if failure.path.String() != parent.Path().String() {
+				t.Fatalf("uncaught failure path should be %s, but got %s", parent.Path(), failure.path)
+			}
+
+			if failure.err == nil || failure.err.Error() != "child failure" {
+				t.Fatalf("uncaught failure cause should be the child failure, but got %v", failure.err)
+			}
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("uncaught failure handler was not invoked")
+	}
+}
+
+type CountingActor struct {
+	*UntypedActor
+
+	wg *sync.WaitGroup
+}
+
+func (p *CountingActor) CountingActor(wg *sync.WaitGroup) {
+	p.wg = wg
+}
+
+func (p *CountingActor) Receive(message interface{}) (handled bool, err error) {
+	p.wg.Done()
+	return true, nil
+}
+
+func TestSystemMetrics(t *testing.T) {
+	system := newTestActorSystem(t, "SystemMetrics")
+
+	before := system.SystemMetrics()
+
+	wg := &sync.WaitGroup{}
+
+	countingProps, err := props.Create((*CountingActor)(nil), wg)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	var refs []akka.ActorRef
+	for i := 0; i < 3; i++ {
+		ref, err := system.ActorOf(countingProps, "counting-"+strconv.Itoa(i))
+		if err != nil {
+			t.Fatalf("create actor failure: %s", err.Error())
+		}
+		refs = append(refs, ref)
+	}
+
+	wg.Add(30)
+	for _, ref := range refs {
+		for i := 0; i < 10; i++ {
+			ref.Tell(i)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("messages were not processed")
+	}
+
+	metrics := system.SystemMetrics()
+	for deadline := time.Now().Add(time.Second); metrics.ProcessedMessages < before.ProcessedMessages+30 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+		metrics = system.SystemMetrics()
+	}
+
+	if metrics.Mailboxes != before.Mailboxes+3 {
+		t.Fatalf("metrics should count 3 more mailboxes, before: %d, after: %d", before.Mailboxes, metrics.Mailboxes)
+	}
+
+	if metrics.ProcessedMessages < before.ProcessedMessages+30 {
+		t.Fatalf("metrics should count at least 30 more processed messages, before: %d, after: %d", before.ProcessedMessages, metrics.ProcessedMessages)
+	}
+
+	if metrics.QueuedMessages < 0 || metrics.QueuedMessages > 30 {
+		t.Fatalf("queued messages should be plausible, but got %d", metrics.QueuedMessages)
+	}
+
+	found := false
+	for _, dispatcherMetrics := range metrics.Dispatchers {
+		if dispatcherMetrics.Id == dispatch.DefaultDispatcherId {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatalf("metrics should contain the default dispatcher, but got %v", metrics.Dispatchers)
+	}
+}
+
+func TestActorSystemWithoutConfigUsesReferenceDefaults(t *testing.T) {
+	system, err := NewActorSystem("ReferenceDefaults")
+	if err != nil {
+		t.Fatalf("create actor system without config failure: %s", err.Error())
+	}
+
+	settings := system.Settings()
+
+	if settings.ProviderClass != "LocalActorRefProvider" {
+		t.Fatalf("default provider should be LocalActorRefProvider, but got %q", settings.ProviderClass)
+	}
+
+	if settings.LogLevel != "INFO" || len(settings.Loggers) != 1 {
+		t.Fatalf("default logging should be INFO with the default logger, but got %s and %v", settings.LogLevel, settings.Loggers)
+	}
+
+	dispatcher := system.dispatchers.Lookup(dispatch.DefaultDispatcherId)
+	if dispatcher == nil || dispatcher.SystemMessageDrainInterval() != 1 {
+		t.Fatalf("default dispatcher should be configured from the reference config")
+	}
+
+	if quota := dispatcher.SystemMessageQuota(); quota != dispatch.DefaultSystemMessageQuota || quota <= 0 {
+		t.Fatalf("default dispatcher should have a system message quota of %d, but got %d", dispatch.DefaultSystemMessageQuota, quota)
+	}
+
+	mailboxType, exist := system.mailboxes.Lookup(dispatch.DefaultMailboxId)
+	if !exist {
+		t.Fatalf("default mailbox should be configured")
+	}
+
+	if _, ok := mailboxType.(*dispatch.UnboundedMailbox); !ok {
+		t.Fatalf("default mailbox should be unbounded, but got %T", mailboxType)
+	}
+
+	wg := &sync.WaitGroup{}
+	countingProps, err := props.Create((*CountingActor)(nil), wg)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(countingProps, "counting")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	wg.Add(1)
+	ref.Tell("hello")
+	wg.Wait()
+}
+
+func TestSystemTellRoutesToTheUserGuardian(t *testing.T) {
+	system := newTestActorSystem(t, "SystemTell")
+	collector := newEventCollector(system, akka.DeadLetter{})
+
+	received := make(chan interface{}, 1)
+
+	recordingProps, err := props.Create((*RecordingActor)(nil), received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	worker, err := system.ActorOf(recordingProps, "worker")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	system.Tell(sysmsg.NewStopChild(worker), nil)
+
+	cell := worker.(*LocalActorRef).Cell()
+	for deadline := time.Now().Add(3 * time.Second); !cell.IsTerminated() && time.Now().Before(deadline); {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if !cell.IsTerminated() {
+		t.Fatalf("StopChild told to the system should stop the child of the user guardian")
+	}
+
+	system.Tell("unknown", nil)
+
+	select {
+	case e := <-collector.events:
+		{
+			deadLetter, ok := e.(akka.DeadLetter)
+			if !ok || deadLetter.Message() != "unknown" {
+				t.Fatalf("unknown message should become a dead letter, but got %#v", e)
+			}
+
+			if !deadLetter.Recipient().Equals(system.Guardian()) {
+				t.Fatalf("dead letter recipient should be the user guardian, but got %s", deadLetter.Recipient())
+			}
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("unknown message was not handed to the dead letters")
+	}
+}
+
+func TestWhenTerminatedCompletesAfterTermination(t *testing.T) {
+	system := newTestActorSystem(t, "WhenTerminated")
+
+	whenTerminated := system.WhenTerminated()
+
+	select {
+	case <-whenTerminated:
+		t.Fatalf("running system should not be terminated")
+	default:
+	}
+
+	echoProps, err := props.Create((*echoActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	echo, err := system.ActorOf(echoProps, "echo")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	callbackDone := make(chan struct{})
+	var echoStoppedFirst bool
+	system.RegisterOnTermination(func() {
+		echoStoppedFirst = echo.(*LocalActorRef).IsTerminated() && system.Guardian().IsTerminated()
+		time.Sleep(50 * time.Millisecond)
+		close(callbackDone)
+	})
+
+	go system.Terminate()
+
+	select {
+	case <-whenTerminated:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("system did not terminate")
+	}
+
+	if !echoStoppedFirst {
+		t.Fatalf("the user guardian and its actors should be stopped before the termination callbacks run")
+	}
+
+	if !system.SystemGuardian().IsTerminated() {
+		t.Fatalf("the system guardian should be stopped once the system terminated")
+	}
+
+	select {
+	case <-callbackDone:
+	default:
+		t.Fatalf("termination should complete after the termination callbacks")
+	}
+
+	select {
+	case <-system.WhenTerminated():
+	default:
+		t.Fatalf("WhenTerminated after termination should be completed already")
+	}
+
+	system.Terminate()
+}
+
+func TestTerminationCallbacksRunOnceInReverseOrder(t *testing.T) {
+	system := newTestActorSystem(t, "TerminationCallbacks")
+
+	var order []int
+	for i := 1; i <= 3; i++ {
+		n := i
+		system.RegisterOnTermination(func() { order = append(order, n) })
+	}
+
+	system.Terminate()
+	system.Terminate()
+
+	if !reflect.DeepEqual(order, []int{3, 2, 1}) {
+		t.Fatalf("termination callbacks should run once in reverse order of registration, but got %v", order)
+	}
+
+	late := false
+	system.RegisterOnTermination(func() { late = true })
+
+	if !late {
+		t.Fatalf("callback registered after termination should run at once")
+	}
+}
+
+// StuckActor blocks on the first message until released
+type StuckActor struct {
+	*UntypedActor
+
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p *StuckActor) StuckActor(started, release chan struct{}) {
+	p.started = started
+	p.release = release
+}
+
+func (p *StuckActor) Receive(message interface{}) (handled bool, err error) {
+	if message == "block" {
+		close(p.started)
+		<-p.release
+	}
+	return true, nil
+}
+
+func TestTerminationForceClosesStuckMailboxesAfterShutdownTimeout(t *testing.T) {
+	config := strings.Replace(testConfig, "throughput = 5", "throughput = 5\n\t\t\tshutdown-timeout = 100ms", 1)
+
+	system, err := NewActorSystem("ShutdownTimeout", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	collector := newEventCollector(system, akka.DeadLetter{})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	stuckProps, err := props.Create((*StuckActor)(nil), started, release)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(stuckProps, "stuck")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	ref.Tell("block")
+
+	select {
+	case <-started:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("stuck actor did not receive its first message")
+	}
+
+	ref.Tell("a")
+	ref.Tell("b")
+
+	terminated := make(chan time.Duration)
+	go func() {
+		start := time.Now()
+		system.Terminate()
+		terminated <- time.Since(start)
+	}()
+
+	select {
+	case took := <-terminated:
+		if took < 100*time.Millisecond {
+			t.Fatalf("termination should wait for the shutdown-timeout, but took %s", took)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("termination should not wait for the stuck handler")
+	}
+
+	var lost []string
+	for len(lost) < 2 {
+		select {
+		case e := <-collector.events:
+			if msg, ok := e.(akka.DeadLetter).Message().(string); ok {
+				lost = append(lost, msg)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("queued messages should be dead-lettered, but got %v", lost)
+		}
+	}
+
+	if strings.Join(lost, "") != "ab" {
+		t.Fatalf("queued messages should be dead-lettered in order, but got %v", lost)
+	}
+}
+
+func TestActorSystemEqualsItsGuardianByPath(t *testing.T) {
+	system := newTestActorSystem(t, "SystemIdentity")
+	foreign := newTestActorSystem(t, "ForeignSystem")
+
+	guardian := system.Provider().Guardian()
+
+	if !system.Equals(guardian) || !system.Equals(guardian.Path()) {
+		t.Fatalf("system should equal its guardian %s", guardian.Path())
+	}
+
+	if system.Equals(system.Provider().SystemGuardian()) || system.Equals(guardian.Path().Append("child")) {
+		t.Fatalf("system should only equal its guardian path")
+	}
+
+	if system.Equals(foreign.Provider().Guardian()) || system.Equals(foreign.Provider().Guardian().Path()) || system.Equals(foreign) {
+		t.Fatalf("system should not equal the foreign system %s", foreign.Path())
+	}
+
+	if !system.Equals(system) || system.Equals(nil) || system.Equals("SystemIdentity") {
+		t.Fatalf("system should equal itself only")
+	}
+}
+
+type SlowActor struct {
+	*UntypedActor
+
+	wg *sync.WaitGroup
+}
+
+func (p *SlowActor) SlowActor(wg *sync.WaitGroup) {
+	p.wg = wg
+}
+
+func (p *SlowActor) Receive(message interface{}) (handled bool, err error) {
+	time.Sleep(20 * time.Millisecond)
+	p.wg.Done()
+	return true, nil
+}
+
+func TestSystemMetricsMeasureMessageDwellTime(t *testing.T) {
+	system := newTestActorSystem(t, "MessageDwellTime")
+
+	wg := &sync.WaitGroup{}
+	slowProps, err := props.Create((*SlowActor)(nil), wg)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	slow, err := system.ActorOf(slowProps, "slow")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	before := system.SystemMetrics()
+
+	// the n-th message waits for the n-1 before it
+	wg.Add(5)
+	for i := 0; i < 5; i++ {
+		slow.Tell(i)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("messages were not processed")
+	}
+
+	// 20ms + 40ms + 60ms + 80ms at least
+	if dwell := system.SystemMetrics().MessageDwellTime - before.MessageDwellTime; dwell < 200*time.Millisecond {
+		t.Fatalf("messages queued behind a slow actor should have waited 200ms in total, but got %s", dwell)
+	}
+}
@@ -0,0 +1,234 @@
+package actor
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/configuration"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/pkg/class_loader"
+)
+
+var errFailingProviderInit = errors.New("failing provider init")
+
+func init() {
+	class_loader.Default.Register((*FailingInitProvider)(nil), "akka.test.failing-init-provider")
+	class_loader.Default.Register((*StatusNameTakenProvider)(nil), "akka.test.status-name-taken-provider")
+}
+
+// FailingInitProvider fails after the local provider has started its guardians
+// and loggers, to check nothing of the partial init is left running
+type FailingInitProvider struct {
+	LocalActorRefProvider
+}
+
+func (p *FailingInitProvider) Init(system akka.ActorSystem) (err error) {
+	if err = p.LocalActorRefProvider.Init(system); err != nil {
+		return
+	}
+	return errFailingProviderInit
+}
+
+// StatusNameTakenProvider reserves the name of the system status publisher,
+// so the start fails after the provider, the loggers and the deadlock
+// detector are running
+type StatusNameTakenProvider struct {
+	LocalActorRefProvider
+}
+
+func (p *StatusNameTakenProvider) Init(system akka.ActorSystem) (err error) {
+	if err = p.LocalActorRefProvider.Init(system); err != nil {
+		return
+	}
+	p.SystemGuardian().Underlying().(*ActorCell).ReserveChild("systemStatus")
+	return
+}
+
+func TestFailedStartDoesNotLeakGoroutines(t *testing.T) {
+	config := strings.Replace(testConfig, `provider = "LocalActorRefProvider"`, `provider = "akka.test.failing-init-provider"`, 1)
+	config = strings.Replace(config, "loggers = []", `loggers = ["akka.event.default-logger"]`, 1)
+
+	baseline := runtime.NumGoroutine()
+
+	_, err := NewActorSystem("FailedStart", configuration.ParseString(config))
+	if err != errFailingProviderInit {
+		t.Fatalf("start should fail with the provider init error, but got %v", err)
+	}
+
+	expectNoLeakedGoroutines(t, baseline)
+}
+
+func TestFailedSystemActorStartDoesNotLeakGoroutines(t *testing.T) {
+	config := strings.Replace(testConfig, `provider = "LocalActorRefProvider"`, `provider = "akka.test.status-name-taken-provider"`, 1)
+	config = strings.Replace(config, "loggers = []", `loggers = ["akka.event.default-logger"]
+	system-status-interval = 1s`, 1)
+	config = strings.Replace(config, "actor {", `actor {
+		debug {
+			deadlock-detection = on
+		}
+`, 1)
+
+	baseline := runtime.NumGoroutine()
+
+	_, err := NewActorSystem("FailedSystemActorStart", configuration.ParseString(config))
+	if err == nil || !strings.HasPrefix(err.Error(), ErrActorNameExists.Error()) {
+		t.Fatalf("start should fail with ErrActorNameExists, but got %v", err)
+	}
+
+	expectNoLeakedGoroutines(t, baseline)
+}
+
+func expectNoLeakedGoroutines(t *testing.T, baseline int) {
+	deadline := time.Now().Add(3 * time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			t.Fatalf("%d goroutines leaked after the failed start:\n%s", runtime.NumGoroutine()-baseline, buf[:runtime.Stack(buf, true)])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLocalProviderSpawnsActorsUnderTheGuardian(t *testing.T) {
+	config := strings.Replace(testConfig, `provider = "LocalActorRefProvider"`, `provider = "akka.actor.LocalActorRefProvider"`, 1)
+
+	system, err := NewActorSystem("LocalProvider", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	provider := system.Provider()
+
+	if provider.Guardian().Path().String() != "akka://LocalProvider/user" {
+		t.Fatalf("user guardian should live at /user, but got %s", provider.Guardian().Path())
+	}
+
+	if provider.SystemGuardian().Path().String() != "akka://LocalProvider/system" {
+		t.Fatalf("system guardian should live at /system, but got %s", provider.SystemGuardian().Path())
+	}
+
+	if provider.DefaultAddress() != provider.RootPath().Address() {
+		t.Fatalf("default address should be the address of the root path, but got %s", provider.DefaultAddress())
+	}
+
+	if provider.RootGuardianAt(provider.DefaultAddress()) != provider.RootGuardian() {
+		t.Fatalf("root guardian at the default address should be the root guardian")
+	}
+
+	received := make(chan interface{}, 1)
+
+	recordingProps, err := props.Create((*RecordingActor)(nil), received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(recordingProps, "worker")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	if ref.Path().Name() != "worker" || ref.Path().Parent().CompareTo(provider.Guardian().Path()) != 0 {
+		t.Fatalf("actor should be created under the user guardian, but got %s", ref.Path())
+	}
+
+	if parent := ref.(*LocalActorRef).Parent(); !parent.Equals(provider.Guardian()) {
+		t.Fatalf("parent of the actor should be the user guardian, but got %s", parent)
+	}
+
+	ref.Tell("ping")
+
+	select {
+	case msg := <-received:
+		if msg != "ping" {
+			t.Fatalf("actor should receive ping, but got %v", msg)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("message was not delivered")
+	}
+}
+
+func TestGuardiansParentUserAndSystemActors(t *testing.T) {
+	system := newTestActorSystem(t, "Guardians")
+
+	received := make(chan interface{}, 2)
+
+	recordingProps, err := props.Create((*RecordingActor)(nil), received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	userActor, err := system.ActorOf(recordingProps, "worker")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	systemActor, err := system.SystemActorOf(recordingProps, "daemon")
+	if err != nil {
+		t.Fatalf("create system actor failure: %s", err.Error())
+	}
+
+	if path := userActor.Path().ToStringWithoutAddress(); path != "/user/worker" {
+		t.Fatalf("user actor should live at /user/worker, but got %s", path)
+	}
+
+	if path := systemActor.Path().ToStringWithoutAddress(); path != "/system/daemon" {
+		t.Fatalf("system actor should live at /system/daemon, but got %s", path)
+	}
+
+	root := system.Provider().RootGuardian()
+	for _, guardian := range []akka.LocalActorRef{system.Guardian(), system.SystemGuardian()} {
+		if parent := guardian.(*LocalActorRef).Parent(); !parent.Equals(root) {
+			t.Fatalf("parent of %s should be the root guardian, but got %s", guardian.Path(), parent)
+		}
+	}
+
+	for _, ref := range []akka.ActorRef{userActor, systemActor} {
+		ref.Tell("ping")
+
+		select {
+		case <-received:
+		case <-time.After(3 * time.Second):
+			t.Fatalf("%s did not receive the message", ref.Path())
+		}
+	}
+}
+
+func TestInvalidConfigurationReportsEveryProblem(t *testing.T) {
+	config := strings.Replace(testConfig, `loglevel = "ERROR"`, `loglevel = "LOUD"`, 1)
+	config = strings.Replace(config, "loggers = []", `loggers = ["akka.test.missing-logger"]`, 1)
+	config = strings.Replace(config, "throughput = 5", "throughput = 0", 1)
+	config = strings.Replace(config, `provider = "LocalActorRefProvider"`, `provider = "LocalActorRefProvider"
+
+		custom-dispatcher {
+			type = "akka.test.missing-configurator"
+		}`, 1)
+
+	_, err := NewActorSystem("InvalidConfiguration", configuration.ParseString(config))
+
+	configErr, ok := err.(*ConfigurationError)
+	if !ok || !strings.HasPrefix(err.Error(), ErrInvalidConfiguration.Error()) {
+		t.Fatalf("start should fail with a ConfigurationError, but got %v", err)
+	}
+
+	expected := []string{
+		`akka.loglevel = "LOUD"`,
+		"akka.loggers: akka.test.missing-logger",
+		"akka.actor.default-dispatcher.throughput = 0",
+		"akka.actor.custom-dispatcher.type: akka.test.missing-configurator",
+	}
+
+	if len(configErr.Problems) != len(expected) {
+		t.Fatalf("every problem should be reported once, but got %q", configErr.Problems)
+	}
+
+	for _, want := range expected {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("problem %q should be reported, but got %s", want, err.Error())
+		}
+	}
+}
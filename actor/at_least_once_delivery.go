@@ -0,0 +1,177 @@
+package actor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/event"
+)
+
+const (
+	DefaultRedeliverInterval      = 5 * time.Second
+	DefaultRedeliveryBurstLimit   = 10000
+	DefaultMaxUnconfirmedMessages = 100000
+)
+
+type AtLeastOnceDeliverySettings struct {
+	// RedeliverInterval is how long a delivery stays unconfirmed before it is
+	// sent again
+	RedeliverInterval time.Duration
+	// RedeliveryBurstLimit caps the deliveries sent again per interval
+	RedeliveryBurstLimit int
+	// MaxUnconfirmedMessages caps the deliveries in flight, Deliver fails once
+	// it is reached
+	MaxUnconfirmedMessages int
+	// MaxRedeliveries drops a delivery after it was sent again that many
+	// times, zero keeps redelivering until it is confirmed
+	MaxRedeliveries int
+}
+
+type redeliveryTick struct{}
+
+type unconfirmedDelivery struct {
+	destination akka.ActorRef
+	message     interface{}
+	sentAt      time.Time
+	attempts    int
+}
+
+// AtLeastOnceDelivery sends messages again until they are confirmed, it is
+// meant to be embedded by an actor and created with its context in PreStart.
+// Every message has to be given to ReceiveRedelivery first, it is not safe to
+// use outside of the actor
+type AtLeastOnceDelivery struct {
+	context  akka.ActorContext
+	settings AtLeastOnceDeliverySettings
+
+	deliverySequenceNr int64
+	unconfirmed        map[int64]*unconfirmedDelivery
+
+	redeliverTask akka.Cancelable
+}
+
+func NewAtLeastOnceDelivery(context akka.ActorContext, settings AtLeastOnceDeliverySettings) *AtLeastOnceDelivery {
+	if settings.RedeliverInterval <= 0 {
+		settings.RedeliverInterval = DefaultRedeliverInterval
+	}
+
+	if settings.RedeliveryBurstLimit <= 0 {
+		settings.RedeliveryBurstLimit = DefaultRedeliveryBurstLimit
+	}
+
+	if settings.MaxUnconfirmedMessages <= 0 {
+		settings.MaxUnconfirmedMessages = DefaultMaxUnconfirmedMessages
+	}
+
+	return &AtLeastOnceDelivery{
+		context:     context,
+		settings:    settings,
+		unconfirmed: make(map[int64]*unconfirmedDelivery),
+	}
+}
+
+// Deliver sends the message built for the next delivery id to destination,
+// the id has to be confirmed with ConfirmDelivery to stop the redelivery
+func (p *AtLeastOnceDelivery) Deliver(destination akka.ActorRef, deliveryIdToMessage func(deliveryId int64) interface{}) (deliveryId int64, err error) {
+	if len(p.unconfirmed) >= p.settings.MaxUnconfirmedMessages {
+		p.warning(fmt.Sprintf("redelivery buffer is full, %d deliveries are unconfirmed", len(p.unconfirmed)))
+		err = ErrMaxUnconfirmedMessagesExceeded
+		return
+	}
+
+	p.deliverySequenceNr++
+	deliveryId = p.deliverySequenceNr
+
+	delivery := &unconfirmedDelivery{
+		destination: destination,
+		message:     deliveryIdToMessage(deliveryId),
+		sentAt:      time.Now(),
+	}
+
+	p.unconfirmed[deliveryId] = delivery
+	destination.Tell(delivery.message, p.context.Self())
+
+	p.scheduleRedelivery()
+
+	return
+}
+
+// ConfirmDelivery returns false if deliveryId is not unconfirmed, like when
+// it was confirmed already
+func (p *AtLeastOnceDelivery) ConfirmDelivery(deliveryId int64) bool {
+	if _, exist := p.unconfirmed[deliveryId]; !exist {
+		return false
+	}
+
+	delete(p.unconfirmed, deliveryId)
+
+	if len(p.unconfirmed) == 0 && p.redeliverTask != nil {
+		p.redeliverTask.Cancel(false)
+		p.redeliverTask = nil
+	}
+
+	return true
+}
+
+func (p *AtLeastOnceDelivery) NumberOfUnconfirmed() int {
+	return len(p.unconfirmed)
+}
+
+// ReceiveRedelivery handles the redelivery ticks, it returns false for all
+// other messages
+func (p *AtLeastOnceDelivery) ReceiveRedelivery(message interface{}) bool {
+	if _, ok := message.(*redeliveryTick); !ok {
+		return false
+	}
+
+	p.redeliverTask = nil
+	p.redeliverOverdue()
+	p.scheduleRedelivery()
+
+	return true
+}
+
+func (p *AtLeastOnceDelivery) redeliverOverdue() {
+	deadline := time.Now().Add(-p.settings.RedeliverInterval)
+
+	var ids []int64
+	for id, delivery := range p.unconfirmed {
+		if !delivery.sentAt.After(deadline) {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	if len(ids) > p.settings.RedeliveryBurstLimit {
+		ids = ids[:p.settings.RedeliveryBurstLimit]
+	}
+
+	for _, id := range ids {
+		delivery := p.unconfirmed[id]
+
+		if p.settings.MaxRedeliveries > 0 && delivery.attempts >= p.settings.MaxRedeliveries {
+			delete(p.unconfirmed, id)
+			p.warning(fmt.Sprintf("delivery %d to %s is dropped unconfirmed after %d redeliveries", id, delivery.destination.Path(), delivery.attempts))
+			continue
+		}
+
+		delivery.attempts++
+		delivery.sentAt = time.Now()
+		delivery.destination.Tell(delivery.message, p.context.Self())
+	}
+}
+
+func (p *AtLeastOnceDelivery) scheduleRedelivery() {
+	if p.redeliverTask != nil || len(p.unconfirmed) == 0 {
+		return
+	}
+
+	p.redeliverTask = p.context.Schedule(p.settings.RedeliverInterval, &redeliveryTick{})
+}
+
+func (p *AtLeastOnceDelivery) warning(message string) {
+	p.context.System().EventStream().Publish(event.NewWarningEvent(p.context.Self().Path().String(), p, message))
+}
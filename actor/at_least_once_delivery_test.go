@@ -0,0 +1,174 @@
+package actor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/event"
+)
+
+type reliableMessage struct {
+	DeliveryId int64
+	Payload    string
+}
+
+type reliableConfirm struct {
+	DeliveryId int64
+}
+
+type ReliableSenderActor struct {
+	*UntypedActor
+	*AtLeastOnceDelivery
+
+	destination akka.ActorRef
+	settings    AtLeastOnceDeliverySettings
+	results     chan error
+}
+
+func (p *ReliableSenderActor) ReliableSenderActor(destination akka.ActorRef, settings AtLeastOnceDeliverySettings, results chan error) {
+	p.destination = destination
+	p.settings = settings
+	p.results = results
+}
+
+func (p *ReliableSenderActor) PreStart() (err error) {
+	p.AtLeastOnceDelivery = NewAtLeastOnceDelivery(p.Context(), p.settings)
+	return
+}
+
+func (p *ReliableSenderActor) Receive(message interface{}) (handled bool, err error) {
+	if p.ReceiveRedelivery(message) {
+		return true, nil
+	}
+
+	switch msg := message.(type) {
+	case string:
+		{
+			_, err := p.Deliver(p.destination, func(deliveryId int64) interface{} {
+				return &reliableMessage{DeliveryId: deliveryId, Payload: msg}
+			})
+			p.results <- err
+		}
+	case *reliableConfirm:
+		{
+			p.ConfirmDelivery(msg.DeliveryId)
+		}
+	}
+	return true, nil
+}
+
+// UnreliableReceiverActor drops the first deliveries it receives, the
+// later ones are confirmed
+type UnreliableReceiverActor struct {
+	*UntypedActor
+
+	drops    int
+	received chan *reliableMessage
+}
+
+func (p *UnreliableReceiverActor) UnreliableReceiverActor(drops int, received chan *reliableMessage) {
+	p.drops = drops
+	p.received = received
+}
+
+func (p *UnreliableReceiverActor) Receive(message interface{}) (handled bool, err error) {
+	if msg, ok := message.(*reliableMessage); ok {
+		p.received <- msg
+
+		if p.drops > 0 {
+			p.drops--
+			return true, nil
+		}
+
+		p.Sender().Tell(&reliableConfirm{DeliveryId: msg.DeliveryId}, p.Self())
+	}
+	return true, nil
+}
+
+func newReliablePair(t *testing.T, system *ActorSystemImpl, drops int, settings AtLeastOnceDeliverySettings) (sender akka.ActorRef, results chan error, received chan *reliableMessage) {
+	received = make(chan *reliableMessage, 20)
+	results = make(chan error, 10)
+
+	receiverProps, err := props.Create((*UnreliableReceiverActor)(nil), drops, received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	receiver, err := system.ActorOf(receiverProps, "receiver")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	senderProps, err := props.Create((*ReliableSenderActor)(nil), receiver, settings, results)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	if sender, err = system.ActorOf(senderProps, "sender"); err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	return
+}
+
+func TestAtLeastOnceDeliveryRedeliversUntilConfirmed(t *testing.T) {
+	system := newTestActorSystem(t, "AtLeastOnceDelivery")
+
+	sender, results, received := newReliablePair(t, system, 2, AtLeastOnceDeliverySettings{RedeliverInterval: 50 * time.Millisecond})
+
+	sender.Tell("order-1")
+
+	if err := <-results; err != nil {
+		t.Fatalf("deliver failure: %s", err.Error())
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case msg := <-received:
+			if msg.DeliveryId != 1 || msg.Payload != "order-1" {
+				t.Fatalf("expected delivery 1 of order-1, but got %#v", msg)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("delivery should be sent again until it is confirmed, but was received %d times", i)
+		}
+	}
+
+	select {
+	case msg := <-received:
+		t.Fatalf("confirmed delivery should not be sent again, but got %#v", msg)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestAtLeastOnceDeliveryWarnsWhenBufferIsFull(t *testing.T) {
+	system := newTestActorSystem(t, "AtLeastOnceDeliveryFull")
+	collector := newEventCollector(system, event.Warning{})
+
+	sender, results, _ := newReliablePair(t, system, 100, AtLeastOnceDeliverySettings{
+		RedeliverInterval:      time.Second,
+		MaxUnconfirmedMessages: 1,
+	})
+
+	sender.Tell("order-1")
+	sender.Tell("order-2")
+
+	if err := <-results; err != nil {
+		t.Fatalf("first delivery should be accepted, but got %s", err.Error())
+	}
+
+	if err := <-results; err != ErrMaxUnconfirmedMessagesExceeded {
+		t.Fatalf("delivery beyond the buffer should fail, but got %v", err)
+	}
+
+	select {
+	case e := <-collector.events:
+		if warning, ok := e.(*event.Warning); !ok || !strings.Contains(warning.Message().(string), "buffer is full") {
+			t.Fatalf("expected a redelivery buffer warning, but got %v", e)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("full redelivery buffer was not warned about")
+	}
+}
@@ -0,0 +1,151 @@
+package actor
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+)
+
+type BackoffOptions struct {
+	ChildProps akka.Props
+	ChildName  string
+
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// RandomFactor spreads the restart delays to delay * (1 ± RandomFactor),
+	// so children failing together are not restarted together
+	RandomFactor float64
+	// Rand is the source of the jitter, the global source is used if nil. A
+	// *rand.Rand is not safe for concurrent use, so it must not be shared
+	// between supervisors
+	Rand *rand.Rand
+
+	// ResetBackoff is how long the child has to run without stopping before
+	// the restart count is reset, it defaults to MinBackoff
+	ResetBackoff time.Duration
+}
+
+type backoffStartChild struct{}
+
+type backoffResetRestartCount struct {
+	current int
+}
+
+type BackoffSupervisor struct {
+	*UntypedActor
+
+	options BackoffOptions
+
+	child        akka.ActorRef
+	restartCount int
+}
+
+func NewBackoffSupervisorProps(options BackoffOptions) (supervisorProps akka.Props, err error) {
+	if options.ChildProps == nil || len(options.ChildName) == 0 {
+		err = ErrBadBackoffOptions
+		return
+	}
+
+	if options.MinBackoff <= 0 || options.MaxBackoff < options.MinBackoff || options.RandomFactor < 0 {
+		err = ErrBadBackoffOptions
+		return
+	}
+
+	if options.ResetBackoff <= 0 {
+		options.ResetBackoff = options.MinBackoff
+	}
+
+	return props.Create((*BackoffSupervisor)(nil), options)
+}
+
+func (p *BackoffSupervisor) BackoffSupervisor(options BackoffOptions) {
+	p.options = options
+}
+
+func (p *BackoffSupervisor) PreStart() (err error) {
+	return p.startChild()
+}
+
+func (p *BackoffSupervisor) SupervisorStrategy() akka.SupervisorStrategy {
+	return StoppingStrategy
+}
+
+func (p *BackoffSupervisor) Receive(message interface{}) (handled bool, err error) {
+	switch msg := message.(type) {
+	case *Terminated:
+		{
+			if p.child == nil || msg.Actor != p.child {
+				return false, nil
+			}
+
+			p.child = nil
+
+			delay := calculateBackoffDelay(p.restartCount, p.options.MinBackoff, p.options.MaxBackoff, p.options.RandomFactor, p.options.Rand)
+			p.Context().System().Scheduler().ScheduleTellOnce(delay, p.Self(), &backoffStartChild{}, p.Self(), nil)
+			p.restartCount++
+		}
+	case *backoffStartChild:
+		{
+			if p.child == nil {
+				err = p.startChild()
+			}
+		}
+	case *backoffResetRestartCount:
+		{
+			if msg.current == p.restartCount {
+				p.restartCount = 0
+			}
+		}
+	default:
+		if p.child == nil {
+			return false, nil
+		}
+		p.child.Tell(message, p.Sender())
+	}
+
+	return true, nil
+}
+
+func (p *BackoffSupervisor) startChild() (err error) {
+	var child akka.ActorRef
+	if child, err = p.Context().ActorOf(p.options.ChildProps, p.options.ChildName); err != nil {
+		return
+	}
+
+	if err = p.Context().Watch(child); err != nil {
+		return
+	}
+
+	p.child = child
+
+	p.Context().System().Scheduler().ScheduleTellOnce(p.options.ResetBackoff, p.Self(), &backoffResetRestartCount{p.restartCount}, p.Self(), nil)
+
+	return
+}
+
+// calculateBackoffDelay doubles minBackoff per restart, jitters it by
+// randomFactor and keeps the result between minBackoff and maxBackoff
+func calculateBackoffDelay(restartCount int, minBackoff, maxBackoff time.Duration, randomFactor float64, rnd *rand.Rand) time.Duration {
+	delay := float64(maxBackoff)
+	if restartCount < 30 {
+		delay = math.Min(delay, float64(minBackoff)*math.Pow(2, float64(restartCount)))
+	}
+
+	if randomFactor > 0 {
+		var r float64
+		if rnd != nil {
+			r = rnd.Float64()
+		} else {
+			r = rand.Float64()
+		}
+		delay *= 1 + (2*r-1)*randomFactor
+	}
+
+	delay = math.Max(float64(minBackoff), math.Min(float64(maxBackoff), delay))
+
+	return time.Duration(delay)
+}
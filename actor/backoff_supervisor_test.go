@@ -0,0 +1,157 @@
+package actor
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka/actor/props"
+)
+
+type BackoffChildActor struct {
+	*UntypedActor
+
+	starts chan time.Time
+}
+
+func (p *BackoffChildActor) BackoffChildActor(starts chan time.Time) {
+	p.starts = starts
+}
+
+func (p *BackoffChildActor) PreStart() (err error) {
+	p.starts <- time.Now()
+	return
+}
+
+func (p *BackoffChildActor) Receive(message interface{}) (handled bool, err error) {
+	return true, errors.New("backoff child failure")
+}
+
+func TestBackoffSupervisorIncreasingRestartDelays(t *testing.T) {
+	system := newTestActorSystem(t, "BackoffSupervisor")
+
+	starts := make(chan time.Time, 10)
+
+	childProps, err := props.Create((*BackoffChildActor)(nil), starts)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	supervisorProps, err := NewBackoffSupervisorProps(BackoffOptions{
+		ChildProps:   childProps,
+		ChildName:    "child",
+		MinBackoff:   50 * time.Millisecond,
+		MaxBackoff:   time.Second,
+		ResetBackoff: 10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("create backoff supervisor props failure: %s", err.Error())
+	}
+
+	supervisor, err := system.ActorOf(supervisorProps, "supervisor")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	waitStart := func() time.Time {
+		select {
+		case started := <-starts:
+			return started
+		case <-time.After(3 * time.Second):
+			t.Fatalf("child was not started")
+		}
+		return time.Time{}
+	}
+
+	last := waitStart()
+
+	var delays []time.Duration
+	for i := 0; i < 4; i++ {
+		supervisor.Tell("fail")
+
+		started := waitStart()
+		delays = append(delays, started.Sub(last))
+		last = started
+	}
+
+	if delays[0] < 50*time.Millisecond {
+		t.Fatalf("first restart should wait at least min backoff, but waited %s", delays[0])
+	}
+
+	for i := 1; i < len(delays); i++ {
+		if delays[i] <= delays[i-1] {
+			t.Fatalf("restart delays should increase, but got %v", delays)
+		}
+	}
+}
+
+func TestBackoffDelayIsCappedByMaxBackoff(t *testing.T) {
+	minBackoff := 100 * time.Millisecond
+	maxBackoff := time.Second
+
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+
+	for i, want := range expected {
+		if got := calculateBackoffDelay(i, minBackoff, maxBackoff, 0, nil); got != want {
+			t.Fatalf("delay of restart %d should be %s, but got %s", i, want, got)
+		}
+	}
+
+	if got := calculateBackoffDelay(100, minBackoff, maxBackoff, 0, nil); got != maxBackoff {
+		t.Fatalf("delay should be capped by max backoff, but got %s", got)
+	}
+}
+
+func TestBackoffJitterStaysWithinBounds(t *testing.T) {
+	minBackoff := 100 * time.Millisecond
+	maxBackoff := 2 * time.Second
+	randomFactor := 0.2
+
+	rnd := rand.New(rand.NewSource(42))
+
+	spread := map[time.Duration]bool{}
+	for i := 0; i < 200; i++ {
+		restartCount := i % 4
+		base := minBackoff << uint(restartCount)
+
+		delay := calculateBackoffDelay(restartCount, minBackoff, maxBackoff, randomFactor, rnd)
+
+		low := time.Duration(float64(base) * (1 - randomFactor))
+		if low < minBackoff {
+			low = minBackoff
+		}
+		high := time.Duration(float64(base) * (1 + randomFactor))
+
+		if delay < low || delay > high {
+			t.Fatalf("delay of restart %d should be within [%s, %s], but got %s", restartCount, low, high, delay)
+		}
+
+		if restartCount == 2 {
+			spread[delay] = true
+		}
+	}
+
+	if len(spread) < 2 {
+		t.Fatalf("jitter should spread the delays, but got %v", spread)
+	}
+
+	for i := 0; i < 100; i++ {
+		if delay := calculateBackoffDelay(10, minBackoff, maxBackoff, randomFactor, rnd); delay < time.Duration(float64(maxBackoff)*(1-randomFactor)) || delay > maxBackoff {
+			t.Fatalf("jittered delay should be clamped by max backoff, but got %s", delay)
+		}
+	}
+
+	first := calculateBackoffDelay(3, minBackoff, maxBackoff, randomFactor, rand.New(rand.NewSource(7)))
+	second := calculateBackoffDelay(3, minBackoff, maxBackoff, randomFactor, rand.New(rand.NewSource(7)))
+	if first != second {
+		t.Fatalf("the same seed should give the same delay, but got %s and %s", first, second)
+	}
+}
@@ -0,0 +1,167 @@
+package actor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/event"
+	"github.com/go-akka/configuration"
+)
+
+type pushBehavior struct{ name string }
+type replaceBehavior struct{ name string }
+type unbecomeBehavior struct{}
+
+// BecomingActor answers "who" with the name of its current behavior and
+// reports the result of every behavior change
+type BecomingActor struct {
+	*UntypedActor
+
+	replies chan interface{}
+}
+
+func (p *BecomingActor) BecomingActor(replies chan interface{}) {
+	p.replies = replies
+}
+
+func (p *BecomingActor) Receive(message interface{}) (handled bool, err error) {
+	return p.behave("initial", message), nil
+}
+
+func (p *BecomingActor) behave(name string, message interface{}) bool {
+	switch msg := message.(type) {
+	case *pushBehavior:
+		{
+			p.replies <- p.Context().Become(p.named(msg.name), false)
+		}
+	case *replaceBehavior:
+		{
+			p.replies <- p.Context().Become(p.named(msg.name), true)
+		}
+	case *unbecomeBehavior:
+		{
+			p.Context().Unbecome()
+			p.replies <- nil
+		}
+	case string:
+		{
+			p.replies <- name
+		}
+	}
+	return true
+}
+
+func (p *BecomingActor) named(name string) akka.ReceiveFunc {
+	return func(message interface{}) (bool, error) {
+		return p.behave(name, message), nil
+	}
+}
+
+func newBecomingActor(t *testing.T, maxDepth string) (system *ActorSystemImpl, ref akka.ActorRef, ask func(message interface{}) interface{}) {
+	config := strings.Replace(testConfig, "actor {", "actor {\n\t\tbehavior-stack-max-depth = "+maxDepth, 1)
+
+	system, err := NewActorSystem("BehaviorStack", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	replies := make(chan interface{}, 10)
+
+	becomingProps, err := props.Create((*BecomingActor)(nil), replies)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	if ref, err = system.ActorOf(becomingProps, "becoming"); err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	ask = func(message interface{}) interface{} {
+		ref.Tell(message)
+		select {
+		case reply := <-replies:
+			return reply
+		case <-time.After(3 * time.Second):
+			t.Fatalf("no reply to %v", message)
+		}
+		return nil
+	}
+
+	return
+}
+
+func TestBecomePushesAndReplacesBehaviors(t *testing.T) {
+	_, _, ask := newBecomingActor(t, "10")
+
+	ask(&pushBehavior{"first"})
+	ask(&pushBehavior{"second"})
+
+	if current := ask("who"); current != "second" {
+		t.Fatalf("pushed behavior should be current, but got %v", current)
+	}
+
+	ask(&replaceBehavior{"replaced"})
+	if current := ask("who"); current != "replaced" {
+		t.Fatalf("replacing behavior should be current, but got %v", current)
+	}
+
+	ask(&unbecomeBehavior{})
+	if current := ask("who"); current != "first" {
+		t.Fatalf("unbecome should go back to the behavior below the replaced one, but got %v", current)
+	}
+}
+
+func TestBecomeRefusesToGrowBeyondMaxDepth(t *testing.T) {
+	system, _, ask := newBecomingActor(t, "3")
+	collector := newEventCollector(system, event.Warning{})
+
+	for i := 0; i < 2; i++ {
+		if err := ask(&pushBehavior{"nested"}); err != nil {
+			t.Fatalf("push %d below the max depth should succeed, but got %v", i, err)
+		}
+	}
+
+	err, _ := ask(&pushBehavior{"overflow"}).(error)
+	if err == nil || !strings.HasPrefix(err.Error(), ErrBehaviorStackTooDeep.Error()) {
+		t.Fatalf("push at the max depth should fail with ErrBehaviorStackTooDeep, but got %v", err)
+	}
+
+	if current := ask("who"); current != "nested" {
+		t.Fatalf("refused behavior should not become current, but got %v", current)
+	}
+
+	if err := ask(&replaceBehavior{"replaced"}); err != nil {
+		t.Fatalf("replacing at the max depth should succeed, but got %v", err)
+	}
+
+	select {
+	case e := <-collector.events:
+		if warning := e.(*event.Warning); !strings.Contains(warning.Message().(string), ErrBehaviorStackTooDeep.Error()) {
+			t.Fatalf("warning should tell about the max depth, but got %v", warning.Message())
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("refused push should publish a warning")
+	}
+}
+
+func TestUnbecomeKeepsTheInitialBehavior(t *testing.T) {
+	_, _, ask := newBecomingActor(t, "10")
+
+	ask(&unbecomeBehavior{})
+	ask(&unbecomeBehavior{})
+
+	if current := ask("who"); current != "initial" {
+		t.Fatalf("unbecome on the initial behavior should be a no-op, but got %v", current)
+	}
+
+	ask(&pushBehavior{"pushed"})
+	ask(&unbecomeBehavior{})
+	ask(&unbecomeBehavior{})
+
+	if current := ask("who"); current != "initial" {
+		t.Fatalf("unbecome should stop at the initial behavior, but got %v", current)
+	}
+}
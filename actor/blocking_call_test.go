@@ -0,0 +1,84 @@
+package actor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/dispatch"
+	"github.com/go-akka/akka/event"
+	"github.com/go-akka/configuration"
+)
+
+type blockingActor struct {
+	*UntypedActor
+}
+
+func (p *blockingActor) Receive(message interface{}) (handled bool, err error) {
+	if delay, ok := message.(time.Duration); ok {
+		time.Sleep(delay)
+	}
+	p.Sender().Tell(message, p.Self())
+	return true, nil
+}
+
+func TestSlowHandlerIsWarnedAboutBlockingTheDispatcher(t *testing.T) {
+	config := strings.Replace(testConfig, "throughput = 5", `throughput = 5
+			blocking-call-warning-threshold = 50ms`, 1)
+
+	system, err := NewActorSystem("BlockingCall", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	collector := newEventCollector(system, event.Warning{})
+
+	blockingProps, err := props.Create((*blockingActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	blocking, err := system.ActorOf(blockingProps, "blocking")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	dedicated, err := system.ActorOf(blockingProps.WithDispatcher(dispatch.DefaultBlockingIODispatcherId), "dedicated")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	inbox := NewInbox(system)
+	defer inbox.Stop()
+
+	inbox.Send(blocking, "fast")
+	inbox.Send(dedicated, 100*time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if _, err = inbox.Receive(3 * time.Second); err != nil {
+			t.Fatalf("receive failure: %s", err.Error())
+		}
+	}
+
+	select {
+	case e := <-collector.events:
+		t.Fatalf("fast handler and the blocking-io dispatcher should not be warned, but got %v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	inbox.Send(blocking, 100*time.Millisecond)
+	if _, err = inbox.Receive(3 * time.Second); err != nil {
+		t.Fatalf("receive failure: %s", err.Error())
+	}
+
+	select {
+	case e := <-collector.events:
+		warning, ok := e.(*event.Warning)
+		if !ok || !strings.Contains(fmt.Sprint(warning.Message()), "blocking-call-warning-threshold") || warning.LogSource() != blocking.Path().String() {
+			t.Fatalf("slow handler should be warned about, but got %v", e)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("slow handler was not warned about")
+	}
+}
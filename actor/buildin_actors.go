@@ -13,77 +13,91 @@ func (p *EventStreamActor) Receive(message interface{}) (unhandled bool) {
 	return
 }
 
+// GuardianActor is the /user guardian, the actors created with
+// ActorSystem.ActorOf are its children and supervised by the strategy of
+// akka.actor.guardian-supervisor-strategy
 type GuardianActor struct {
-	*ActorBase
+	*UntypedActor
+
+	strategy akka.SupervisorStrategy
 }
 
-func (p *GuardianActor) Receive(message interface{}) (unhandled bool) {
+func (p *GuardianActor) GuardianActor(strategy akka.SupervisorStrategy) {
+	p.strategy = strategy
+}
 
+func (p *GuardianActor) Receive(message interface{}) (handled bool, err error) {
 	switch msg := message.(type) {
 	case *Terminated:
 		{
 			p.Context().StopChild(p.Self())
-			return
 		}
 	case *sysmsg.StopChild:
 		{
 			p.Context().StopChild(msg.Child())
 		}
 	default:
-		p.Context().System().DeadLetters().Tell(akka.NewDeadLetter(message, p.Sender(), p.Self()), p.Sender())
+		p.Context().System().(*ActorSystemImpl).deadLetter(message, p.Sender(), p.Self())
 	}
 
-	return
+	return true, nil
 }
 
-func (p *GuardianActor) PreStart() (err error) {
+func (p *GuardianActor) SupervisorStrategy() akka.SupervisorStrategy {
+	return newGuardianSupervisorStrategy(p.Context().System().(*ActorSystemImpl), p.strategy)
+}
+
+// PreRestart keeps the children, a guardian is never recreated without them
+func (p *GuardianActor) PreRestart(cause error, message interface{}) (err error) {
 	return
 }
 
+// SystemGuardianActor is the /system guardian, it watches the user guardian
+// and tells the termination hooks once it terminated. It is stopped by
+// Terminate after the loggers among its children are flushed
 type SystemGuardianActor struct {
 	*UntypedActor
 
 	userGuardian     akka.ActorRef
-	terminationHooks map[akka.ActorRef]bool
+	terminationHooks *akka.ActorRefSet
 }
 
-func (p *SystemGuardianActor) Receive(message interface{}) (unhandled bool) {
+func (p *SystemGuardianActor) SystemGuardianActor(userGuardian akka.ActorRef) {
+	p.userGuardian = userGuardian
+	p.terminationHooks = akka.NewActorRefSet()
+}
 
+func (p *SystemGuardianActor) PreStart() (err error) {
+	return p.Context().Watch(p.userGuardian)
+}
+
+func (p *SystemGuardianActor) Receive(message interface{}) (handled bool, err error) {
 	switch msg := message.(type) {
 	case *Terminated:
 		{
-			terminatedActor := msg.Actor
-			if p.userGuardian.CompareTo(terminatedActor) != 0 {
-				p.Context().Become(p.Terminating, true)
-
-				for terminationHook, _ := range p.terminationHooks {
-					terminationHook.Tell(akka.TerminationHook{}, akka.NoSender{})
+			if p.userGuardian.Equals(msg.Actor) {
+				for _, terminationHook := range p.terminationHooks.Refs() {
+					terminationHook.Tell(akka.TerminationHook{}, p.Self())
 				}
-
-				p.stopWhenAllTerminationHooksDone()
 			} else {
-				delete(p.terminationHooks, terminatedActor)
+				p.terminationHooks.Remove(msg.Actor)
 			}
-
-			return
 		}
 	case *sysmsg.StopChild:
 		{
 			p.Context().StopChild(msg.Child())
 		}
 	default:
-		p.Context().System().DeadLetters().Tell(akka.NewDeadLetter(message, p.Sender(), p.Self()), p.Sender())
+		return false, nil
 	}
 
-	return
+	return true, nil
 }
 
-func (p *SystemGuardianActor) Terminating(message interface{}) (unhandled bool, err error) {
-	return
+func (p *SystemGuardianActor) SupervisorStrategy() akka.SupervisorStrategy {
+	return DefaultSupervisorStrategy
 }
 
-func (p *SystemGuardianActor) stopWhenAllTerminationHooksDone() {
-	if len(p.terminationHooks) == 0 {
-		p.Context().StopChild(p.Self())
-	}
+func (p *SystemGuardianActor) PreRestart(cause error, message interface{}) (err error) {
+	return
 }
@@ -0,0 +1,64 @@
+package actor
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-akka/akka"
+)
+
+var (
+	_ akka.Cancelable = (*Cancelable)(nil)
+)
+
+type Cancelable struct {
+	cancelled int32
+
+	callbacks []func()
+	locker    sync.Mutex
+}
+
+func NewCancelable() *Cancelable {
+	return &Cancelable{}
+}
+
+func (p *Cancelable) IsCancellationRequested() bool {
+	return atomic.LoadInt32(&p.cancelled) == 1
+}
+
+func (p *Cancelable) CancelAfter(delay time.Duration) {
+	timer := time.AfterFunc(delay, func() {
+		p.Cancel(false)
+	})
+	p.register(func() { timer.Stop() })
+}
+
+func (p *Cancelable) Cancel(throwOnFirstException bool) (err error) {
+	if !atomic.CompareAndSwapInt32(&p.cancelled, 0, 1) {
+		return
+	}
+
+	p.locker.Lock()
+	callbacks := p.callbacks
+	p.callbacks = nil
+	p.locker.Unlock()
+
+	for _, fn := range callbacks {
+		fn()
+	}
+
+	return
+}
+
+func (p *Cancelable) register(fn func()) {
+	p.locker.Lock()
+	if !p.IsCancellationRequested() {
+		p.callbacks = append(p.callbacks, fn)
+		p.locker.Unlock()
+		return
+	}
+	p.locker.Unlock()
+
+	fn()
+}
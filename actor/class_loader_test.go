@@ -0,0 +1,82 @@
+package actor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/event"
+	"github.com/go-akka/akka/pkg/class_loader"
+	"github.com/go-akka/configuration"
+)
+
+var pluginLoggerStarted = make(chan struct{}, 1)
+
+type pluginLogger struct {
+}
+
+func (p *pluginLogger) Receive(context akka.ActorContext, message interface{}) (wasHandled bool, err error) {
+	if _, ok := message.(*event.InitializeLogger); ok {
+		pluginLoggerStarted <- struct{}{}
+		context.Sender().Tell(&event.LoggerInitialized{}, context.Self())
+	}
+	wasHandled = true
+	return
+}
+
+type pluginActor struct {
+	*UntypedActor
+}
+
+func (p *pluginActor) Receive(message interface{}) (handled bool, err error) {
+	p.Sender().Tell("plugin:"+message.(string), p.Self())
+	return true, nil
+}
+
+func TestPluginTypesAreLoadedFromThePerSystemClassLoader(t *testing.T) {
+	loader := class_loader.NewClassicClassLoader(AkkaClassLoader())
+	loader.Register((*pluginLogger)(nil), "akka.test.plugin-logger")
+	loader.Register((*pluginActor)(nil), "akka.test.plugin-actor")
+
+	if _, exist := class_loader.Default.ClassNameOf("akka.test.plugin-logger"); exist {
+		t.Fatalf("plugin types should not leak into the default class loader")
+	}
+
+	config := strings.Replace(testConfig, "loggers = []", `loggers = ["akka.test.plugin-logger"]`, 1)
+
+	system, err := NewActorSystemWithClassLoader("PluginClassLoader", loader, configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	select {
+	case <-pluginLoggerStarted:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("plugin logger was not started")
+	}
+
+	actorType, exist := system.ClassLoader().ClassNameOf("akka.test.plugin-actor")
+	if !exist {
+		t.Fatalf("plugin actor should be found in the system's class loader")
+	}
+
+	pluginProps, err := props.Create(actorType)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	plugin, err := system.ActorOf(pluginProps, "plugin")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	inbox := NewInbox(system)
+	defer inbox.Stop()
+
+	inbox.Send(plugin, "hello")
+	if msg, err := inbox.Receive(3 * time.Second); err != nil || msg != "plugin:hello" {
+		t.Fatalf("plugin actor should reply, but got %v, %v", msg, err)
+	}
+}
@@ -0,0 +1,152 @@
+package actor
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-akka/akka"
+)
+
+// DeadLetterActorRef publishes the messages sent to it as DeadLetter on the
+// event stream. Messages marked with DeadLetterSuppression are only counted,
+// and if publish-suppressed-dead-letters is on rolled up into a
+// SuppressedDeadLetter once every suppressed-dead-letters-summary-interval
+type DeadLetterActorRef struct {
+	*akka.MinimalActorRef
+
+	eventStream       akka.EventStream
+	publishSuppressed bool
+	summaryInterval   time.Duration
+
+	suppressed int64
+
+	// pending are the suppressed dead letters since the last summary, last
+	// is the latest of them, summary publishes them once it fires
+	pending       int64
+	last          akka.DeadLetter
+	summary       *time.Timer
+	flushed       bool
+	summaryLocker sync.Mutex
+}
+
+func NewDeadLetterActorRef(provider akka.ActorRefProvider, path akka.ActorPath, eventStream akka.EventStream) *DeadLetterActorRef {
+	return &DeadLetterActorRef{
+		MinimalActorRef:   akka.NewMinimalActorRef(path, provider),
+		eventStream:       eventStream,
+		publishSuppressed: provider.Settings().PublishSuppressedDeadLetters,
+		summaryInterval:   provider.Settings().SuppressedDeadLettersSummaryInterval,
+	}
+}
+
+func (p *DeadLetterActorRef) Tell(message interface{}, sender ...akka.ActorRef) (err error) {
+	var deadLetter akka.DeadLetter
+
+	switch msg := message.(type) {
+	case akka.DeadLetter:
+		{
+			deadLetter = msg
+		}
+	case *akka.DeadLetter:
+		{
+			deadLetter = *msg
+		}
+	default:
+		var from akka.ActorRef
+		if len(sender) > 0 {
+			from = sender[0]
+		}
+		deadLetter = akka.NewDeadLetter(message, from, p)
+	}
+
+	if _, ok := deadLetter.Message().(akka.DeadLetterSuppression); ok {
+		atomic.AddInt64(&p.suppressed, 1)
+		if p.publishSuppressed {
+			p.rollUp(deadLetter)
+		}
+		return
+	}
+
+	p.eventStream.Publish(deadLetter)
+
+	return
+}
+
+func (p *DeadLetterActorRef) SuppressedCount() int64 {
+	return atomic.LoadInt64(&p.suppressed)
+}
+
+// rollUp counts deadLetter into the next summary, the first one since the
+// last summary schedules it
+func (p *DeadLetterActorRef) rollUp(deadLetter akka.DeadLetter) {
+	if p.summaryInterval <= 0 {
+		p.eventStream.Publish(akka.NewSuppressedDeadLetter(deadLetter, 1))
+		return
+	}
+
+	p.summaryLocker.Lock()
+	defer p.summaryLocker.Unlock()
+
+	if p.flushed {
+		return
+	}
+
+	p.pending++
+	p.last = deadLetter
+
+	if p.pending == 1 {
+		p.summary = time.AfterFunc(p.summaryInterval, p.publishSummary)
+	}
+}
+
+func (p *DeadLetterActorRef) publishSummary() {
+	p.summaryLocker.Lock()
+	last, count := p.last, p.pending
+	p.last, p.pending, p.summary = akka.DeadLetter{}, 0, nil
+	p.summaryLocker.Unlock()
+
+	if count > 0 {
+		p.eventStream.Publish(akka.NewSuppressedDeadLetter(last, count))
+	}
+}
+
+// flushSummary stops the pending summary and publishes it right away, the
+// suppressed dead letters after it are only counted. It runs on termination
+// while the loggers are still there
+func (p *DeadLetterActorRef) flushSummary() {
+	p.summaryLocker.Lock()
+	p.flushed = true
+	if p.summary != nil {
+		p.summary.Stop()
+	}
+	p.summaryLocker.Unlock()
+
+	p.publishSummary()
+}
+
+// deadLetterQueue is the queue a closed mailbox is cleaned up into, every
+// message enqueued is handed to the dead letters of the system right away
+type deadLetterQueue struct {
+	system *ActorSystemImpl
+}
+
+func (p deadLetterQueue) Enqueue(receiver akka.ActorRef, envelope akka.Envelope) (err error) {
+	p.system.deadLetter(envelope.Message, envelope.Sender, receiver)
+	return
+}
+
+func (p deadLetterQueue) Dequeue() (envelope akka.Envelope, ok bool) {
+	return
+}
+
+func (p deadLetterQueue) CleanUp(owner akka.ActorRef, deadLetters akka.MessageQueue) (err error) {
+	return
+}
+
+func (p deadLetterQueue) NumberOfMessages() int {
+	return 0
+}
+
+func (p deadLetterQueue) HasMessages() bool {
+	return false
+}
@@ -0,0 +1,277 @@
+package actor
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/configuration"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+)
+
+type heartbeat struct{}
+
+func (p *heartbeat) DeadLetterSuppression() {}
+
+type eventCollector struct {
+	*akka.MinimalActorRef
+
+	events chan interface{}
+}
+
+func newEventCollector(system *ActorSystemImpl, classes ...interface{}) *eventCollector {
+	collector := &eventCollector{
+		MinimalActorRef: akka.NewMinimalActorRef(akka.NewRootActorPath(akka.NewAddress("akka", "test", "", 0), "/collector"), nil),
+		events:          make(chan interface{}, 10),
+	}
+
+	for _, class := range classes {
+		system.EventStream().Subscribe(collector, reflect.TypeOf(class))
+	}
+
+	return collector
+}
+
+func (p *eventCollector) Tell(message interface{}, sender ...akka.ActorRef) (err error) {
+	p.events <- message
+	return
+}
+
+func TestSuppressedMessagesDoNotPublishDeadLetters(t *testing.T) {
+	system := newTestActorSystem(t, "DeadLetterSuppression")
+	collector := newEventCollector(system, akka.DeadLetter{}, akka.SuppressedDeadLetter{})
+
+	deadLetters := NewDeadLetterActorRef(system.Provider(), system.Provider().RootPath().Append("deadLetters"), system.EventStream())
+
+	deadLetters.Tell(&heartbeat{})
+	deadLetters.Tell("lost")
+
+	select {
+	case e := <-collector.events:
+		{
+			deadLetter, ok := e.(akka.DeadLetter)
+			if !ok || deadLetter.Message() != "lost" {
+				t.Fatalf("only the unsuppressed message should be published as dead letter, but got %#v", e)
+			}
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("dead letter was not published")
+	}
+
+	select {
+	case e := <-collector.events:
+		t.Fatalf("suppressed message should not be published, but got %#v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if n := deadLetters.SuppressedCount(); n != 1 {
+		t.Fatalf("suppressed dead letters should be counted, expected 1, but got %d", n)
+	}
+}
+
+func TestSuppressedDeadLettersAreRolledUpWhenEnabled(t *testing.T) {
+	config := strings.Replace(testConfig, "akka {", "akka {\n\tpublish-suppressed-dead-letters = on\n\tsuppressed-dead-letters-summary-interval = 100ms\n", 1)
+
+	system, err := NewActorSystem("SuppressedDeadLetterPublished", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	collector := newEventCollector(system, akka.DeadLetter{}, akka.SuppressedDeadLetter{})
+
+	deadLetters := NewDeadLetterActorRef(system.Provider(), system.Provider().RootPath().Append("deadLetters"), system.EventStream())
+
+	for _, rollUp := range []int64{3, 1} {
+		for i := int64(0); i < rollUp; i++ {
+			deadLetters.Tell(&heartbeat{})
+		}
+
+		select {
+		case e := <-collector.events:
+			{
+				suppressed, ok := e.(akka.SuppressedDeadLetter)
+				if !ok {
+					t.Fatalf("expected SuppressedDeadLetter, but got %#v", e)
+				}
+
+				if suppressed.Count() != rollUp {
+					t.Fatalf("summary should roll up %d suppressed dead letters, but got %d", rollUp, suppressed.Count())
+				}
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("summary of %d suppressed dead letters was not published", rollUp)
+		}
+
+		select {
+		case e := <-collector.events:
+			t.Fatalf("suppressed dead letters should be published in one summary, but also got %#v", e)
+		case <-time.After(150 * time.Millisecond):
+		}
+	}
+}
+
+func TestPendingSuppressedDeadLettersAreFlushedOnTermination(t *testing.T) {
+	config := strings.Replace(testConfig, "akka {", "akka {\n\tpublish-suppressed-dead-letters = on\n\tsuppressed-dead-letters-summary-interval = 300ms\n", 1)
+
+	system, err := NewActorSystem("SuppressedDeadLetterFlushed", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	collector := newEventCollector(system, akka.SuppressedDeadLetter{})
+
+	system.DeadLetters().Tell(&heartbeat{})
+	system.DeadLetters().Tell(&heartbeat{})
+
+	system.Terminate()
+
+	select {
+	case e := <-collector.events:
+		if suppressed, ok := e.(akka.SuppressedDeadLetter); !ok || suppressed.Count() != 2 {
+			t.Fatalf("termination should flush a summary of 2 suppressed dead letters, but got %#v", e)
+		}
+	default:
+		t.Fatalf("the pending summary was not flushed on termination")
+	}
+
+	system.DeadLetters().Tell(&heartbeat{})
+
+	select {
+	case e := <-collector.events:
+		t.Fatalf("no summary should be published after termination, but got %#v", e)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+func TestSendMessageToTerminatedCellGoesToDeadLetters(t *testing.T) {
+	system := newTestActorSystem(t, "TerminatedCell")
+	collector := newEventCollector(system, akka.DeadLetter{})
+
+	echoProps, err := props.Create((*echoActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	echo, err := system.ActorOf(echoProps, "echo")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	inbox := NewInbox(system)
+	defer inbox.Stop()
+
+	if err = inbox.Watch(echo); err != nil {
+		t.Fatalf("watch failure: %s", err.Error())
+	}
+
+	inbox.Send(echo, &PoisonPill{})
+
+	if _, err = inbox.Receive(3 * time.Second); err != nil {
+		t.Fatalf("receive failure: %s", err.Error())
+	}
+
+	err = echo.(*LocalActorRef).Cell().SendMessage(akka.NewEnvelope("late", inbox.Self()))
+	if !errors.Is(err, ErrActorTerminated) {
+		t.Fatalf("sending to a terminated cell should fail with ErrActorTerminated, but got %v", err)
+	}
+
+	select {
+	case e := <-collector.events:
+		if deadLetter := e.(akka.DeadLetter); deadLetter.Message() != "late" || deadLetter.Recipient() != echo {
+			t.Fatalf("the late message should be dead-lettered for the echo actor, but got %v", deadLetter)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("the late message was not dead-lettered")
+	}
+}
+
+func TestSystemDeadLettersPublishesDeadLetters(t *testing.T) {
+	system := newTestActorSystem(t, "SystemDeadLetters")
+	collector := newEventCollector(system, akka.DeadLetter{})
+
+	deadLetters := system.DeadLetters()
+	if deadLetters == nil || deadLetters.Path().String() != "akka://SystemDeadLetters/deadLetters" {
+		t.Fatalf("system should have its dead letters at /deadLetters, but got %v", deadLetters)
+	}
+
+	if system.Provider().DeadLetters() != deadLetters {
+		t.Fatalf("system and provider should share the dead letters")
+	}
+
+	inbox := NewInbox(system)
+	defer inbox.Stop()
+
+	if err := deadLetters.Tell("lost", inbox.Self()); err != nil {
+		t.Fatalf("tell failure: %s", err.Error())
+	}
+
+	select {
+	case e := <-collector.events:
+		{
+			deadLetter, ok := e.(akka.DeadLetter)
+			if !ok || deadLetter.Message() != "lost" || deadLetter.Sender() != inbox.Self() || deadLetter.Recipient() != deadLetters {
+				t.Fatalf("expected a dead letter of the message, but got %#v", e)
+			}
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("message to the dead letters was not published")
+	}
+}
+
+func TestTellWithResultReportsTheDeadLetterOfAStoppedActor(t *testing.T) {
+	system := newTestActorSystem(t, "TellWithResult")
+	collector := newEventCollector(system, akka.DeadLetter{})
+
+	echoProps, err := props.Create((*echoActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	echo, err := system.ActorOf(echoProps, "echo")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	inbox := NewInbox(system)
+	defer inbox.Stop()
+
+	teller := echo.(akka.CanTellWithResult)
+	if err = teller.TellWithResult("alive", inbox.Self()); err != nil {
+		t.Fatalf("tell to a running actor should succeed, but got %s", err.Error())
+	}
+
+	if reply, err := inbox.Receive(3 * time.Second); err != nil || reply != "alive" {
+		t.Fatalf("running actor should reply, but got %v, %v", reply, err)
+	}
+
+	inbox.Watch(echo)
+	echo.Tell(&PoisonPill{})
+
+	if _, err = inbox.Receive(3 * time.Second); err != nil {
+		t.Fatalf("actor was not terminated: %s", err.Error())
+	}
+
+	err = teller.TellWithResult("late", inbox.Self())
+	if !errors.Is(err, ErrActorTerminated) {
+		t.Fatalf("tell to a stopped actor should fail with ErrActorTerminated, but got %v", err)
+	}
+
+	if err = echo.Tell("later"); err != nil {
+		t.Fatalf("plain tell should keep dropping the error, but got %s", err.Error())
+	}
+
+	for _, expected := range []string{"late", "later"} {
+		select {
+		case e := <-collector.events:
+			if deadLetter := e.(akka.DeadLetter); deadLetter.Message() != expected {
+				t.Fatalf("expected a dead letter of %s, but got %v", expected, deadLetter.Message())
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("%s was not dead-lettered", expected)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package actor
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/event"
+)
+
+type deadLetterSummaryTick struct{}
+
+// DeadLetterListener is the /system/deadLetterListener actor, it logs the
+// first max dead letters one by one and afterwards only how many were
+// suppressed, once every summaryInterval
+type DeadLetterListener struct {
+	*UntypedActor
+
+	max             int
+	summaryInterval time.Duration
+
+	logged     int
+	suppressed int
+}
+
+func (p *DeadLetterListener) DeadLetterListener(max int, summaryInterval time.Duration) {
+	p.max = max
+	p.summaryInterval = summaryInterval
+}
+
+func (p *DeadLetterListener) PostStop() (err error) {
+	p.Context().System().EventStream().Unsubscribe(p.Self())
+	return
+}
+
+func (p *DeadLetterListener) Receive(message interface{}) (handled bool, err error) {
+	switch msg := message.(type) {
+	case akka.DeadLetter:
+		{
+			if p.logged < p.max {
+				p.logged++
+				p.log(fmt.Sprintf("Message [%T] from %v to %v was not delivered. [%d] dead letters encountered", msg.Message(), msg.Sender(), msg.Recipient(), p.logged))
+
+				if p.logged == p.max {
+					p.log(fmt.Sprintf("the next dead letters are only counted and summarized every %s, see akka.log-dead-letters", p.summaryInterval))
+					p.Context().Schedule(p.summaryInterval, &deadLetterSummaryTick{})
+				}
+				return true, nil
+			}
+			p.suppressed++
+		}
+	case *deadLetterSummaryTick:
+		{
+			if p.suppressed > 0 {
+				p.log(fmt.Sprintf("suppressed %d dead letters", p.suppressed))
+				p.suppressed = 0
+			}
+			p.Context().Schedule(p.summaryInterval, &deadLetterSummaryTick{})
+		}
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (p *DeadLetterListener) log(message string) {
+	p.Context().System().EventStream().Publish(event.NewInfoEvent(p.Self().Path().String(), p, message))
+}
+
+func (p *ActorSystemImpl) startDeadLetterListener(max int, summaryInterval time.Duration) (err error) {
+	listenerProps, err := props.Create((*DeadLetterListener)(nil), max, summaryInterval)
+	if err != nil {
+		return
+	}
+
+	listener, err := p.SystemActorOf(listenerProps, "deadLetterListener")
+	if err != nil {
+		return
+	}
+
+	// subscribed right away, not in PreStart, so no dead letter of the
+	// starting system is missed
+	p.eventStream.Subscribe(listener, reflect.TypeOf(akka.DeadLetter{}))
+
+	p.RegisterOnTermination(func() {
+		listener.(akka.InternalActorRef).Stop()
+	})
+
+	return
+}
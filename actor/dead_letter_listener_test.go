@@ -0,0 +1,51 @@
+package actor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka/event"
+	"github.com/go-akka/configuration"
+)
+
+func TestDeadLetterListenerSummarizesBeyondTheLimit(t *testing.T) {
+	config := strings.Replace(testConfig, "actor {", "log-dead-letters = 3\n\tlog-dead-letters-summary-interval = 50ms\n\tactor {", 1)
+
+	system, err := NewActorSystem("DeadLetterListener", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	collector := newEventCollector(system, event.Info{})
+
+	for i := 0; i < 7; i++ {
+		system.deadLetter(fmt.Sprintf("lost %d", i), nil, system.Guardian())
+	}
+
+	var logged []string
+	for !strings.Contains(strings.Join(logged, "\n"), "suppressed") {
+		select {
+		case e := <-collector.events:
+			logged = append(logged, e.(*event.Info).Message().(string))
+		case <-time.After(3 * time.Second):
+			t.Fatalf("dead letters beyond the limit should be summarized, but got %v", logged)
+		}
+	}
+
+	var verbatim int
+	for _, message := range logged {
+		if strings.HasPrefix(message, "Message [string]") {
+			verbatim++
+		}
+	}
+
+	if verbatim != 3 {
+		t.Fatalf("the first 3 dead letters should be logged one by one, but got %v", logged)
+	}
+
+	if summary := logged[len(logged)-1]; summary != "suppressed 4 dead letters" {
+		t.Fatalf("the summary should count the 4 other dead letters, but got %q", summary)
+	}
+}
@@ -0,0 +1,136 @@
+package actor
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-akka/akka"
+)
+
+// deadlockDetector periodically samples the dispatcher metrics, when no mailbox
+// is running but messages stay queued without any progress for longer than
+// threshold it logs a dump of the actor tree and the mailbox states
+type deadlockDetector struct {
+	system    *ActorSystemImpl
+	interval  time.Duration
+	threshold time.Duration
+
+	lastProcessed int64
+	stalledSince  time.Time
+	reported      bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func newDeadlockDetector(system *ActorSystemImpl, interval, threshold time.Duration) *deadlockDetector {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	return &deadlockDetector{
+		system:    system,
+		interval:  interval,
+		threshold: threshold,
+		stop:      make(chan struct{}),
+	}
+}
+
+func (p *deadlockDetector) Start() {
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case now := <-ticker.C:
+				p.check(now)
+			}
+		}
+	}()
+}
+
+func (p *deadlockDetector) Stop() {
+	p.stopOnce.Do(func() { close(p.stop) })
+}
+
+func (p *deadlockDetector) check(now time.Time) {
+	metrics := p.system.SystemMetrics()
+
+	stalled := metrics.RunningMailboxes == 0 &&
+		metrics.QueuedMessages > 0 &&
+		metrics.ProcessedMessages == p.lastProcessed
+
+	p.lastProcessed = metrics.ProcessedMessages
+
+	if !stalled {
+		p.stalledSince = time.Time{}
+		p.reported = false
+		return
+	}
+
+	if p.stalledSince.IsZero() {
+		p.stalledSince = now
+	}
+
+	if p.reported || now.Sub(p.stalledSince) < p.threshold {
+		return
+	}
+
+	p.reported = true
+
+	p.system.Log().Warning("Possible deadlock: all dispatchers idle with %d queued messages for %s\n%s",
+		metrics.QueuedMessages, now.Sub(p.stalledSince), p.system.DumpActorTree())
+}
+
+// DumpActorTree renders the actor hierarchy below the root guardian together
+// with the state of each actor's mailbox
+func (p *ActorSystemImpl) DumpActorTree() string {
+	buf := &bytes.Buffer{}
+
+	root, ok := p.provider.RootGuardian().(*LocalActorRef)
+	if !ok {
+		return buf.String()
+	}
+
+	dumpActor(buf, root, 0)
+
+	return buf.String()
+}
+
+func dumpActor(buf *bytes.Buffer, ref *LocalActorRef, depth int) {
+	cell := ref.Cell()
+	mailbox := cell.Mailbox()
+
+	fmt.Fprintf(buf, "%s%s", strings.Repeat("  ", depth), ref.Path())
+
+	if mailbox != nil {
+		fmt.Fprintf(buf, " [mailbox: %s, messages: %d, system-messages: %v, processed: %d]",
+			mailboxState(mailbox), mailbox.NumberOfMessages(), mailbox.HasSystemMessages(), mailbox.ProcessedMessages())
+	}
+
+	buf.WriteString("\n")
+
+	for _, child := range cell.Children() {
+		if childRef, ok := child.(*LocalActorRef); ok {
+			dumpActor(buf, childRef, depth+1)
+		}
+	}
+}
+
+func mailboxState(mailbox akka.Mailbox) string {
+	if mailbox.IsClosed() {
+		return "closed"
+	}
+
+	if mailbox.HasMessages() || mailbox.HasSystemMessages() {
+		return "pending"
+	}
+
+	return "idle"
+}
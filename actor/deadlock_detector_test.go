@@ -0,0 +1,89 @@
+package actor
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/event"
+	"github.com/go-akka/configuration"
+)
+
+type warningCollector struct {
+	*akka.MinimalActorRef
+
+	warnings chan string
+}
+
+func (p *warningCollector) Tell(message interface{}, sender ...akka.ActorRef) (err error) {
+	if warning, ok := message.(*event.Warning); ok {
+		p.warnings <- fmt.Sprint(warning.Message())
+	}
+	return
+}
+
+func TestDeadlockDetectionDumpsStalledMailboxes(t *testing.T) {
+	// the dump is a warning, so the level must let it through
+	config := strings.Replace(testConfig, `loglevel = "ERROR"`, `loglevel = "WARN"`, 1)
+	config = strings.Replace(config, "actor {", `actor {
+		debug {
+			deadlock-detection = on
+			deadlock-detection-interval = 20ms
+			deadlock-detection-threshold = 100ms
+		}
+`, 1)
+
+	system, err := NewActorSystem("DeadlockDetection", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+	defer system.Terminate()
+
+	collector := &warningCollector{
+		MinimalActorRef: akka.NewMinimalActorRef(akka.NewRootActorPath(akka.NewAddress("akka", "test", "", 0), "/collector"), nil),
+		warnings:        make(chan string, 10),
+	}
+	system.EventStream().Subscribe(collector, reflect.TypeOf((*event.Warning)(nil)).Elem())
+
+	wg := &sync.WaitGroup{}
+
+	stalledProps, err := props.Create((*CountingActor)(nil), wg)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(stalledProps, "stalled")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	wg.Add(1)
+	ref.Tell("started")
+	wg.Wait()
+
+	for system.SystemMetrics().RunningMailboxes > 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	wg.Add(1)
+
+	// enqueue without registering the mailbox for execution, so the message
+	// stays pending while the dispatcher is idle
+	ref.(*LocalActorRef).Cell().Mailbox().Enqueue(ref, akka.Envelope{Message: "stuck", Sender: ref})
+
+	select {
+	case dump := <-collector.warnings:
+		{
+			if !strings.Contains(dump, "Possible deadlock") || !strings.Contains(dump, "stalled") || !strings.Contains(dump, "messages: 1") {
+				t.Fatalf("diagnostic dump should describe the stalled mailbox, but got:\n%s", dump)
+			}
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("no diagnostic dump was produced for the stalled mailbox")
+	}
+}
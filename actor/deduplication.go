@@ -0,0 +1,105 @@
+package actor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/event"
+)
+
+const (
+	// DeduplicationIdHeader is the envelope header carrying the id of a
+	// message, the messages without one are never duplicates. It is kept by
+	// Forward but never propagated by Context().Tell
+	DeduplicationIdHeader = "dedup-id"
+
+	DefaultDeduplicationWindow = time.Minute
+	DefaultDeduplicationMaxIds = 10000
+)
+
+type DeduplicationSettings struct {
+	// Window is how long an id is remembered after it was first seen
+	Window time.Duration
+	// MaxIds caps the ids remembered, the oldest are forgotten first
+	MaxIds int
+}
+
+type seenId struct {
+	id string
+	at time.Time
+}
+
+// Deduplication drops the messages whose DeduplicationIdHeader was seen
+// within the window, it is meant to be embedded by an actor and created with
+// its context in PreStart. Every message has to be given to ReceiveDuplicate
+// first, it is not safe to use outside of the actor
+type Deduplication struct {
+	context  akka.ActorContext
+	settings DeduplicationSettings
+
+	seen map[string]bool
+	// order holds the seen ids oldest first, so they are forgotten in the
+	// order they were remembered
+	order []seenId
+}
+
+func NewDeduplication(context akka.ActorContext, settings DeduplicationSettings) *Deduplication {
+	if settings.Window <= 0 {
+		settings.Window = DefaultDeduplicationWindow
+	}
+
+	if settings.MaxIds <= 0 {
+		settings.MaxIds = DefaultDeduplicationMaxIds
+	}
+
+	return &Deduplication{
+		context:  context,
+		settings: settings,
+		seen:     make(map[string]bool),
+	}
+}
+
+// ReceiveDuplicate returns true for a message whose id was seen within the
+// window, it is dropped with a debug event. It returns false for all other
+// messages and remembers their id
+func (p *Deduplication) ReceiveDuplicate(message interface{}) bool {
+	id := p.context.Headers().Get(DeduplicationIdHeader)
+	if len(id) == 0 {
+		return false
+	}
+
+	now := time.Now()
+	p.forget(now.Add(-p.settings.Window))
+
+	if p.seen[id] {
+		p.context.System().EventStream().Publish(event.NewDebugEvent(p.context.Self().Path().String(), p, fmt.Sprintf("dropped duplicate %s of message %T", id, message)))
+		return true
+	}
+
+	if len(p.order) >= p.settings.MaxIds {
+		p.forgetOldest()
+	}
+
+	p.seen[id] = true
+	p.order = append(p.order, seenId{id: id, at: now})
+
+	return false
+}
+
+// NumberOfSeenIds are the ids remembered
+func (p *Deduplication) NumberOfSeenIds() int {
+	return len(p.order)
+}
+
+func (p *Deduplication) forget(before time.Time) {
+	for len(p.order) > 0 && p.order[0].at.Before(before) {
+		p.forgetOldest()
+	}
+}
+
+func (p *Deduplication) forgetOldest() {
+	delete(p.seen, p.order[0].id)
+	p.order[0] = seenId{}
+	p.order = p.order[1:]
+}
@@ -0,0 +1,171 @@
+package actor
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/event"
+	"github.com/go-akka/configuration"
+)
+
+type IdempotentActor struct {
+	*UntypedActor
+	*Deduplication
+
+	settings DeduplicationSettings
+	received chan interface{}
+}
+
+func (p *IdempotentActor) IdempotentActor(settings DeduplicationSettings, received chan interface{}) {
+	p.settings = settings
+	p.received = received
+}
+
+func (p *IdempotentActor) PreStart() (err error) {
+	p.Deduplication = NewDeduplication(p.Context(), p.settings)
+	return
+}
+
+func (p *IdempotentActor) Receive(message interface{}) (handled bool, err error) {
+	if p.ReceiveDuplicate(message) {
+		return true, nil
+	}
+
+	p.received <- message
+	return true, nil
+}
+
+func newIdempotentActor(t *testing.T, system *ActorSystemImpl, name string, settings DeduplicationSettings) (ref akka.ActorRef, received chan interface{}) {
+	received = make(chan interface{}, 20)
+
+	idempotentProps, err := props.Create((*IdempotentActor)(nil), settings, received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	if ref, err = system.ActorOf(idempotentProps, name); err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	return
+}
+
+func withId(message interface{}, id string) interface{} {
+	return akka.WithHeaders(message, akka.Headers{DeduplicationIdHeader: id})
+}
+
+func receiveAll(received chan interface{}) (messages []interface{}) {
+	for {
+		select {
+		case msg := <-received:
+			messages = append(messages, msg)
+		case <-time.After(100 * time.Millisecond):
+			return
+		}
+	}
+}
+
+// FanOutActor tells two messages to next for every message it receives
+type FanOutActor struct {
+	*UntypedActor
+
+	next akka.ActorRef
+}
+
+func (p *FanOutActor) FanOutActor(next akka.ActorRef) {
+	p.next = next
+}
+
+func (p *FanOutActor) Receive(message interface{}) (handled bool, err error) {
+	if err = p.Context().Tell(p.next, fmt.Sprintf("%v 1", message)); err != nil {
+		return true, err
+	}
+	return true, p.Context().Tell(p.next, fmt.Sprintf("%v 2", message))
+}
+
+func TestMessagesToldByAHandlerDoNotShareItsId(t *testing.T) {
+	config := strings.Replace(testConfig, "actor {", `actor {
+		propagated-headers = ["trace-id", "dedup-id"]`, 1)
+
+	system, err := NewActorSystem("DeduplicationFanOut", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	ref, received := newIdempotentActor(t, system, "idempotent", DeduplicationSettings{})
+
+	fanOutProps, err := props.Create((*FanOutActor)(nil), ref)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	fanOut, err := system.ActorOf(fanOutProps, "fanout")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	fanOut.Tell(withId("order", "1"))
+
+	if messages := receiveAll(received); !reflect.DeepEqual(messages, []interface{}{"order 1", "order 2"}) {
+		t.Fatalf("both messages told by the handler should arrive, but got %v", messages)
+	}
+}
+
+func TestDuplicateIdsAreDeliveredOnce(t *testing.T) {
+	system := newTestActorSystem(t, "Deduplication")
+	collector := newEventCollector(system, event.Debug{})
+
+	ref, received := newIdempotentActor(t, system, "idempotent", DeduplicationSettings{})
+
+	ref.Tell(withId("first", "1"))
+	ref.Tell(withId("first again", "1"))
+	ref.Tell(withId("second", "2"))
+	ref.Tell("no id")
+	ref.Tell("no id")
+	ref.Tell(withId("first once more", "1"))
+
+	if messages := receiveAll(received); !reflect.DeepEqual(messages, []interface{}{"first", "second", "no id", "no id"}) {
+		t.Fatalf("duplicates should be dropped, but got %v", messages)
+	}
+
+	dropped := 0
+	for len(collector.events) > 0 {
+		if debug, ok := (<-collector.events).(*event.Debug); ok && strings.Contains(fmt.Sprint(debug.Message()), "duplicate 1") {
+			dropped++
+		}
+	}
+
+	if dropped != 2 {
+		t.Fatalf("every dropped duplicate should be logged, but got %d debug events", dropped)
+	}
+}
+
+func TestDeduplicationWindowIsBounded(t *testing.T) {
+	system := newTestActorSystem(t, "DeduplicationWindow")
+
+	counted, received := newIdempotentActor(t, system, "counted", DeduplicationSettings{MaxIds: 2})
+
+	for _, id := range []string{"a", "b", "c", "a", "c"} {
+		counted.Tell(withId(id, id))
+	}
+
+	if messages := receiveAll(received); !reflect.DeepEqual(messages, []interface{}{"a", "b", "c", "a"}) {
+		t.Fatalf("the oldest id should be forgotten beyond MaxIds, but got %v", messages)
+	}
+
+	timed, received := newIdempotentActor(t, system, "timed", DeduplicationSettings{Window: 50 * time.Millisecond})
+
+	timed.Tell(withId("a", "a"))
+	timed.Tell(withId("a", "a"))
+	time.Sleep(100 * time.Millisecond)
+	timed.Tell(withId("a", "a"))
+
+	if messages := receiveAll(received); !reflect.DeepEqual(messages, []interface{}{"a", "a"}) {
+		t.Fatalf("an id should be forgotten after the window, but got %v", messages)
+	}
+}
@@ -0,0 +1,90 @@
+package actor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/event"
+)
+
+type orderPlaced struct {
+	Id string
+}
+
+type DiagnosticLoggingActor struct {
+	*UntypedActor
+
+	log *event.DiagnosticLoggingAdapter
+}
+
+func (p *DiagnosticLoggingActor) PreStart() (err error) {
+	p.log = event.Logging.GetDiagnosticLogger(p.Context(), true)
+	return
+}
+
+func (p *DiagnosticLoggingActor) Receive(message interface{}) (handled bool, err error) {
+	if order, ok := message.(*orderPlaced); ok {
+		p.log.SetMDC(akka.Headers{"orderId": order.Id})
+		defer p.log.ClearMDC()
+	}
+
+	p.log.Error(errors.New("rejected"), "rejected %v", message)
+	return true, nil
+}
+
+func TestDiagnosticLoggerTagsEventsWithPathAndMessageType(t *testing.T) {
+	system := newTestActorSystem(t, "DiagnosticLogging")
+	collector := newEventCollector(system, event.Error{})
+
+	actorProps, err := props.Create((*DiagnosticLoggingActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(actorProps, "orders")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	ref.Tell(akka.WithHeaders(&orderPlaced{Id: "42"}, akka.Headers{"traceId": "t-1"}))
+
+	var logged *event.Error
+	select {
+	case e := <-collector.events:
+		logged = e.(*event.Error)
+	case <-time.After(3 * time.Second):
+		t.Fatalf("diagnostic logger did not publish an event")
+	}
+
+	mdc := logged.MDC()
+	if mdc.Get(event.MDCActorPath) != ref.Path().String() {
+		t.Fatalf("event should be tagged with the actor path %s, but got %v", ref.Path(), mdc)
+	}
+
+	if mdc.Get(event.MDCMessageType) != "*actor.orderPlaced" {
+		t.Fatalf("event should be tagged with the message type, but got %v", mdc)
+	}
+
+	if mdc.Get("traceId") != "t-1" || mdc.Get("orderId") != "42" {
+		t.Fatalf("event should carry the message headers and the actor MDC, but got %v", mdc)
+	}
+
+	if line := logged.String(); !strings.Contains(line, event.MDCMessageType+"=*actor.orderPlaced") {
+		t.Fatalf("log line should render the MDC, but got %s", line)
+	}
+
+	ref.Tell("plain")
+
+	select {
+	case e := <-collector.events:
+		if mdc := e.(*event.Error).MDC(); mdc.Get("orderId") != "" || mdc.Get(event.MDCMessageType) != "string" {
+			t.Fatalf("cleared MDC should not leak into the next message, but got %v", mdc)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("diagnostic logger did not publish an event")
+	}
+}
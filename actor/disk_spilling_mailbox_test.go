@@ -0,0 +1,112 @@
+package actor
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/dispatch"
+	"github.com/go-akka/akka/pkg/class_loader"
+	"github.com/go-akka/akka/serialization"
+	"github.com/go-akka/configuration"
+)
+
+func init() {
+	class_loader.Default.Register((*unreadableSerializer)(nil), "akka.test.unreadable-serializer")
+}
+
+// unreadableSerializer writes JSON but never reads it back
+type unreadableSerializer struct {
+	serialization.JSONSerializer
+}
+
+func (p *unreadableSerializer) FromBinaryWithType(data []byte, typ reflect.Type) (v interface{}, err error) {
+	return nil, errors.New("unreadable")
+}
+
+// GatedActor reports every message, it waits for gate on the first one
+type GatedActor struct {
+	*UntypedActor
+
+	gate     chan struct{}
+	received chan interface{}
+}
+
+func (p *GatedActor) GatedActor(gate chan struct{}, received chan interface{}) {
+	p.gate = gate
+	p.received = received
+}
+
+func (p *GatedActor) Receive(message interface{}) (handled bool, err error) {
+	p.received <- message
+	if message == "first" {
+		<-p.gate
+	}
+	return true, nil
+}
+
+func TestSpilledMessagesThatCanNotBeReadBackAreDeadLettered(t *testing.T) {
+	config := strings.Replace(testConfig, "actor {", `actor {
+		spilling-mailbox {
+			mailbox-type = "akka.dispatch.disk-spilling-mailbox"
+			in-memory-limit = 1
+		}
+
+		serializers {
+			unreadable = "akka.test.unreadable-serializer"
+		}
+		default-serializer = unreadable
+`, 1)
+
+	system, err := NewActorSystem("SpillFailure", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	collector := newEventCollector(system, akka.DeadLetter{})
+
+	gate := make(chan struct{})
+	received := make(chan interface{}, 10)
+
+	gatedProps, err := props.Create((*GatedActor)(nil), gate, received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(gatedProps.WithMailbox("akka.actor.spilling-mailbox"), "gated")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	ref.Tell("first")
+	expectReceived(t, received, "first")
+
+	// second stays in memory, third is spilled
+	ref.Tell("second")
+	ref.Tell("third")
+	close(gate)
+
+	expectReceived(t, received, "second")
+
+	select {
+	case e := <-collector.events:
+		{
+			deadLetter := e.(akka.DeadLetter)
+			if _, ok := deadLetter.Message().(*dispatch.SpillFailure); !ok || deadLetter.Recipient().CompareTo(ref) != 0 {
+				t.Fatalf("expected a SpillFailure dead letter to %s, but got %#v", ref.Path(), e)
+			}
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("the message that could not be read back was not dead-lettered")
+	}
+
+	select {
+	case message := <-received:
+		t.Fatalf("the unreadable message should not reach the actor, but got %v", message)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,82 @@
+package actor
+
+import (
+	"reflect"
+)
+
+type UnhandledFunc func(message interface{}) (handled bool, err error)
+
+// DispatchTable maps the type of a message to its handler, so an actor's
+// Receive can be written as p.table.Dispatch(message) instead of a type switch
+type DispatchTable struct {
+	handlers  map[reflect.Type]reflect.Value
+	unhandled UnhandledFunc
+}
+
+func NewDispatchTable() *DispatchTable {
+	return &DispatchTable{
+		handlers: make(map[reflect.Type]reflect.Value),
+	}
+}
+
+// On registers handler for messages of the same type as message, which can
+// also be given as a reflect.Type, the handler should be func(T) or
+// func(T) error where a message of that type is assignable to T
+func (p *DispatchTable) On(message interface{}, handler interface{}) (err error) {
+	if message == nil {
+		err = ErrDispatchMessageTypeIsNil
+		return
+	}
+
+	msgType, ok := message.(reflect.Type)
+	if !ok {
+		msgType = reflect.TypeOf(message)
+	}
+
+	if handler == nil {
+		err = ErrDispatchHandlerShouldBeFunc
+		return
+	}
+
+	fnVal := reflect.ValueOf(handler)
+	fnType := fnVal.Type()
+
+	if fnType.Kind() != reflect.Func || fnType.NumIn() != 1 {
+		err = ErrDispatchHandlerShouldBeFunc
+		return
+	}
+
+	if checkFuncReturnsError(fnType) != nil {
+		err = ErrDispatchHandlerShouldBeFunc
+		return
+	}
+
+	if !msgType.AssignableTo(fnType.In(0)) {
+		err = ErrDispatchHandlerArgMismatch
+		return
+	}
+
+	p.handlers[msgType] = fnVal
+
+	return
+}
+
+// OnUnhandled sets the handler for messages without a registered type, by
+// default such messages are reported as not handled
+func (p *DispatchTable) OnUnhandled(fn UnhandledFunc) {
+	p.unhandled = fn
+}
+
+func (p *DispatchTable) Dispatch(message interface{}) (handled bool, err error) {
+	if message != nil {
+		if fnVal, exist := p.handlers[reflect.TypeOf(message)]; exist {
+			return true, callFuncReturningError(fnVal, reflect.ValueOf(message))
+		}
+	}
+
+	if p.unhandled != nil {
+		return p.unhandled(message)
+	}
+
+	return
+}
@@ -0,0 +1,68 @@
+package actor
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type greet struct {
+	Name string
+}
+
+type shout struct {
+	Text string
+}
+
+func TestDispatchTableDispatchesByMessageType(t *testing.T) {
+	table := NewDispatchTable()
+
+	var greeted, shouted string
+	errShout := errors.New("too loud")
+
+	if err := table.On(greet{}, func(msg greet) { greeted = msg.Name }); err != nil {
+		t.Fatalf("register greet handler failure: %s", err.Error())
+	}
+
+	if err := table.On(reflect.TypeOf((*shout)(nil)), func(msg *shout) error { shouted = msg.Text; return errShout }); err != nil {
+		t.Fatalf("register shout handler failure: %s", err.Error())
+	}
+
+	if handled, err := table.Dispatch(greet{Name: "akka"}); !handled || err != nil || greeted != "akka" {
+		t.Fatalf("greet should be dispatched to its handler, handled: %v, err: %v, greeted: %q", handled, err, greeted)
+	}
+
+	if handled, err := table.Dispatch(&shout{Text: "hey"}); !handled || err != errShout || shouted != "hey" {
+		t.Fatalf("shout should be dispatched to its handler, handled: %v, err: %v, shouted: %q", handled, err, shouted)
+	}
+
+	if handled, _ := table.Dispatch(42); handled {
+		t.Fatalf("unregistered message should not be handled")
+	}
+
+	var unhandled interface{}
+	table.OnUnhandled(func(message interface{}) (bool, error) {
+		unhandled = message
+		return true, nil
+	})
+
+	if handled, _ := table.Dispatch("unknown"); !handled || unhandled != "unknown" {
+		t.Fatalf("unregistered message should fall back to the unhandled handler, got %v", unhandled)
+	}
+}
+
+func TestDispatchTableRejectsBadHandlers(t *testing.T) {
+	table := NewDispatchTable()
+
+	if err := table.On(greet{}, "not a func"); err != ErrDispatchHandlerShouldBeFunc {
+		t.Fatalf("expected ErrDispatchHandlerShouldBeFunc, but got %v", err)
+	}
+
+	if err := table.On(greet{}, func(msg shout) {}); err != ErrDispatchHandlerArgMismatch {
+		t.Fatalf("expected ErrDispatchHandlerArgMismatch, but got %v", err)
+	}
+
+	if err := table.On(greet{}, func(msg greet) int { return 0 }); err != ErrDispatchHandlerShouldBeFunc {
+		t.Fatalf("expected ErrDispatchHandlerShouldBeFunc, but got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package actor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/dispatch"
+	"github.com/go-akka/akka/event"
+	"github.com/go-akka/configuration"
+)
+
+func TestDispatcherIdIsResolvedThroughItsAlias(t *testing.T) {
+	config := strings.Replace(testConfig, "actor {", `actor {
+		my-new-dispatcher {
+			type = "dispatcher"
+			throughput = 2
+		}
+
+		dispatcher-aliases {
+			"akka.actor.my-old-dispatcher" = "akka.actor.my-new-dispatcher"
+			"akka.actor.my-lost-dispatcher" = "akka.actor.my-missing-dispatcher"
+		}
+`, 1)
+	config = strings.Replace(config, `loglevel = "ERROR"`, `loglevel = "WARNING"`, 1)
+
+	system, err := NewActorSystem("DispatcherAlias", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	collector := newEventCollector(system, event.Warning{})
+
+	echoProps, err := props.Create((*echoActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(echoProps.WithDispatcher("akka.actor.my-old-dispatcher"), "aliased")
+	if err != nil {
+		t.Fatalf("create actor on the aliased dispatcher failure: %s", err.Error())
+	}
+
+	dispatcher := ref.(*LocalActorRef).Cell().Dispatcher()
+	if dispatcher != system.dispatchers.Lookup("akka.actor.my-new-dispatcher") || dispatcher.Throughput() != 2 {
+		t.Fatalf("actor should run on my-new-dispatcher, but got throughput %d", dispatcher.Throughput())
+	}
+
+	select {
+	case e := <-collector.events:
+		warning, ok := e.(*event.Warning)
+		if !ok || !strings.Contains(fmt.Sprint(warning.Message()), "[akka.actor.my-old-dispatcher]") {
+			t.Fatalf("fallback to the alias should be warned, but got %v", e)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("fallback to the alias was not warned")
+	}
+
+	if _, err = system.ActorOf(echoProps.WithDispatcher("akka.actor.my-old-dispatcher"), "aliased-again"); err != nil {
+		t.Fatalf("create second actor on the aliased dispatcher failure: %s", err.Error())
+	}
+
+	select {
+	case e := <-collector.events:
+		t.Fatalf("alias should be warned once only, but got %v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	for _, id := range []string{"akka.actor.my-lost-dispatcher", "akka.actor.my-unknown-dispatcher"} {
+		if system.dispatchers.HasDispatcher(id) {
+			t.Fatalf("%s should not be resolved", id)
+		}
+
+		_, err = system.ActorOf(echoProps.WithDispatcher(id), "")
+		if err == nil || !strings.HasPrefix(err.Error(), dispatch.ErrDispatcherNotConfigured.Error()) {
+			t.Fatalf("actor on %s should fail with ErrDispatcherNotConfigured, but got %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package actor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/dispatch"
+	"github.com/go-akka/configuration"
+)
+
+func TestActorsRunOnTheDispatcherOfTheirProps(t *testing.T) {
+	config := strings.Replace(testConfig, "actor {", `actor {
+		second-dispatcher {
+			type = "dispatcher"
+			throughput = 1
+		}
+
+		deployment {
+			/lost {
+				dispatcher = "akka.actor.missing-dispatcher"
+			}
+		}
+`, 1)
+
+	system, err := NewActorSystem("DispatcherOverride", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	echoProps, err := props.Create((*echoActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	defaultRef, err := system.ActorOf(echoProps, "default")
+	if err != nil {
+		t.Fatalf("create actor on the default dispatcher failure: %s", err.Error())
+	}
+
+	secondRef, err := system.ActorOf(echoProps.WithDispatcher("akka.actor.second-dispatcher"), "second")
+	if err != nil {
+		t.Fatalf("create actor on second-dispatcher failure: %s", err.Error())
+	}
+
+	defaultDispatcher := defaultRef.(*LocalActorRef).Cell().Dispatcher()
+	secondDispatcher := secondRef.(*LocalActorRef).Cell().Dispatcher()
+
+	if defaultDispatcher != system.dispatchers.Lookup(dispatch.DefaultDispatcherId) {
+		t.Fatalf("actor without dispatcher should run on the default dispatcher, but got %s", defaultDispatcher.Metrics().Id)
+	}
+
+	if secondDispatcher == defaultDispatcher || secondDispatcher != system.dispatchers.Lookup("akka.actor.second-dispatcher") {
+		t.Fatalf("actor should run on second-dispatcher, but got %s", secondDispatcher.Metrics().Id)
+	}
+
+	before := secondDispatcher.Metrics().ProcessedMessages
+
+	inbox := NewInbox(system)
+	defer inbox.Stop()
+
+	if err = inbox.Send(secondRef, "ping"); err != nil {
+		t.Fatalf("send to the second actor failure: %s", err.Error())
+	}
+
+	if reply, err := inbox.Receive(3 * time.Second); err != nil || reply != "ping" {
+		t.Fatalf("second actor should echo ping, but got %v, %v", reply, err)
+	}
+
+	for deadline := time.Now().Add(time.Second); secondDispatcher.Metrics().ProcessedMessages == before && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if secondDispatcher.Metrics().ProcessedMessages == before {
+		t.Fatalf("the message of the second actor should be processed by second-dispatcher")
+	}
+
+	// a missing dispatcher fails the same way from props and from the
+	// deployment, the second attempt shows the name is not kept reserved
+	missingProps := echoProps.WithDispatcher("akka.actor.missing-dispatcher")
+	for _, attempt := range []struct {
+		props akka.Props
+		name  string
+	}{{missingProps, "missing"}, {echoProps, "lost"}, {echoProps, "lost"}} {
+		if _, err = system.ActorOf(attempt.props, attempt.name); err == nil || !strings.HasPrefix(err.Error(), dispatch.ErrDispatcherNotConfigured.Error()) {
+			t.Fatalf("actor %s on a missing dispatcher should fail with ErrDispatcherNotConfigured, but got %v", attempt.name, err)
+		}
+	}
+}
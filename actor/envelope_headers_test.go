@@ -0,0 +1,133 @@
+package actor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+)
+
+type ForwardingActor struct {
+	*UntypedActor
+
+	next akka.ActorRef
+}
+
+func (p *ForwardingActor) ForwardingActor(next akka.ActorRef) {
+	p.next = next
+}
+
+func (p *ForwardingActor) Receive(message interface{}) (handled bool, err error) {
+	return true, p.Context().Forward(p.next, message)
+}
+
+type HeaderProbeActor struct {
+	*UntypedActor
+
+	headers chan akka.Headers
+}
+
+func (p *HeaderProbeActor) HeaderProbeActor(headers chan akka.Headers) {
+	p.headers = headers
+}
+
+func (p *HeaderProbeActor) Receive(message interface{}) (handled bool, err error) {
+	p.headers <- p.Context().Headers()
+	return true, nil
+}
+
+func TestHeadersSurviveTwoForwards(t *testing.T) {
+	system := newTestActorSystem(t, "EnvelopeHeaders")
+
+	headers := make(chan akka.Headers, 1)
+
+	probeProps, err := props.Create((*HeaderProbeActor)(nil), headers)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	next, err := system.ActorOf(probeProps, "probe")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	for _, name := range []string{"second", "first"} {
+		forwardingProps, err := props.Create((*ForwardingActor)(nil), next)
+		if err != nil {
+			t.Fatalf("create props failure: %s", err.Error())
+		}
+
+		if next, err = system.ActorOf(forwardingProps, name); err != nil {
+			t.Fatalf("create actor failure: %s", err.Error())
+		}
+	}
+
+	next.Tell(akka.WithHeaders("hello", akka.Headers{"trace-id": "abc-123"}))
+
+	select {
+	case received := <-headers:
+		if received.Get("trace-id") != "abc-123" {
+			t.Fatalf("trace id should be propagated two hops downstream, but got headers %v", received)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("probe did not receive the forwarded message")
+	}
+}
+
+// RelayingActor tells every message to next through its context, it does not
+// forward it
+type RelayingActor struct {
+	*UntypedActor
+
+	next akka.ActorRef
+}
+
+func (p *RelayingActor) RelayingActor(next akka.ActorRef) {
+	p.next = next
+}
+
+func (p *RelayingActor) Receive(message interface{}) (handled bool, err error) {
+	return true, p.Context().Tell(p.next, akka.WithHeaders(message, akka.Headers{"hop": "relay"}))
+}
+
+func TestHeadersPropagateThroughTheTellsOfTheReceive(t *testing.T) {
+	system := newTestActorSystem(t, "RelayedHeaders")
+
+	headers := make(chan akka.Headers, 1)
+
+	probeProps, err := props.Create((*HeaderProbeActor)(nil), headers)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	probe, err := system.ActorOf(probeProps, "probe")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	relayProps, err := props.Create((*RelayingActor)(nil), probe)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	relay, err := system.ActorOf(relayProps, "relay")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	relay.Tell(akka.WithHeaders("hello", akka.Headers{"trace-id": "abc-123", "hop": "source", "user": "alice"}))
+
+	select {
+	case received := <-headers:
+		if received.Get("trace-id") != "abc-123" || received.Get("hop") != "relay" {
+			t.Fatalf("the tell of the relay should keep the trace id and its own hop header, but got headers %v", received)
+		}
+
+		if _, exist := received["user"]; exist {
+			t.Fatalf("only the propagated headers should be carried on, but got headers %v", received)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("probe did not receive the relayed message")
+	}
+}
@@ -2,6 +2,11 @@ package actor
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-akka/akka"
 )
 
 var (
@@ -11,7 +16,84 @@ var (
 	ErrNoUntypedActorOrReceiveActorCombind = errors.New("actor should combine *actor.UntypedActor or *actor.ReceiveActor")
 	ErrBadActorInitFuncOutNumber           = errors.New("the actor init func return's number should be 0 or 1,the type should be void or error")
 	ErrBadActorInitFuncOutType             = errors.New("the actor init func return's should be void or error")
+	ErrActorInitFuncNotFound               = errors.New("the actor init func named as the actor type is not found, but constructor args are given")
 	ErrSmartReceiveShouldBeFunc            = errors.New("smart receiver for args should be a func")
 	ErrWrongArgNumForSmartReceiveFunc      = errors.New("wrong arg num for smart receive func")
 	ErrSmartReceiveArgIsNil                = errors.New("smart receive arg should not be nil")
+	ErrDispatchHandlerShouldBeFunc         = errors.New("dispatch table handler should be a func with one arg, returning void or error")
+	ErrDispatchHandlerArgMismatch          = errors.New("dispatch table handler arg type does not accept the message type")
+	ErrDispatchMessageTypeIsNil            = errors.New("dispatch table message type should not be nil")
+	ErrInvalidActorName                    = errors.New("invalid actor name, must contain only word characters (i.e. [a-zA-Z0-9] plus non-leading '-' or '_')")
+	ErrActorNameExists                     = errors.New("actor name is not unique, the parent already has a child of that name")
+	ErrInboxReceiveTimeout                 = errors.New("inbox receive timed out")
+	ErrBadBackoffOptions                   = errors.New("backoff options should have child props, child name and 0 < min-backoff <= max-backoff")
+	ErrNilMessage                          = errors.New("message should not be nil")
+	ErrMaxUnconfirmedMessagesExceeded      = errors.New("too many unconfirmed deliveries, ConfirmDelivery has to be called before delivering more")
+	ErrMessageNotSerializable              = errors.New("message is not serializable, checked by akka.actor.serialize-messages")
+	ErrStashOverflow                       = errors.New("stash is full")
+	ErrStashNothingToStash                 = errors.New("stash should be called while a message is received")
+	ErrActorTerminated                     = errors.New("actor is terminated, the message is sent to the dead letters")
+	ErrBehaviorStackTooDeep                = errors.New("behavior stack reached akka.actor.behavior-stack-max-depth")
+	ErrInvalidConfiguration                = errors.New("invalid configuration")
 )
+
+// ActorCreationError is returned when the actor of Path could not be produced
+// or initialized from its props, ActorType is the type given to props.Create
+type ActorCreationError struct {
+	Path         akka.ActorPath
+	ActorType    reflect.Type
+	ProducerType reflect.Type
+	Cause        error
+}
+
+func (p *ActorCreationError) Error() string {
+	return fmt.Sprintf("create actor %s (%v by %v) failure: %v", p.Path, p.ActorType, p.ProducerType, p.Cause)
+}
+
+func (p *ActorCreationError) Unwrap() error {
+	return p.Cause
+}
+
+// ActorInitializationError is the failure reported to the supervisor when
+// Actor could not be created or its PreStart failed, DefaultDecider stops the
+// actor instead of restarting it into the same failure
+type ActorInitializationError struct {
+	Actor akka.ActorRef
+	Cause error
+}
+
+func (p *ActorInitializationError) Error() string {
+	return fmt.Sprintf("actor %s initialization failure: %v", p.Actor.Path(), p.Cause)
+}
+
+func (p *ActorInitializationError) Unwrap() error {
+	return p.Cause
+}
+
+// ActorPanicError is the failure reported to the supervisor when the actor of
+// Path panicked, Value is what was recovered
+type ActorPanicError struct {
+	Path  akka.ActorPath
+	Value interface{}
+	Stack []byte
+}
+
+func (p *ActorPanicError) Error() string {
+	return fmt.Sprintf("actor %s panicked: %v", p.Path, p.Value)
+}
+
+// Unwrap returns the recovered value if the handler panicked with an error
+func (p *ActorPanicError) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+// ConfigurationError lists every problem found in the configuration of a
+// system, so they can all be fixed at once
+type ConfigurationError struct {
+	Problems []string
+}
+
+func (p *ConfigurationError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrInvalidConfiguration, strings.Join(p.Problems, "; "))
+}
@@ -0,0 +1,113 @@
+package actor
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/configuration"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/event"
+)
+
+func publishErrorWithLogSource(t *testing.T, config string) *event.Error {
+	system, err := NewActorSystem("CallerInfo", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	collector := newEventCollector(system, event.Error{})
+
+	source := system.Provider().RootPath().Append("user").Append("source")
+	system.EventStream().PublishWithLogSource(event.NewErrorEvent(errors.New("failure"), "unknown", system, "failed"), akka.NewMinimalActorRef(source, nil))
+
+	select {
+	case e := <-collector.events:
+		{
+			errorEvent, ok := e.(*event.Error)
+			if !ok {
+				t.Fatalf("expected an error event, but got %#v", e)
+			}
+
+			if errorEvent.LogSource() != source.String() {
+				t.Fatalf("log source should be %s, but got %s", source, errorEvent.LogSource())
+			}
+
+			return errorEvent
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("error event was not published")
+	}
+
+	return nil
+}
+
+func TestPublishWithLogSourceRecordsCallerWhenEnabled(t *testing.T) {
+	config := strings.Replace(testConfig, "akka {", `akka {
+	log-caller-info = on`, 1)
+
+	errorEvent := publishErrorWithLogSource(t, config)
+
+	if !strings.HasPrefix(errorEvent.Caller(), "event_stream_caller_test.go:") {
+		t.Fatalf("caller should point into this file, but got %q", errorEvent.Caller())
+	}
+
+	if !strings.Contains(errorEvent.String(), errorEvent.Caller()) {
+		t.Fatalf("rendered event should show the caller, but got %s", errorEvent.String())
+	}
+}
+
+func TestPublishWithLogSourceSkipsCallerByDefault(t *testing.T) {
+	if caller := publishErrorWithLogSource(t, testConfig).Caller(); caller != "" {
+		t.Fatalf("caller should only be recorded when enabled, but got %q", caller)
+	}
+}
+
+func collectEventStreamTraces(t *testing.T, config string) (traces []string) {
+	system, err := NewActorSystem("EventStreamDebug", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	collector := newEventCollector(system, event.Debug{})
+
+	subscriber := akka.NewMinimalActorRef(system.Provider().RootPath().Append("user").Append("subscriber"), nil)
+
+	system.EventStream().Subscribe(subscriber, reflect.TypeOf(""))
+	system.EventStream().Publish("ping")
+	system.EventStream().Unsubscribe(subscriber)
+
+	for {
+		select {
+		case e := <-collector.events:
+			traces = append(traces, e.(*event.Debug).Message().(string))
+		case <-time.After(100 * time.Millisecond):
+			return
+		}
+	}
+}
+
+func TestDebugEventStreamTracesSubscriptionsAndPublications(t *testing.T) {
+	config := strings.Replace(testConfig, "actor {", "actor {\n\t\tdebug.event-stream = on", 1)
+
+	traces := strings.Join(collectEventStreamTraces(t, config), "\n")
+
+	for _, want := range []string{
+		"subscribing Actor[akka://EventStreamDebug/user/subscriber] to channel string",
+		"publishing string",
+		"unsubscribing Actor[akka://EventStreamDebug/user/subscriber] from all channels",
+	} {
+		if !strings.Contains(traces, want) {
+			t.Fatalf("traces should contain %q, but got\n%s", want, traces)
+		}
+	}
+}
+
+func TestEventStreamIsNotTracedByDefault(t *testing.T) {
+	if traces := collectEventStreamTraces(t, testConfig); len(traces) != 0 {
+		t.Fatalf("event stream should not be traced without debug, but got %v", traces)
+	}
+}
@@ -0,0 +1,122 @@
+package actor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/dispatch/sysmsg"
+)
+
+// Inbox lets code outside of actors talk to actors, the messages sent to its
+// temporary ref are queued until they are taken by Receive
+type Inbox struct {
+	provider akka.ActorRefProvider
+	receiver *inboxActorRef
+}
+
+func NewInbox(system akka.ActorSystem) *Inbox {
+	provider := system.(*ActorSystemImpl).Provider()
+	path := provider.TempPath()
+
+	receiver := &inboxActorRef{
+		MinimalActorRef: akka.NewMinimalActorRef(path, provider),
+		signal:          make(chan struct{}, 1),
+	}
+
+	provider.RegisterTempActor(receiver, path)
+
+	return &Inbox{
+		provider: provider,
+		receiver: receiver,
+	}
+}
+
+func (p *Inbox) Self() akka.ActorRef {
+	return p.receiver
+}
+
+func (p *Inbox) Send(target akka.ActorRef, message interface{}) error {
+	return target.Tell(message, p.receiver)
+}
+
+// Receive returns the next message, waiting at most timeout for it to arrive
+func (p *Inbox) Receive(timeout time.Duration) (message interface{}, err error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		var ok bool
+		if message, ok = p.receiver.dequeue(); ok {
+			return
+		}
+
+		select {
+		case <-p.receiver.signal:
+		case <-timer.C:
+			{
+				if message, ok = p.receiver.dequeue(); ok {
+					return
+				}
+				err = ErrInboxReceiveTimeout
+				return
+			}
+		}
+	}
+}
+
+// Watch makes the inbox receive a *Terminated once target stops
+func (p *Inbox) Watch(target akka.ActorRef) (err error) {
+	watchee, ok := target.(akka.InternalActorRef)
+	if !ok {
+		return
+	}
+	return watchee.SendSystemMessage(&sysmsg.Watch{Watchee: target, Watcher: p.receiver})
+}
+
+func (p *Inbox) Stop() {
+	p.provider.UnregisterTempActor(p.receiver.Path())
+}
+
+type inboxActorRef struct {
+	*akka.MinimalActorRef
+
+	messages []interface{}
+	locker   sync.Mutex
+	signal   chan struct{}
+}
+
+func (p *inboxActorRef) Tell(message interface{}, sender ...akka.ActorRef) (err error) {
+	p.locker.Lock()
+	p.messages = append(p.messages, message)
+	p.locker.Unlock()
+
+	select {
+	case p.signal <- struct{}{}:
+	default:
+	}
+
+	return
+}
+
+func (p *inboxActorRef) SendSystemMessage(message akka.SystemMessage) (err error) {
+	if notification, ok := message.(*sysmsg.DeathWatchNotification); ok {
+		return p.Tell(&Terminated{Actor: notification.Actor, ExistenceConfirmed: notification.ExistenceConfirmed})
+	}
+	return
+}
+
+func (p *inboxActorRef) dequeue() (message interface{}, ok bool) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	if len(p.messages) == 0 {
+		return
+	}
+
+	message = p.messages[0]
+	p.messages[0] = nil
+	p.messages = p.messages[1:]
+
+	return message, true
+}
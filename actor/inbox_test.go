@@ -0,0 +1,88 @@
+package actor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka/actor/props"
+)
+
+type echoActor struct {
+	*UntypedActor
+}
+
+func (p *echoActor) Receive(message interface{}) (handled bool, err error) {
+	p.Sender().Tell(message, p.Self())
+	return true, nil
+}
+
+func TestInboxReceivesReplyFromEchoActor(t *testing.T) {
+	system := newTestActorSystem(t, "InboxEcho")
+
+	echoProps, err := props.Create((*echoActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	echo, err := system.ActorOf(echoProps, "echo")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	inbox := NewInbox(system)
+	defer inbox.Stop()
+
+	for _, msg := range []string{"hello", "world"} {
+		if err = inbox.Send(echo, msg); err != nil {
+			t.Fatalf("send failure: %s", err.Error())
+		}
+	}
+
+	for _, expected := range []string{"hello", "world"} {
+		reply, err := inbox.Receive(3 * time.Second)
+		if err != nil {
+			t.Fatalf("receive failure: %s", err.Error())
+		}
+
+		if reply != expected {
+			t.Fatalf("expected reply %q, but got %v", expected, reply)
+		}
+	}
+
+	if _, err = inbox.Receive(50 * time.Millisecond); err != ErrInboxReceiveTimeout {
+		t.Fatalf("empty inbox should time out, but got %v", err)
+	}
+}
+
+func TestInboxWatchReceivesTerminated(t *testing.T) {
+	system := newTestActorSystem(t, "InboxWatch")
+
+	echoProps, err := props.Create((*echoActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	echo, err := system.ActorOf(echoProps, "echo")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	inbox := NewInbox(system)
+	defer inbox.Stop()
+
+	if err = inbox.Watch(echo); err != nil {
+		t.Fatalf("watch failure: %s", err.Error())
+	}
+
+	inbox.Send(echo, &PoisonPill{})
+
+	message, err := inbox.Receive(3 * time.Second)
+	if err != nil {
+		t.Fatalf("receive failure: %s", err.Error())
+	}
+
+	terminated, ok := message.(*Terminated)
+	if !ok || terminated.Actor != echo {
+		t.Fatalf("expected Terminated for the echo actor, but got %v", message)
+	}
+}
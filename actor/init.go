@@ -7,5 +7,9 @@ import (
 
 func init() {
 	class_loader.Default.Register((*LocalActorRefProvider)(nil), "LocalActorRefProvider")
+	class_loader.Default.Register((*LocalActorRefProvider)(nil), "akka.actor.LocalActorRefProvider")
+	class_loader.Default.Register((*DefaultScheduler)(nil), "akka.actor.DefaultScheduler")
+	class_loader.Default.Register((*DefaultSupervisorStrategyConfigurator)(nil), "akka.actor.DefaultSupervisorStrategy")
+	class_loader.Default.Register((*StoppingSupervisorStrategyConfigurator)(nil), "akka.actor.StoppingSupervisorStrategy")
 	props.RegisterGlobalProducerCreator(newReflectProducer)
 }
@@ -0,0 +1,191 @@
+package actor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+)
+
+type PooledStateActor struct {
+	*UntypedActor
+
+	resets   chan struct{}
+	received []interface{}
+}
+
+func (p *PooledStateActor) PooledStateActor(resets chan struct{}) {
+	p.resets = resets
+}
+
+func (p *PooledStateActor) Reset() {
+	p.resets <- struct{}{}
+}
+
+type pooledState struct {
+	received int
+	hasBase  bool
+}
+
+func (p *PooledStateActor) Receive(message interface{}) (handled bool, err error) {
+	if message == "state" {
+		p.Sender().Tell(&pooledState{received: len(p.received), hasBase: p.UntypedActor != nil}, p.Self())
+		return true, nil
+	}
+
+	p.received = append(p.received, message)
+	return true, nil
+}
+
+func TestPooledInstancesDoNotLeakStateBetweenIncarnations(t *testing.T) {
+	system := newTestActorSystem(t, "InstancePool")
+
+	resets := make(chan struct{}, 10)
+
+	stateProps, err := props.Create((*PooledStateActor)(nil), resets)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+	pooledProps := stateProps.WithPooling(true)
+
+	inbox := NewInbox(system)
+	defer inbox.Stop()
+
+	for i := 0; i < 3; i++ {
+		ref, err := system.ActorOf(pooledProps, "")
+		if err != nil {
+			t.Fatalf("create actor failure: %s", err.Error())
+		}
+
+		inbox.Send(ref, "state")
+		reply, err := inbox.Receive(3 * time.Second)
+		if err != nil {
+			t.Fatalf("receive failure: %s", err.Error())
+		}
+
+		if state := reply.(*pooledState); state.received != 0 || !state.hasBase {
+			t.Fatalf("incarnation %d should start without the state of the previous one, but got %+v", i, state)
+		}
+
+		for _, msg := range []string{"a", "b", "c"} {
+			inbox.Send(ref, msg)
+		}
+
+		if err = inbox.Watch(ref); err != nil {
+			t.Fatalf("watch failure: %s", err.Error())
+		}
+		inbox.Send(ref, &PoisonPill{})
+
+		if _, err = inbox.Receive(3 * time.Second); err != nil {
+			t.Fatalf("receive failure: %s", err.Error())
+		}
+
+		select {
+		case <-resets:
+		case <-time.After(3 * time.Second):
+			t.Fatalf("stopped instance %d should be reset before it is pooled", i)
+		}
+	}
+
+	unpooled, err := system.ActorOf(stateProps, "unpooled")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	if err = inbox.Watch(unpooled); err != nil {
+		t.Fatalf("watch failure: %s", err.Error())
+	}
+	inbox.Send(unpooled, &PoisonPill{})
+
+	if _, err = inbox.Receive(3 * time.Second); err != nil {
+		t.Fatalf("receive failure: %s", err.Error())
+	}
+
+	select {
+	case <-resets:
+		t.Fatalf("instances of props without pooling should not be reset")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestReleasedInstancesAreZeroedAndReused(t *testing.T) {
+	resets := make(chan struct{}, 100)
+
+	stateProps, err := props.Create((*PooledStateActor)(nil), resets)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+	pooledProps := stateProps.WithPooling(true)
+
+	reused := false
+
+	// the pool may drop instances, e.g. with the race detector
+	for i := 0; i < 20 && !reused; i++ {
+		actor, err := pooledProps.NewActor()
+		if err != nil {
+			t.Fatalf("new actor failure: %s", err.Error())
+		}
+
+		// NewActor does not run the init func, the cell does
+		released := actor.(*PooledStateActor)
+		released.resets = resets
+		released.received = append(released.received, "state")
+
+		pooledProps.ReleaseActor(actor)
+
+		if released.received != nil || released.UntypedActor != nil {
+			t.Fatalf("released instance should be zeroed, but got %+v", released)
+		}
+
+		next, err := pooledProps.NewActor()
+		if err != nil {
+			t.Fatalf("new actor failure: %s", err.Error())
+		}
+
+		if next.(*PooledStateActor) == released {
+			reused = true
+		}
+		next.(*PooledStateActor).resets = resets
+		pooledProps.ReleaseActor(next)
+	}
+
+	if !reused {
+		t.Fatalf("released instances should be reused by the next actors")
+	}
+}
+
+func benchmarkProduceAndRelease(b *testing.B, pooling bool) {
+	resets := make(chan struct{}, 1)
+
+	stateProps, err := props.Create((*PooledStateActor)(nil), resets)
+	if err != nil {
+		b.Fatalf("create props failure: %s", err.Error())
+	}
+
+	var benchProps akka.Props = stateProps.WithPooling(pooling)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		actor, err := benchProps.NewActor()
+		if err != nil {
+			b.Fatalf("new actor failure: %s", err.Error())
+		}
+
+		actor.(*PooledStateActor).resets = resets
+		benchProps.ReleaseActor(actor)
+
+		select {
+		case <-resets:
+		default:
+		}
+	}
+}
+
+func BenchmarkProduceUnpooled(b *testing.B) {
+	benchmarkProduceAndRelease(b, false)
+}
+
+func BenchmarkProducePooled(b *testing.B) {
+	benchmarkProduceAndRelease(b, true)
+}
@@ -1,8 +1,10 @@
 package internal
 
 import (
-	"github.com/go-akka/akka"
+	"sync"
 	"time"
+
+	"github.com/go-akka/akka"
 )
 
 type childNameReserved struct {
@@ -27,14 +29,28 @@ type ChildRestartStats struct {
 
 	maxNrOfRetriesCount         int
 	restartTimeWindowStartNanos int
+
+	createdAt    time.Time
+	startedAt    time.Time
+	restartCount int
+	lastFailure  error
+	lastFailedAt time.Time
+	failed       *akka.Envelope
+	state        akka.ChildState
+
+	locker sync.Mutex
 }
 
 func NewChildRestartStats(child akka.InternalActorRef, maxNrOfRetriesCount, restartTimeWindowStartNanos int) akka.ChildRestartStats {
+	now := time.Now()
 	stats := &ChildRestartStats{
 		child:                       child,
 		uid:                         child.Path().Uid(),
 		maxNrOfRetriesCount:         maxNrOfRetriesCount,
 		restartTimeWindowStartNanos: restartTimeWindowStartNanos,
+		createdAt:                   now,
+		startedAt:                   now,
+		state:                       akka.ChildRunning,
 	}
 
 	return stats
@@ -94,3 +110,91 @@ func (p *ChildRestartStats) Child() akka.InternalActorRef {
 func (c *ChildRestartStats) ChildRestartStats() {}
 
 func (p *ChildRestartStats) ChildStats() {}
+
+func (p *ChildRestartStats) RestartCount() int {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return p.restartCount
+}
+
+func (p *ChildRestartStats) LastFailure() (cause error, at time.Time) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return p.lastFailure, p.lastFailedAt
+}
+
+func (p *ChildRestartStats) FailedEnvelope() *akka.Envelope {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return p.failed
+}
+
+func (p *ChildRestartStats) CreatedAt() time.Time {
+	return p.createdAt
+}
+
+func (p *ChildRestartStats) Uptime() time.Duration {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return time.Since(p.startedAt)
+}
+
+func (p *ChildRestartStats) State() akka.ChildState {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return p.state
+}
+
+// RecordFailure is called by the parent once the child reported a failure
+func (p *ChildRestartStats) RecordFailure(cause error, failed *akka.Envelope) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	p.lastFailure = cause
+	p.lastFailedAt = time.Now()
+	p.failed = failed
+	if p.state != akka.ChildStopping {
+		p.state = akka.ChildSuspended
+	}
+}
+
+func (p *ChildRestartStats) RecordResume() {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	p.failed = nil
+	if p.state != akka.ChildStopping {
+		p.state = akka.ChildRunning
+	}
+}
+
+func (p *ChildRestartStats) RecordRestart() {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	p.failed = nil
+	p.restartCount++
+	p.startedAt = time.Now()
+	if p.state != akka.ChildStopping {
+		p.state = akka.ChildRunning
+	}
+}
+
+func (p *ChildRestartStats) RecordStop() {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	p.state = akka.ChildStopping
+}
+
+func (p *ChildRestartStats) SuspensionReason() string {
+	if withCell, ok := p.child.(akka.ActorRefWithCell); ok {
+		return withCell.Underlying().SuspensionReason()
+	}
+	return ""
+}
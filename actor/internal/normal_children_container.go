@@ -27,7 +27,13 @@ func (p *NormalChildrenContainer) Add(name string, stats akka.ChildRestartStats)
 	return createNormalChildContainer(p.children.Set(name, stats))
 }
 
+// Remove only removes the incarnation of child, a newer child created with
+// the same name stays
 func (p *NormalChildrenContainer) Remove(child akka.ActorRef) akka.ChildrenContainer {
+	if _, exist := p.GetByRef(child); !exist {
+		return p
+	}
+
 	return createNormalChildContainer(p.children.Remove(child.Path().Name()))
 }
 
@@ -36,7 +42,7 @@ func (p *NormalChildrenContainer) ShallDie(actor akka.ActorRef) akka.ChildrenCon
 }
 
 func (p *NormalChildrenContainer) Reserve(name string) akka.ChildrenContainer {
-	// TODO: throw error while key exist
+	// the children refs reserve only names that are not taken
 	return newNormalChildrenContainer(p.children.Set(name, _childNameReservedInstance))
 }
 
@@ -0,0 +1,175 @@
+package actor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/configuration"
+)
+
+type SenderProbeActor struct {
+	*UntypedActor
+
+	senders chan akka.ActorRef
+}
+
+func (p *SenderProbeActor) SenderProbeActor(senders chan akka.ActorRef) {
+	p.senders = senders
+}
+
+func (p *SenderProbeActor) Receive(message interface{}) (handled bool, err error) {
+	p.senders <- p.Sender()
+	return true, p.Sender().Tell("reply", p.Self())
+}
+
+func newSenderProbe(t *testing.T, system *ActorSystemImpl) (ref akka.ActorRef, senders chan akka.ActorRef) {
+	senders = make(chan akka.ActorRef, 10)
+
+	probeProps, err := props.Create((*SenderProbeActor)(nil), senders)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	if ref, err = system.ActorOf(probeProps, "probe"); err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	return
+}
+
+func TestNilMessageIsRejectedAsInvalidMessage(t *testing.T) {
+	system := newTestActorSystem(t, "NilMessage")
+	collector := newEventCollector(system, akka.InvalidMessage{}, akka.DeadLetter{})
+
+	ref, senders := newSenderProbe(t, system)
+
+	if err := ref.Tell(nil); err != ErrNilMessage {
+		t.Fatalf("nil message should be rejected with ErrNilMessage, but got %v", err)
+	}
+
+	select {
+	case e := <-collector.events:
+		{
+			invalid, ok := e.(*akka.InvalidMessage)
+			if deadLetter, isDeadLetter := e.(akka.DeadLetter); isDeadLetter {
+				invalid, ok = deadLetter.Message().(*akka.InvalidMessage)
+			}
+
+			if !ok || invalid.Recipient != ref || len(invalid.Reason) == 0 {
+				t.Fatalf("expected an InvalidMessage for %s, but got %#v", ref, e)
+			}
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("rejected nil message was not delivered to the dead letters")
+	}
+
+	select {
+	case <-senders:
+		t.Fatalf("nil message should never reach the actor")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+type serializableOrder struct {
+	Item     string
+	Quantity int
+}
+
+type orderWithCallback struct {
+	Item    string
+	OnReply func(string)
+}
+
+type orderWithSecret struct {
+	Item   string
+	secret string
+}
+
+func TestSerializeMessagesRejectsNonSerializableMessages(t *testing.T) {
+	config := strings.Replace(testConfig, "actor {", "actor {\n\t\tserialize-messages = on", 1)
+
+	system, err := NewActorSystem("SerializeMessages", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	ref, senders := newSenderProbe(t, system)
+
+	if err = ref.Tell(&serializableOrder{Item: "book", Quantity: 2}); err != nil {
+		t.Fatalf("serializable message should be told, but got %s", err.Error())
+	}
+
+	select {
+	case <-senders:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("serializable message was not received")
+	}
+
+	for _, message := range []interface{}{
+		&orderWithCallback{Item: "book", OnReply: func(string) {}},
+		orderWithSecret{Item: "book", secret: "lost on the wire"},
+	} {
+		if err = ref.Tell(message); err == nil || !strings.HasPrefix(err.Error(), ErrMessageNotSerializable.Error()) {
+			t.Fatalf("%T should fail the serialization check, but got %v", message, err)
+		}
+	}
+
+	select {
+	case <-senders:
+		t.Fatalf("non-serializable messages should never reach the actor")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unchecked, _ := newSenderProbe(t, newTestActorSystem(t, "SerializeMessagesOff"))
+	if err = unchecked.Tell(&orderWithCallback{Item: "book"}); err != nil {
+		t.Fatalf("serialize-messages should be off by default, but got %s", err.Error())
+	}
+}
+
+func TestSerializeMessagesSkipsTheMessagesOfTheDefaultLoggers(t *testing.T) {
+	config := strings.Replace(testConfig, "actor {", "actor {\n\t\tserialize-messages = on", 1)
+	config = strings.Replace(config, "loggers = []", `loggers = ["akka.event.default-logger"]`, 1)
+
+	system, err := NewActorSystem("SerializeMessagesLoggers", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("system with serialize-messages and the default loggers should start, but got %s", err.Error())
+	}
+
+	ref, senders := newSenderProbe(t, system)
+	if err = ref.Tell(&serializableOrder{Item: "book", Quantity: 1}); err != nil {
+		t.Fatalf("serializable message should be told, but got %s", err.Error())
+	}
+
+	select {
+	case <-senders:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("serializable message was not received")
+	}
+}
+
+func TestNilSenderIsReplacedByDeadLetters(t *testing.T) {
+	system := newTestActorSystem(t, "NilSender")
+
+	ref, senders := newSenderProbe(t, system)
+
+	ref.Tell("no sender given")
+	ref.Tell("nil sender", nil)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case sender := <-senders:
+			if sender == nil {
+				t.Fatalf("actor should never see a nil sender")
+			}
+
+			if deadLetters := system.DeadLetters(); deadLetters != nil && sender != deadLetters {
+				t.Fatalf("missing sender should be the dead letters, but got %v", sender)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("message %d was not received", i)
+		}
+	}
+}
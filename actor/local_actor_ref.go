@@ -1,6 +1,10 @@
 package actor
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/go-akka/akka"
 )
 
@@ -51,13 +55,51 @@ func NewLocalActorRef(
 
 func (p *LocalActorRef) Tell(message interface{}, sender ...akka.ActorRef) error {
 	var s akka.ActorRef
-	if len(sender) == 0 {
-		s = (*akka.NoSender)(nil)
-	} else {
+	if len(sender) > 0 {
 		s = sender[0]
 	}
+	return p.send(message, s, false)
+}
+
+// TellWithResult is Tell returning ErrActorTerminated when the message is
+// dead-lettered because the actor is terminated. A message can still be
+// dead-lettered without it when the actor terminates before handling it
+func (p *LocalActorRef) TellWithResult(message interface{}, sender akka.ActorRef) error {
+	return p.send(message, sender, true)
+}
+
+func (p *LocalActorRef) send(message interface{}, s akka.ActorRef, withResult bool) error {
+	s = p.system.senderOrDeadLetters(s)
+
+	if message == nil {
+		p.system.rejectMessage(&akka.InvalidMessage{Reason: ErrNilMessage.Error(), Sender: s, Recipient: p})
+		return ErrNilMessage
+	}
+
+	// a stopped incarnation never takes messages again, even when an actor
+	// with the same name was created since
+	if p.cell.IsTerminated() {
+		p.system.deadLetter(message, s, p)
+		if withResult {
+			return fmt.Errorf("%w: %s", ErrActorTerminated, p.path)
+		}
+		return nil
+	}
+
+	envelope := akka.NewEnvelope(message, s)
+
+	if p.system.settings.SerializeMessages {
+		if err := p.system.verifySerializable(envelope.Message); err != nil {
+			p.system.rejectMessage(&akka.InvalidMessage{Reason: err.Error(), Sender: s, Recipient: p})
+			return err
+		}
+	}
 
-	return p.cell.SendMessage(akka.Envelope{message, s})
+	if p.system.settings.Tracing {
+		p.system.startSpan(&envelope, p)
+	}
+
+	return p.cell.SendMessage(envelope)
 }
 
 func (p *LocalActorRef) Path() akka.ActorPath {
@@ -65,7 +107,11 @@ func (p *LocalActorRef) Path() akka.ActorPath {
 }
 
 func (p *LocalActorRef) CompareTo(other akka.ActorRef) int {
-	return 0
+	return akka.CompareActorRefs(p, other)
+}
+
+func (p *LocalActorRef) Equals(other akka.ActorRef) bool {
+	return akka.CompareActorRefs(p, other) == 0
 }
 
 func (p *LocalActorRef) String() string {
@@ -73,11 +119,55 @@ func (p *LocalActorRef) String() string {
 }
 
 func (p *LocalActorRef) Parent() akka.InternalActorRef {
-	return nil
+	return p.supervisor
 }
 
+// GetChild walks down the names, ".." goes up to the parent, NoBody is
+// returned if one of them does not exist. A name like "worker#1234" only
+// matches the incarnation of worker with that uid
 func (p *LocalActorRef) GetChild(names ...string) akka.InternalActorRef {
-	return nil
+	var current akka.InternalActorRef = p
+
+	for _, name := range names {
+		switch name {
+		case "", ".":
+			{
+				continue
+			}
+		case "..":
+			{
+				current = current.Parent()
+			}
+		default:
+			if local, ok := current.(*LocalActorRef); ok {
+				current = local.getChildIncarnation(name)
+			} else {
+				current = current.GetChild(name)
+			}
+		}
+
+		if current == nil {
+			return akka.NoBody
+		}
+	}
+
+	return current
+}
+
+func (p *LocalActorRef) getChildIncarnation(name string) akka.InternalActorRef {
+	uid := 0
+	if i := strings.Index(name, "#"); i >= 0 {
+		uid, _ = strconv.Atoi(name[i+1:])
+		name = name[:i]
+	}
+
+	child := p.GetSingleChild(name)
+	if child == nil || (uid != 0 && child.Path().Uid() != uid) {
+		return nil
+	}
+
+	internal, _ := child.(akka.InternalActorRef)
+	return internal
 }
 
 func (p *LocalActorRef) Resume(causedByFailure error) {
@@ -130,6 +220,6 @@ func (p *LocalActorRef) SendSystemMessage(message akka.SystemMessage) (err error
 	return p.cell.SendSystemMessage(message)
 }
 
-func (p *LocalActorRef) Restart(cause error) {
-	p.cell.Restart(cause)
+func (p *LocalActorRef) Restart(cause error, failed *akka.Envelope) {
+	p.cell.Restart(cause, failed)
 }
@@ -1,11 +1,16 @@
 package actor
 
 import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/orcaman/concurrent-map"
+
 	"github.com/go-akka/akka"
 	"github.com/go-akka/akka/actor/props"
 	"github.com/go-akka/akka/dispatch"
 	"github.com/go-akka/akka/pkg/dynamic_access"
-	"sync"
 )
 
 var (
@@ -24,10 +29,19 @@ type LocalActorRefProvider struct {
 	defaultMailbox    akka.MailboxType
 
 	rootPath       akka.ActorPath
+	deadLetters    *DeadLetterActorRef
 	rootGuardian   akka.LocalActorRef
 	guardian       akka.LocalActorRef
 	systemGuardian akka.LocalActorRef
 
+	tempNode   akka.ActorPath
+	tempNumber int64
+	tempActors cmap.ConcurrentMap
+
+	// actors holds the live actors by path, it is sharded so creating and
+	// stopping actors in parallel rarely contend
+	actors cmap.ConcurrentMap
+
 	constructOnce sync.Once
 }
 
@@ -42,6 +56,11 @@ func (p *LocalActorRefProvider) Construct(
 		p.settings = settings
 		p.eventStrem = eventStrem
 		p.dynamicAccess = dynamicAccess
+
+		// the dead letters are there before the mailboxes and dispatchers,
+		// which hand them what they can not deliver
+		p.rootPath = akka.NewRootActorPath(akka.NewAddress("akka", p.systemName, "", 0), "/")
+		p.deadLetters = NewDeadLetterActorRef(p, p.rootPath.Append("deadLetters"), eventStrem)
 	})
 }
 
@@ -50,7 +69,13 @@ func (p *LocalActorRefProvider) Init(system akka.ActorSystem) (err error) {
 	p.defaultDispatcher = p.system.dispatchers.Lookup(dispatch.DefaultDispatcherId)
 	p.defaultMailbox, _ = p.system.mailboxes.Lookup(dispatch.DefaultMailboxId)
 
-	p.rootPath = akka.NewRootActorPath(akka.NewAddress("akka", p.systemName, "", 0), "/")
+	if p.deployer, err = akka.NewDeployer(p.settings, p.dynamicAccess); err != nil {
+		return
+	}
+
+	p.tempNode = akka.NewChildActorPath(p.rootPath, "temp", 0)
+	p.tempActors = cmap.New()
+	p.actors = cmap.New()
 
 	var rootGuardian, userGuardian, systemGuardian akka.LocalActorRef
 
@@ -72,7 +97,9 @@ func (p *LocalActorRefProvider) Init(system akka.ActorSystem) (err error) {
 
 	p.rootGuardian.Start()
 
-	p.eventStrem.StartDefaultLoggers(p.system)
+	if err = p.eventStrem.StartDefaultLoggers(p.system); err != nil {
+		return
+	}
 
 	return
 }
@@ -89,15 +116,64 @@ func (p *LocalActorRefProvider) ActorOf(
 
 	sys := system.(*ActorSystemImpl)
 
+	var deployed []akka.Deploy
+	if lookupDeploy {
+		if d, exist := p.deployer.Lookup(path); exist {
+			deployed = append(deployed, d)
+		}
+	}
+
+	if deploy != nil {
+		deployed = append(deployed, *deploy)
+	}
+
+	if len(deployed) > 0 {
+		merged := props.Deploy()
+		for i := len(deployed) - 1; i >= 0; i-- {
+			merged = deployed[i].WithFallback(merged)
+		}
+		props = props.WithDeploy(merged)
+	}
+
+	if pool, ok := props.RouterConfig().(akka.Pool); ok {
+		if routerProps, err := p.routerProps(pool, props); err != nil {
+			sys.Log().Error(err, "create router for %s failed, the actor is created without router", path)
+		} else {
+			props = routerProps
+		}
+	}
+
+	// the dispatchers of props and of the deployment are checked by
+	// makeChild, Lookup panics on one that is not configured
 	dispatcher := sys.dispatchers.Lookup(props.Dispatcher())
 	mailboxType, _ := sys.mailboxes.Lookup(props.Mailbox())
 
-	// dispitcher := akka.MessageDispatcher{}
-	return NewLocalActorRef(sys, props, dispatcher, mailboxType, supervisor, path)
+	ref := NewLocalActorRef(sys, props, dispatcher, mailboxType, supervisor, path)
+	p.registerActor(ref)
+
+	return ref
+}
+
+// routerProps creates the props of the RouterActor standing for routeeProps,
+// the routees keep the dispatcher and mailbox but not the router config
+func (p *LocalActorRefProvider) routerProps(pool akka.Pool, routeeProps akka.Props) (routerProps akka.Props, err error) {
+	if routerProps, err = props.Create((*RouterActor)(nil), pool, routeeProps.WithRouter(akka.NoRouter{})); err != nil {
+		return
+	}
+
+	if dispatcher := pool.RouterDispatcher(); len(dispatcher) > 0 {
+		if !p.system.dispatchers.HasDispatcher(dispatcher) {
+			err = fmt.Errorf("%s: %s", dispatch.ErrDispatcherNotConfigured, dispatcher)
+			return
+		}
+		routerProps = routerProps.WithDispatcher(dispatcher)
+	}
+
+	return
 }
 
 func (p *LocalActorRefProvider) DeadLetters() akka.ActorRef {
-	return nil
+	return p.deadLetters
 }
 
 func (p *LocalActorRefProvider) Deployer() akka.Deployer {
@@ -105,11 +181,16 @@ func (p *LocalActorRefProvider) Deployer() akka.Deployer {
 }
 
 func (p *LocalActorRefProvider) DefaultAddress() akka.Address {
-	return akka.Address{}
+	return p.rootPath.Address()
 }
 
-func (p *LocalActorRefProvider) ExternalAddressFor(addr akka.Address) akka.Address {
-	return akka.Address{}
+// ExternalAddressFor returns the local address for the local address only, a
+// local provider is not reachable under any other address
+func (p *LocalActorRefProvider) ExternalAddressFor(addr akka.Address) (external akka.Address) {
+	if addr == p.rootPath.Address() {
+		external = addr
+	}
+	return
 }
 
 func (p *LocalActorRefProvider) Guardian() akka.LocalActorRef {
@@ -121,11 +202,46 @@ func (p *LocalActorRefProvider) SystemGuardian() akka.LocalActorRef {
 }
 
 func (p *LocalActorRefProvider) RegisterTempActor(actorRef akka.InternalActorRef, path akka.ActorPath) {
+	p.tempActors.Set(path.String(), actorRef)
+}
+
+// ResolveActorRef looks up the live actor or temp actor at path, a path with
+// a uid only resolves the incarnation of that uid
+func (p *LocalActorRefProvider) ResolveActorRef(path akka.ActorPath) (ref akka.ActorRef, ok bool) {
+	if path == nil {
+		return
+	}
+
+	var v interface{}
+	if v, ok = p.actors.Get(registryKey(path)); !ok {
+		if v, ok = p.tempActors.Get(path.String()); !ok {
+			return
+		}
+	}
+
+	if ref, ok = v.(akka.ActorRef); ok && path.Uid() != 0 && ref.Path().Uid() != path.Uid() {
+		ref, ok = nil, false
+	}
 	return
 }
 
-func (p *LocalActorRefProvider) ResolveActorRef(path akka.ActorPath) akka.ActorRef {
-	return nil
+func (p *LocalActorRefProvider) registerActor(ref akka.ActorRef) {
+	p.actors.Set(registryKey(ref.Path()), ref)
+}
+
+// unregisterActor is called once the actor terminated, before its parent
+// may reuse the name for a new actor
+func (p *LocalActorRefProvider) unregisterActor(ref akka.ActorRef) {
+	key := registryKey(ref.Path())
+	if registered, exist := p.actors.Get(key); exist && registered == ref {
+		p.actors.Remove(key)
+	}
+}
+
+// registryKey is the path without its uid, the registry holds the live
+// incarnation of each path
+func registryKey(path akka.ActorPath) string {
+	return path.ToStringWithAddress(path.Address())
 }
 
 func (p *LocalActorRefProvider) RootGuardian() akka.InternalActorRef {
@@ -133,11 +249,14 @@ func (p *LocalActorRefProvider) RootGuardian() akka.InternalActorRef {
 }
 
 func (p *LocalActorRefProvider) RootGuardianAt(address akka.Address) akka.ActorRef {
-	return nil
+	if address == p.rootPath.Address() {
+		return p.rootGuardian
+	}
+	return p.DeadLetters()
 }
 
 func (p *LocalActorRefProvider) RootPath() akka.ActorPath {
-	return nil
+	return p.rootPath
 }
 
 func (p *LocalActorRefProvider) Settings() *akka.Settings {
@@ -149,7 +268,7 @@ func (p *LocalActorRefProvider) TempContainer() akka.InternalActorRef {
 }
 
 func (p *LocalActorRefProvider) TempPath() akka.ActorPath {
-	return nil
+	return akka.NewChildActorPath(p.tempNode, "$"+base64Name(atomic.AddInt64(&p.tempNumber, 1)-1), 0)
 }
 
 func (p *LocalActorRefProvider) TerminationFuture() {
@@ -157,7 +276,7 @@ func (p *LocalActorRefProvider) TerminationFuture() {
 }
 
 func (p *LocalActorRefProvider) UnregisterTempActor(path akka.ActorPath) {
-	return
+	p.tempActors.Remove(path.String())
 }
 
 func (p *LocalActorRefProvider) LocalActorRefProvider() {}
@@ -173,6 +292,26 @@ func (p *LocalActorRefProvider) createRootGuardian(system akka.ActorSystem) (ref
 	theOneWhoWalksTheBubblesOfSpaceTime := NewBubbleWalker(p.rootPath.Append("bubble-walker"), p)
 
 	ref = NewLocalActorRef(system, actorProps, p.defaultDispatcher, p.defaultMailbox, theOneWhoWalksTheBubblesOfSpaceTime, p.rootPath)
+	p.registerActor(ref)
+
+	return
+}
+
+// guardianSupervisorStrategy creates the strategy of the user guardian by the
+// configurator of akka.actor.guardian-supervisor-strategy
+func (p *LocalActorRefProvider) guardianSupervisorStrategy() (strategy akka.SupervisorStrategy, err error) {
+	var ins interface{}
+	if ins, err = p.dynamicAccess.CreateInstanceByName(p.settings.SupervisorStrategyClass); err != nil {
+		return
+	}
+
+	configurator, ok := ins.(akka.SupervisorStrategyConfigurator)
+	if !ok {
+		err = fmt.Errorf("%s: %s", akka.ErrBadTypeOfSupervisorConfigurator, p.settings.SupervisorStrategyClass)
+		return
+	}
+
+	strategy = configurator.Create()
 
 	return
 }
@@ -181,8 +320,13 @@ func (p *LocalActorRefProvider) createUserGuardian(rootGuardian akka.LocalActorR
 	cell := rootGuardian.Underlying().(*ActorCell)
 	cell.ReserveChild(name)
 
+	var strategy akka.SupervisorStrategy
+	if strategy, err = p.guardianSupervisorStrategy(); err != nil {
+		return
+	}
+
 	var actorProps akka.Props
-	actorProps, err = props.Create((*RootGuardianActor)(nil), nil)
+	actorProps, err = props.Create((*GuardianActor)(nil), strategy)
 	if err != nil {
 		return
 	}
@@ -190,6 +334,7 @@ func (p *LocalActorRefProvider) createUserGuardian(rootGuardian akka.LocalActorR
 	userGuardian := NewLocalActorRef(p.system, actorProps, p.defaultDispatcher, p.defaultMailbox, rootGuardian, p.rootPath.Append(name))
 
 	cell.InitChild(userGuardian)
+	p.registerActor(userGuardian)
 	userGuardian.Start()
 
 	ref = userGuardian
@@ -209,6 +354,7 @@ func (p *LocalActorRefProvider) createSystemGuardian(rootGuardian akka.LocalActo
 	systemGuardian := NewLocalActorRef(p.system, actorProps, p.defaultDispatcher, p.defaultMailbox, rootGuardian, p.rootPath.Append(name))
 
 	cell.InitChild(systemGuardian)
+	p.registerActor(systemGuardian)
 	systemGuardian.Start()
 
 	ref = systemGuardian
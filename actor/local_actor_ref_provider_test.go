@@ -0,0 +1,62 @@
+package actor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+)
+
+func TestResolveActorRefFindsLiveActorsOnly(t *testing.T) {
+	system := newTestActorSystem(t, "ResolveActorRef")
+
+	echoProps, err := props.Create((*echoActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	echo, err := system.ActorOf(echoProps, "echo")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	if resolved, ok := system.Provider().ResolveActorRef(echo.Path()); !ok || resolved != echo {
+		t.Fatalf("live actor %s should be resolved, but got %v", echo.Path(), resolved)
+	}
+
+	withoutUid := system.Provider().Guardian().Path().Append("echo")
+	if resolved, ok := system.Provider().ResolveActorRef(withoutUid); !ok || resolved != echo {
+		t.Fatalf("live actor should be resolved by %s without uid, but got %v", withoutUid, resolved)
+	}
+
+	otherIncarnation := akka.NewChildActorPath(system.Provider().Guardian().Path(), "echo", echo.Path().Uid()+1)
+	if resolved, ok := system.Provider().ResolveActorRef(otherIncarnation); ok {
+		t.Fatalf("%s should not resolve another incarnation, but got %v", otherIncarnation, resolved)
+	}
+
+	if _, ok := system.Provider().ResolveActorRef(system.Provider().Guardian().Path()); !ok {
+		t.Fatalf("user guardian should be resolved")
+	}
+
+	inbox := NewInbox(system)
+	defer inbox.Stop()
+
+	if err = inbox.Watch(echo); err != nil {
+		t.Fatalf("watch failure: %s", err.Error())
+	}
+
+	inbox.Send(echo, &PoisonPill{})
+
+	if _, err = inbox.Receive(3 * time.Second); err != nil {
+		t.Fatalf("receive failure: %s", err.Error())
+	}
+
+	if resolved, ok := system.Provider().ResolveActorRef(echo.Path()); ok {
+		t.Fatalf("stopped actor should not be resolved, but got %v", resolved)
+	}
+
+	if resolved, ok := system.Provider().ResolveActorRef(system.Provider().Guardian().Path().Append("missing")); ok {
+		t.Fatalf("actor that never existed should not be resolved, but got %v", resolved)
+	}
+}
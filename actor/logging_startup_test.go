@@ -0,0 +1,248 @@
+package actor
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/event"
+	"github.com/go-akka/akka/pkg/class_loader"
+	"github.com/go-akka/configuration"
+)
+
+const slowLoggerStartDelay = 200 * time.Millisecond
+
+var (
+	slowLoggerReadyAt  = make(chan time.Time, 1)
+	slowLoggerReceived = make(chan time.Time, 10)
+)
+
+func init() {
+	class_loader.Default.Register((*slowStartingLogger)(nil), "akka.test.slow-starting-logger")
+	class_loader.Default.Register((*debugLevelLogger)(nil), "akka.test.debug-level-logger")
+	class_loader.Default.Register((*warningLevelLogger)(nil), "akka.test.warning-level-logger")
+	class_loader.Default.Register((*bufferingLogger)(nil), "akka.test.buffering-logger")
+}
+
+type slowStartingLogger struct {
+}
+
+func (p *slowStartingLogger) Receive(context akka.ActorContext, message interface{}) (wasHandled bool, err error) {
+	switch msg := message.(type) {
+	case *event.InitializeLogger:
+		{
+			msg.Bus.Publish(event.NewInfoEvent("test", p, "early"))
+
+			time.Sleep(slowLoggerStartDelay)
+			slowLoggerReadyAt <- time.Now()
+
+			context.Sender().Tell(&event.LoggerInitialized{}, context.Self())
+		}
+	case akka.LogEvent:
+		{
+			if msg.Message() == "early" {
+				slowLoggerReceived <- time.Now()
+			}
+		}
+	}
+	wasHandled = true
+	return
+}
+
+func TestStartDefaultLoggersWaitsForReadiness(t *testing.T) {
+	config := strings.Replace(testConfig, "loggers = []", `loggers = ["akka.test.slow-starting-logger"]`, 1)
+	config = strings.Replace(config, `loglevel = "ERROR"`, `loglevel = "INFO"`, 1)
+
+	start := time.Now()
+
+	if _, err := NewActorSystem("SlowLogger", configuration.ParseString(config)); err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	if elapsed := time.Since(start); elapsed < slowLoggerStartDelay {
+		t.Fatalf("startup should wait for the logger to be ready, but took only %s", elapsed)
+	}
+
+	readyAt := <-slowLoggerReadyAt
+
+	select {
+	case receivedAt := <-slowLoggerReceived:
+		{
+			if receivedAt.Before(readyAt) {
+				t.Fatalf("early log was delivered before the logger was ready")
+			}
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("early log was not delivered after the logger became ready")
+	}
+}
+
+var (
+	debugLevelLoggerEvents   chan akka.LogLevel
+	warningLevelLoggerEvents chan akka.LogLevel
+)
+
+func receiveLevelTestEvent(context akka.ActorContext, message interface{}, events chan akka.LogLevel) {
+	switch logEvent := message.(type) {
+	case *event.InitializeLogger:
+		{
+			context.Sender().Tell(&event.LoggerInitialized{}, context.Self())
+		}
+	case akka.LogEvent:
+		{
+			if msg, ok := logEvent.Message().(string); ok && strings.HasPrefix(msg, "level-test") {
+				events <- logEvent.LogLevel()
+			}
+		}
+	}
+}
+
+type debugLevelLogger struct{}
+
+func (p *debugLevelLogger) Receive(context akka.ActorContext, message interface{}) (wasHandled bool, err error) {
+	receiveLevelTestEvent(context, message, debugLevelLoggerEvents)
+	return true, nil
+}
+
+type warningLevelLogger struct{}
+
+func (p *warningLevelLogger) Receive(context akka.ActorContext, message interface{}) (wasHandled bool, err error) {
+	receiveLevelTestEvent(context, message, warningLevelLoggerEvents)
+	return true, nil
+}
+
+// levelLoggingActor logs at every level through its BusLogging
+type levelLoggingActor struct {
+	*UntypedActor
+}
+
+func (p *levelLoggingActor) Receive(message interface{}) (handled bool, err error) {
+	log := event.Logging.GetLogger(p.Context())
+	log.Debug("level-test debug")
+	log.Info("level-test info")
+	log.Warning("level-test warning")
+	log.Error(nil, "level-test error")
+	return true, nil
+}
+
+func collectLevels(events chan akka.LogLevel) (levels []akka.LogLevel) {
+	for {
+		select {
+		case level := <-events:
+			levels = append(levels, level)
+		case <-time.After(200 * time.Millisecond):
+			return
+		}
+	}
+}
+
+func TestLoggersAreSubscribedAtTheirOwnLevel(t *testing.T) {
+	debugLevelLoggerEvents = make(chan akka.LogLevel, 10)
+	warningLevelLoggerEvents = make(chan akka.LogLevel, 10)
+
+	config := strings.Replace(testConfig, `loglevel = "ERROR"`, `loglevel = "INFO"`, 1)
+	config = strings.Replace(config, `loggers = []`, `loggers = ["akka.test.debug-level-logger", "akka.test.warning-level-logger"]
+	logger-levels {
+		"akka.test.debug-level-logger" = DEBUG
+		"akka.test.warning-level-logger" = WARNING
+	}`, 1)
+
+	system, err := NewActorSystem("LoggerLevels", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	if !system.Log().IsDebugEnabled() {
+		t.Fatalf("debug should be enabled for the debug logger, though loglevel is INFO")
+	}
+
+	levelProps, err := props.Create((*levelLoggingActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(levelProps, "levelLogging")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+	ref.Tell("log")
+
+	if levels := collectLevels(debugLevelLoggerEvents); len(levels) != 4 {
+		t.Fatalf("debug logger should receive all 4 events, but got %v", levels)
+	}
+
+	levels := collectLevels(warningLevelLoggerEvents)
+	if len(levels) != 2 || levels[0] != akka.WarningLevel || levels[1] != akka.ErrorLevel {
+		t.Fatalf("warning logger should only receive the warning and error events, but got %v", levels)
+	}
+}
+
+var (
+	bufferingLoggerWritten int32
+)
+
+// bufferingLogger only writes the events it holds when it is flushed
+type bufferingLogger struct {
+	pending []akka.LogEvent
+}
+
+func (p *bufferingLogger) Receive(context akka.ActorContext, message interface{}) (wasHandled bool, err error) {
+	switch msg := message.(type) {
+	case *event.InitializeLogger:
+		{
+			context.Sender().Tell(&event.LoggerInitialized{}, context.Self())
+		}
+	case *event.FlushLogger:
+		{
+			for _, logEvent := range p.pending {
+				if text, ok := logEvent.Message().(string); ok && strings.HasPrefix(text, "backlog") {
+					atomic.AddInt32(&bufferingLoggerWritten, 1)
+				}
+			}
+			p.pending = nil
+
+			context.Sender().Tell(&event.LoggerFlushed{}, context.Self())
+		}
+	case akka.LogEvent:
+		{
+			p.pending = append(p.pending, msg)
+		}
+	}
+	wasHandled = true
+	return
+}
+
+func TestTerminateFlushesLoggers(t *testing.T) {
+	atomic.StoreInt32(&bufferingLoggerWritten, 0)
+
+	config := strings.Replace(testConfig, "loggers = []", `loggers = ["akka.test.buffering-logger"]`, 1)
+	config = strings.Replace(config, `loglevel = "ERROR"`, `loglevel = "INFO"`, 1)
+
+	system, err := NewActorSystem("FlushLoggers", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	for i := 0; i < 100; i++ {
+		system.EventStream().Publish(event.NewInfoEvent("test", system, fmt.Sprintf("backlog %d", i)))
+	}
+
+	var callbackWritten int32 = -1
+	system.RegisterOnTermination(func() {
+		callbackWritten = atomic.LoadInt32(&bufferingLoggerWritten)
+	})
+
+	system.Terminate()
+
+	if written := atomic.LoadInt32(&bufferingLoggerWritten); written != 100 {
+		t.Fatalf("terminate should flush the whole backlog, but only %d of 100 events were written", written)
+	}
+
+	if callbackWritten != 0 {
+		t.Fatalf("loggers should be flushed after the other termination callbacks ran")
+	}
+}
@@ -0,0 +1,112 @@
+package actor
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/configuration"
+)
+
+type sequenced struct {
+	sender int
+	seq    int
+}
+
+// OrderCheckingActor reports every message that is not the next one of its
+// sender, and signals done once all the messages arrived
+type OrderCheckingActor struct {
+	*UntypedActor
+
+	expected   map[int]int
+	remaining  int
+	violations chan sequenced
+	done       chan struct{}
+}
+
+func (p *OrderCheckingActor) OrderCheckingActor(total int, violations chan sequenced, done chan struct{}) {
+	p.expected = make(map[int]int)
+	p.remaining = total
+	p.violations = violations
+	p.done = done
+}
+
+func (p *OrderCheckingActor) Receive(message interface{}) (handled bool, err error) {
+	msg, ok := message.(*sequenced)
+	if !ok {
+		return false, nil
+	}
+
+	if msg.seq != p.expected[msg.sender] {
+		select {
+		case p.violations <- *msg:
+		default:
+		}
+	}
+	p.expected[msg.sender] = msg.seq + 1
+
+	if p.remaining--; p.remaining == 0 {
+		close(p.done)
+	}
+
+	return true, nil
+}
+
+func runOrdering(t *testing.T, throughput int) {
+	config := strings.Replace(testConfig, "throughput = 5", "throughput = "+strconv.Itoa(throughput), 1)
+
+	system, err := NewActorSystem("MessageOrdering", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+	defer system.Terminate()
+
+	const senders, messages = 8, 2000
+
+	violations := make(chan sequenced, 1)
+	done := make(chan struct{})
+
+	checkerProps, err := props.Create((*OrderCheckingActor)(nil), senders*messages, violations, done)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	checker, err := system.ActorOf(checkerProps, "checker")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	var wg sync.WaitGroup
+	for s := 0; s < senders; s++ {
+		wg.Add(1)
+		go func(sender int) {
+			defer wg.Done()
+			for seq := 0; seq < messages; seq++ {
+				checker.Tell(&sequenced{sender: sender, seq: seq})
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("not every message arrived")
+	}
+
+	select {
+	case v := <-violations:
+		t.Fatalf("message %d of sender %d arrived out of order with throughput %d", v.seq, v.sender, throughput)
+	default:
+	}
+}
+
+func TestMessagesOfEachSenderArriveInOrder(t *testing.T) {
+	// throughput 1 re-registers the mailbox after every message
+	for _, throughput := range []int{1, 5, 100} {
+		runOrdering(t, throughput)
+	}
+}
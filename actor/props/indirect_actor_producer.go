@@ -16,6 +16,13 @@ type IndirectActorProducer interface {
 	ActorType() reflect.Type
 }
 
+// PoolingActorProducer takes back the stopped instances of pooled props to
+// produce the next actors from them
+type PoolingActorProducer interface {
+	IndirectActorProducer
+	Release(actor akka.Actor)
+}
+
 func createProducer(producerCreator ProducerCreatorFunc, v interface{}, args ...interface{}) (producer IndirectActorProducer, err error) {
 	typ := reflect.TypeOf(v)
 
@@ -52,6 +59,21 @@ func (p *_DefaultProducer) ActorType() reflect.Type {
 	return nil
 }
 
+type _FuncProducer struct {
+	fn func() akka.Actor
+}
+
+func (p *_FuncProducer) Produce() (actor akka.Actor, err error) {
+	if actor = p.fn(); actor == nil {
+		err = ErrCreateInstanceFailure
+	}
+	return
+}
+
+func (p *_FuncProducer) ActorType() reflect.Type {
+	return nil
+}
+
 func createInstanceByType(typ reflect.Type, args ...interface{}) (v reflect.Value, err error) {
 	typVal := reflect.New(typ)
 
@@ -38,14 +38,37 @@ func Create(v interface{}, args ...interface{}) (*Props, error) {
 	return v.(*Props), nil
 }
 
+// FromProducer creates props whose actors are produced by producer, without
+// any reflection on the actor type
+func FromProducer(producer IndirectActorProducer) (*Props, error) {
+	if producer == nil {
+		return nil, ErrNoActorProducerSpecified
+	}
+
+	return &Props{
+		producer:        producer,
+		producerCreator: emptyProps.producerCreator,
+		typ:             producer.ActorType(),
+	}, nil
+}
+
+// FromFunc creates props whose actors are returned by fn, the actor is used
+// as it is, so it does not have to combine a base actor type
+func FromFunc(fn func() akka.Actor) (*Props, error) {
+	if fn == nil {
+		return nil, ErrNoActorProducerSpecified
+	}
+
+	return FromProducer(&_FuncProducer{fn: fn})
+}
+
+// Props are immutable, the deploy carries the dispatcher, mailbox and router
 type Props struct {
 	deploy          akka.Deploy
-	mailbox         string
-	dispatcher      string
-	routerConfig    akka.RouterConfig
 	producer        IndirectActorProducer
 	producerCreator ProducerCreatorFunc
 	typ             reflect.Type
+	pooling         bool
 }
 
 func (p Props) Create(v interface{}, args ...interface{}) (props akka.Props, err error) {
@@ -57,8 +80,6 @@ func (p Props) Create(v interface{}, args ...interface{}) (props akka.Props, err
 	props = &Props{
 		producer:        producer,
 		producerCreator: p.producerCreator,
-		mailbox:         dispatch.DefaultMailboxId,
-		dispatcher:      dispatch.DefaultDispatcherId,
 		typ:             reflect.TypeOf(v),
 	}
 
@@ -70,6 +91,10 @@ func (p Props) NewActor() (actor akka.Actor, err error) {
 	return
 }
 
+func (p Props) Deploy() akka.Deploy {
+	return p.deploy
+}
+
 func (p Props) Dispatcher() string {
 	if len(p.deploy.Dispatcher()) == 0 {
 		return dispatch.DefaultDispatcherId
@@ -94,36 +119,65 @@ func (p Props) WithDeploy(deploy akka.Deploy) (props akka.Props) {
 	return newProps
 }
 
+// WithDispatcher binds the actor to the dispatcher of id dispatcher when it is
+// attached, a dispatcher in its deployment config takes precedence. Creating
+// the actor fails with dispatch.ErrDispatcherNotConfigured if the dispatcher
+// it ends up with is not configured
 func (p Props) WithDispatcher(dispatcher string) (props akka.Props) {
-	newProps := p.copy()
-	newProps.deploy = p.deploy.WithDispatcher(dispatcher)
-	return newProps
+	return p.WithDeploy(p.deploy.WithDispatcher(dispatcher))
 }
 
 func (p Props) WithMailbox(mailbox string) (props akka.Props) {
-	newProps := p.copy()
-	newProps.deploy = p.deploy.WithMailbox(mailbox)
-	return newProps
+	return p.WithDeploy(p.deploy.WithMailbox(mailbox))
 }
 
 func (p Props) WithRouter(config akka.RouterConfig) (props akka.Props) {
+	return p.WithDeploy(p.deploy.WithRouterConfig(config))
+}
+
+func (p Props) WithPooling(pooling bool) (props akka.Props) {
 	newProps := p.copy()
-	newProps.deploy = p.deploy.WithRouterConfig(config)
+	newProps.pooling = pooling
 	return newProps
 }
 
+func (p Props) Pooling() bool {
+	return p.pooling
+}
+
+// ReleaseActor hands the stopped actor back to the producer, only for pooled
+// props whose producer pools, the actor must not be used anymore afterwards
+func (p Props) ReleaseActor(actor akka.Actor) {
+	if !p.pooling || actor == nil {
+		return
+	}
+
+	if producer, ok := p.producer.(PoolingActorProducer); ok {
+		producer.Release(actor)
+	}
+}
+
 func (p Props) Type() reflect.Type {
 	return p.typ
 }
 
+func (p Props) Producer() IndirectActorProducer {
+	return p.producer
+}
+
+func (p Props) ProducerType() reflect.Type {
+	if p.producer == nil {
+		return nil
+	}
+	return reflect.TypeOf(p.producer)
+}
+
 func (p Props) copy() (props *Props) {
 	return &Props{
 		deploy:          p.deploy,
-		mailbox:         p.mailbox,
-		dispatcher:      p.dispatcher,
-		routerConfig:    p.routerConfig,
 		producer:        p.producer,
 		producerCreator: p.producerCreator,
 		typ:             p.typ,
+		pooling:         p.pooling,
 	}
 }
@@ -0,0 +1,157 @@
+package actor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/dispatch"
+	"github.com/go-akka/akka/routing"
+)
+
+// funcActor combines no base actor, it can only be created by FromFunc
+type funcActor struct {
+	received chan interface{}
+}
+
+func (p *funcActor) Receive(message interface{}) (handled bool, err error) {
+	p.received <- message
+	return true, nil
+}
+
+type SelfReportingActor struct {
+	*UntypedActor
+
+	selves chan akka.ActorRef
+}
+
+func (p *SelfReportingActor) Receive(message interface{}) (handled bool, err error) {
+	p.selves <- p.Self()
+	return true, nil
+}
+
+type selfReportingProducer struct {
+	selves chan akka.ActorRef
+}
+
+func (p *selfReportingProducer) Produce() (actor akka.Actor, err error) {
+	reporter := &SelfReportingActor{selves: p.selves}
+	reporter.UntypedActor = NewUntypedActor(reporter, nil)
+	return reporter, nil
+}
+
+func (p *selfReportingProducer) ActorType() reflect.Type {
+	return reflect.TypeOf((*SelfReportingActor)(nil)).Elem()
+}
+
+func TestPropsFromFuncCreatesActorsWithoutBase(t *testing.T) {
+	system := newTestActorSystem(t, "PropsFromFunc")
+
+	received := make(chan interface{}, 1)
+
+	funcProps, err := props.FromFunc(func() akka.Actor {
+		return &funcActor{received: received}
+	})
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(funcProps, "func")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	ref.Tell("hello")
+
+	select {
+	case msg := <-received:
+		if msg != "hello" {
+			t.Fatalf("func actor should receive hello, but got %v", msg)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("func actor did not receive the message")
+	}
+}
+
+func TestPropsFromProducerUsesTheGivenProducer(t *testing.T) {
+	system := newTestActorSystem(t, "PropsFromProducer")
+
+	selves := make(chan akka.ActorRef, 1)
+
+	producerProps, err := props.FromProducer(&selfReportingProducer{selves: selves})
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	if producerProps.Type() != reflect.TypeOf((*SelfReportingActor)(nil)).Elem() {
+		t.Fatalf("props type should be the actor type of the producer, but got %v", producerProps.Type())
+	}
+
+	ref, err := system.ActorOf(producerProps, "produced")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	ref.Tell("who")
+
+	select {
+	case self := <-selves:
+		if self.Path().String() != ref.Path().String() {
+			t.Fatalf("produced actor should be %s, but got %s", ref.Path(), self.Path())
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("produced actor did not receive the message")
+	}
+}
+
+func TestPropsFactoriesRejectMissingProducers(t *testing.T) {
+	if _, err := props.FromProducer(nil); err != props.ErrNoActorProducerSpecified {
+		t.Fatalf("FromProducer without producer should fail, but got %v", err)
+	}
+
+	if _, err := props.FromFunc(nil); err != props.ErrNoActorProducerSpecified {
+		t.Fatalf("FromFunc without func should fail, but got %v", err)
+	}
+}
+
+func TestPropsCarryTheirDeploy(t *testing.T) {
+	system := newTestActorSystem(t, "PropsDeploy")
+
+	echoProps, err := props.Create((*echoActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	pool := routing.NewRoundRobinPool(2)
+	deploy := akka.NewDeploy("/deployed", nil, pool, dispatch.DefaultBlockingIODispatcherId, "")
+
+	deployed := echoProps.WithDeploy(deploy)
+
+	if deployed.Deploy() != deploy {
+		t.Fatalf("props should carry the deploy as it is, but got %v", deployed.Deploy())
+	}
+
+	if deployed.Dispatcher() != dispatch.DefaultBlockingIODispatcherId || deployed.Mailbox() != dispatch.DefaultMailboxId || deployed.RouterConfig() != pool {
+		t.Fatalf("props should resolve the deployed settings, but got %s, %s and %v", deployed.Dispatcher(), deployed.Mailbox(), deployed.RouterConfig())
+	}
+
+	if echoProps.Dispatcher() != dispatch.DefaultDispatcherId {
+		t.Fatalf("WithDeploy should not change the original props, but got %s", echoProps.Dispatcher())
+	}
+
+	withMailbox := deployed.WithMailbox("akka.actor.default-mailbox")
+	if withMailbox.Dispatcher() != dispatch.DefaultBlockingIODispatcherId || withMailbox.RouterConfig() != pool || withMailbox.Deploy().Path() != "/deployed" {
+		t.Fatalf("WithMailbox should keep the rest of the deploy, but got %v", withMailbox.Deploy())
+	}
+
+	ref, err := system.ActorOf(echoProps.WithDeploy(akka.NewDeploy("", nil, nil, dispatch.DefaultBlockingIODispatcherId, "")), "blocking")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	if ref.(*LocalActorRef).Cell().Dispatcher() != system.dispatchers.Lookup(dispatch.DefaultBlockingIODispatcherId) {
+		t.Fatalf("actor should run on the deployed dispatcher")
+	}
+}
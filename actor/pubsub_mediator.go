@@ -0,0 +1,176 @@
+package actor
+
+import (
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+)
+
+// MediatorSubscribe makes Ref, or the sender without one, a subscriber of
+// Topic, the sender is answered with a MediatorSubscribeAck. The messages of
+// the mediator are prefixed so they are not mistaken for the EventStream ones
+type MediatorSubscribe struct {
+	Topic string
+	Ref   akka.ActorRef
+}
+
+type MediatorSubscribeAck struct {
+	Subscribe *MediatorSubscribe
+}
+
+// MediatorUnsubscribe removes Ref, or the sender without one, from the
+// subscribers of Topic, the sender is answered with a MediatorUnsubscribeAck
+type MediatorUnsubscribe struct {
+	Topic string
+	Ref   akka.ActorRef
+}
+
+type MediatorUnsubscribeAck struct {
+	Unsubscribe *MediatorUnsubscribe
+}
+
+// MediatorPublish tells Message to every subscriber of Topic, with the sender
+// of the MediatorPublish as its sender. It goes to the dead letters if Topic
+// has none
+type MediatorPublish struct {
+	Topic   string
+	Message interface{}
+}
+
+// MediatorCountSubscribers is answered with the number of subscribers of Topic
+type MediatorCountSubscribers struct {
+	Topic string
+}
+
+// PubSubMediator is the /system/pubsubMediator actor, it keeps the
+// subscribers of each topic of the local system and watches them, so a
+// terminated subscriber is removed from all of its topics
+type PubSubMediator struct {
+	*UntypedActor
+
+	topics map[string]*akka.ActorRefSet
+	// subscriptions counts the topics of each subscriber, it is unwatched
+	// once it has none left
+	subscriptions *akka.ActorRefMap
+}
+
+func (p *PubSubMediator) PreStart() (err error) {
+	p.topics = make(map[string]*akka.ActorRefSet)
+	p.subscriptions = akka.NewActorRefMap()
+	return
+}
+
+func (p *PubSubMediator) Receive(message interface{}) (handled bool, err error) {
+	switch msg := message.(type) {
+	case *MediatorSubscribe:
+		{
+			if err = p.subscribe(msg.Topic, p.refOrSender(msg.Ref)); err != nil {
+				return
+			}
+			p.Sender().Tell(&MediatorSubscribeAck{Subscribe: msg}, p.Self())
+		}
+	case *MediatorUnsubscribe:
+		{
+			p.unsubscribe(msg.Topic, p.refOrSender(msg.Ref))
+			p.Sender().Tell(&MediatorUnsubscribeAck{Unsubscribe: msg}, p.Self())
+		}
+	case *MediatorPublish:
+		{
+			p.publish(msg.Topic, msg.Message)
+		}
+	case *MediatorCountSubscribers:
+		{
+			count := 0
+			if subscribers, exist := p.topics[msg.Topic]; exist {
+				count = subscribers.Len()
+			}
+			p.Sender().Tell(count, p.Self())
+		}
+	case *Terminated:
+		{
+			for topic := range p.topics {
+				p.unsubscribe(topic, msg.Actor)
+			}
+		}
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (p *PubSubMediator) refOrSender(ref akka.ActorRef) akka.ActorRef {
+	if ref == nil {
+		return p.Sender()
+	}
+	return ref
+}
+
+func (p *PubSubMediator) subscribe(topic string, ref akka.ActorRef) (err error) {
+	subscribers, exist := p.topics[topic]
+	if !exist {
+		subscribers = akka.NewActorRefSet()
+		p.topics[topic] = subscribers
+	}
+
+	if !subscribers.Add(ref) {
+		return
+	}
+
+	count, watched := p.subscriptions.Get(ref)
+	if !watched {
+		if err = p.Context().Watch(ref); err != nil {
+			subscribers.Remove(ref)
+			return
+		}
+		count = 0
+	}
+	p.subscriptions.Put(ref, count.(int)+1)
+
+	return
+}
+
+func (p *PubSubMediator) unsubscribe(topic string, ref akka.ActorRef) {
+	subscribers, exist := p.topics[topic]
+	if !exist || !subscribers.Remove(ref) {
+		return
+	}
+
+	if subscribers.Len() == 0 {
+		delete(p.topics, topic)
+	}
+
+	count, _ := p.subscriptions.Get(ref)
+	if count.(int) > 1 {
+		p.subscriptions.Put(ref, count.(int)-1)
+		return
+	}
+
+	p.subscriptions.Delete(ref)
+	p.Context().Unwatch(ref)
+}
+
+func (p *PubSubMediator) publish(topic string, message interface{}) {
+	subscribers, exist := p.topics[topic]
+	if !exist {
+		p.Context().System().(*ActorSystemImpl).deadLetter(message, p.Sender(), p.Self())
+		return
+	}
+
+	for _, subscriber := range subscribers.Refs() {
+		subscriber.Tell(message, p.Sender())
+	}
+}
+
+// PubSubMediator is the mediator of the topics of the system, it is started
+// on first use
+func (p *ActorSystemImpl) PubSubMediator() (mediator akka.ActorRef, err error) {
+	p.pubSubOnce.Do(func() {
+		var mediatorProps akka.Props
+		if mediatorProps, p.pubSubErr = props.Create((*PubSubMediator)(nil)); p.pubSubErr != nil {
+			return
+		}
+		p.pubSubMediator, p.pubSubErr = p.SystemActorOf(mediatorProps, "pubsubMediator")
+	})
+
+	return p.pubSubMediator, p.pubSubErr
+}
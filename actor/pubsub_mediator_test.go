@@ -0,0 +1,103 @@
+package actor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka/actor/props"
+)
+
+func TestPublishFansOutToTheSubscribersOfTheTopic(t *testing.T) {
+	system := newTestActorSystem(t, "PubSubMediator")
+
+	mediator, err := system.PubSubMediator()
+	if err != nil {
+		t.Fatalf("start mediator failure: %s", err.Error())
+	}
+
+	if again, _ := system.PubSubMediator(); again != mediator {
+		t.Fatalf("the system should have a single mediator, but got %s and %s", mediator.Path(), again.Path())
+	}
+
+	first, second, other := make(chan interface{}, 10), make(chan interface{}, 10), make(chan interface{}, 10)
+	subscribers := map[string]chan interface{}{"first": first, "second": second, "other": other}
+
+	refs := map[string]*LocalActorRef{}
+	for name, received := range subscribers {
+		recordingProps, err := props.Create((*RecordingActor)(nil), received)
+		if err != nil {
+			t.Fatalf("create props failure: %s", err.Error())
+		}
+
+		ref, err := system.ActorOf(recordingProps, name)
+		if err != nil {
+			t.Fatalf("create actor failure: %s", err.Error())
+		}
+		refs[name] = ref.(*LocalActorRef)
+	}
+
+	inbox := NewInbox(system)
+	defer inbox.Stop()
+
+	for _, subscribe := range []*MediatorSubscribe{{Topic: "news", Ref: refs["first"]}, {Topic: "news", Ref: refs["second"]}, {Topic: "sports", Ref: refs["other"]}} {
+		inbox.Send(mediator, subscribe)
+		if ack, err := inbox.Receive(3 * time.Second); err != nil || ack.(*MediatorSubscribeAck).Subscribe != subscribe {
+			t.Fatalf("subscribe should be acknowledged, but got %v, %v", ack, err)
+		}
+	}
+
+	inbox.Send(mediator, &MediatorPublish{Topic: "news", Message: "extra"})
+
+	for _, received := range []chan interface{}{first, second} {
+		expectReceived(t, received, "extra")
+	}
+
+	select {
+	case msg := <-other:
+		t.Fatalf("subscriber of another topic should not receive, but got %v", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	inbox.Watch(refs["first"])
+	refs["first"].Tell(&PoisonPill{})
+	if _, err = inbox.Receive(3 * time.Second); err != nil {
+		t.Fatalf("subscriber was not terminated: %s", err.Error())
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for count := 0; count != 1; {
+		inbox.Send(mediator, &MediatorCountSubscribers{Topic: "news"})
+		reply, err := inbox.Receive(3 * time.Second)
+		if err != nil {
+			t.Fatalf("count subscribers failure: %s", err.Error())
+		}
+
+		if count = reply.(int); count != 1 {
+			if time.Now().After(deadline) {
+				t.Fatalf("terminated subscriber should be removed, but the topic has %d", count)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	inbox.Send(mediator, &MediatorUnsubscribe{Topic: "sports", Ref: refs["other"]})
+	if _, err = inbox.Receive(3 * time.Second); err != nil {
+		t.Fatalf("unsubscribe should be acknowledged: %s", err.Error())
+	}
+
+	inbox.Send(mediator, &MediatorPublish{Topic: "news", Message: "late"})
+	inbox.Send(mediator, &MediatorPublish{Topic: "sports", Message: "late"})
+
+	expectReceived(t, second, "late")
+
+	select {
+	case msg := <-other:
+		t.Fatalf("unsubscribed actor should not receive, but got %v", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	inbox.Send(mediator, &MediatorCountSubscribers{Topic: "sports"})
+	if count, err := inbox.Receive(3 * time.Second); err != nil || count != 0 {
+		t.Fatalf("unsubscribed topic should have no subscribers, but got %v, %v", count, err)
+	}
+}
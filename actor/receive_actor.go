@@ -17,12 +17,17 @@ func NewReceiveActor(actor akka.Actor, initFn akka.InitFunc) *ReceiveActor {
 
 	receiveActor := &ReceiveActor{
 		receiveFuns: cmap.New(),
+		initFn:      initFn,
 	}
 
 	return receiveActor
 }
 
 func (p *ReceiveActor) Init() error {
+	return p.construct()
+}
+
+func (p *ReceiveActor) construct() error {
 	if p.initFn != nil {
 		return p.initFn()
 	}
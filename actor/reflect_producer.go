@@ -7,6 +7,7 @@ import (
 	"github.com/go-akka/akka/actor/props"
 	"reflect"
 	"strings"
+	"sync"
 )
 
 type ActorBaseInitFunc func(instance interface{}) (err error)
@@ -29,6 +30,9 @@ type _ReflectProducer struct {
 	typ      reflect.Type
 	args     []interface{}
 	baseType reflect.Type
+
+	// instances are the zeroed instances released by pooled props
+	instances sync.Pool
 }
 
 func newReflectProducer(v interface{}, args ...interface{}) (producer props.IndirectActorProducer, err error) {
@@ -80,7 +84,9 @@ func (p *_ReflectProducer) Init(v interface{}, args ...interface{}) (err error)
 func (p *_ReflectProducer) Produce() (actor akka.Actor, err error) {
 
 	var val reflect.Value
-	if val, err = createInstanceByType(p.typ, p.args...); err != nil {
+	if pooled := p.instances.Get(); pooled != nil {
+		val = reflect.ValueOf(pooled)
+	} else if val, err = createInstanceByType(p.typ, p.args...); err != nil {
 		return
 	}
 
@@ -98,11 +104,15 @@ func (p *_ReflectProducer) Produce() (actor akka.Actor, err error) {
 
 			if p.baseType == unTypedActorPtrType {
 				untypedActor := NewUntypedActor(receiver, initFunc)
-				combine(val, unTypedActorPtrType, untypedActor)
+				if err = combine(val, unTypedActorPtrType, untypedActor); err != nil {
+					return
+				}
 				actor = receiver
 			} else if p.baseType == receiveActorPtrType {
 				receiveActor := NewReceiveActor(receiver, initFunc)
-				combine(val, receiveActorPtrType, receiveActor)
+				if err = combine(val, receiveActorPtrType, receiveActor); err != nil {
+					return
+				}
 				actor = receiver
 			}
 		}
@@ -119,6 +129,22 @@ func (p *_ReflectProducer) ActorType() reflect.Type {
 	return p.typ
 }
 
+// Release resets and zeroes the stopped instance before pooling it, so the
+// next incarnation starts from the same state as a newly allocated one
+func (p *_ReflectProducer) Release(actor akka.Actor) {
+	val := reflect.ValueOf(actor)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Type().Elem() != p.typ {
+		return
+	}
+
+	if resetter, ok := actor.(akka.Resetter); ok {
+		resetter.Reset()
+	}
+
+	val.Elem().Set(reflect.Zero(p.typ))
+	p.instances.Put(actor)
+}
+
 func (p *_ReflectProducer) genInitFunc(val reflect.Value) akka.InitFunc {
 	return func() error {
 		return initInstance(val, p.args...)
@@ -133,6 +159,27 @@ func getTypeName(t reflect.Type) string {
 	return s[strings.LastIndex(s, ".")+1:]
 }
 
+// missingInitFunc reports whether the props give constructor args to an actor
+// type without an init func, they are ignored unless strict-init-funcs is on
+func missingInitFunc(actorProps akka.Props) bool {
+	if withProducer, ok := actorProps.(*props.Props); ok {
+		if producer, ok := withProducer.Producer().(*_ReflectProducer); ok {
+			return len(producer.args) > 0 && !hasInitFunc(reflect.PtrTo(producer.typ))
+		}
+	}
+	return false
+}
+
+func hasInitFunc(typ reflect.Type) bool {
+	constructName := getTypeName(typ)
+	if len(constructName) == 0 {
+		return false
+	}
+
+	_, exist := typ.MethodByName(constructName)
+	return exist
+}
+
 func initInstance(val reflect.Value, args ...interface{}) (err error) {
 
 	constructName := getTypeName(val.Type())
@@ -146,25 +193,37 @@ func initInstance(val reflect.Value, args ...interface{}) (err error) {
 		return
 	}
 
-	outNum := methodVal.Type().NumOut()
+	if err = checkFuncReturnsError(methodVal.Type()); err != nil {
+		return
+	}
+
+	var valArgs []reflect.Value
+	for _, arg := range args {
+		valArgs = append(valArgs, reflect.ValueOf(arg))
+	}
+
+	return callFuncReturningError(methodVal, valArgs...)
+}
+
+func checkFuncReturnsError(fnType reflect.Type) (err error) {
+	outNum := fnType.NumOut()
 	if outNum > 1 {
 		err = ErrBadActorInitFuncOutNumber
 		return
 	}
 
 	if outNum == 1 {
-		if methodVal.Type().Out(0) != errorType {
+		if fnType.Out(0) != errorType {
 			err = ErrBadActorInitFuncOutType
 			return
 		}
 	}
 
-	var valArgs []reflect.Value
-	for _, arg := range args {
-		valArgs = append(valArgs, reflect.ValueOf(arg))
-	}
+	return
+}
 
-	fnRetVals := methodVal.Call(valArgs)
+func callFuncReturningError(fnVal reflect.Value, args ...reflect.Value) (err error) {
+	fnRetVals := fnVal.Call(args)
 
 	if len(fnRetVals) > 0 &&
 		fnRetVals[0].IsValid() &&
@@ -187,28 +246,85 @@ func isCombinedActorBase(v reflect.Type) (isCombined bool) {
 }
 
 func isCombined(v reflect.Type, combineType reflect.Type) bool {
+	_, found := combinedIndex(v, combineType, map[reflect.Type]bool{})
+	return found
+}
+
+// combinedIndex is the index path of the field holding combineType, the base
+// may be embedded by pointer or by value, directly or in embedded structs
+func combinedIndex(v reflect.Type, combineType reflect.Type, visited map[reflect.Type]bool) (index []int, found bool) {
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct || visited[v] {
+		return
+	}
+	visited[v] = true
+
 	for i := 0; i < v.NumField(); i++ {
-		if v.Field(i).Type == combineType {
-			return true
+		field := v.Field(i)
+		if field.Type == combineType ||
+			(field.Anonymous && field.Type == combineType.Elem()) {
+			return []int{i}, true
 		}
 	}
 
-	return false
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if !field.Anonymous {
+			continue
+		}
+
+		// an unexported embedded pointer could not be allocated
+		if field.Type.Kind() == reflect.Ptr && len(field.PkgPath) > 0 {
+			continue
+		}
+
+		if subIndex, subFound := combinedIndex(field.Type, combineType, visited); subFound {
+			return append([]int{i}, subIndex...), true
+		}
+	}
+
+	return
 }
 
 func combine(val reflect.Value, combineType reflect.Type, combineValue interface{}) (err error) {
 
-	typ := val.Elem().Type()
+	index, found := combinedIndex(val.Elem().Type(), combineType, map[reflect.Type]bool{})
+	if !found {
+		err = fmt.Errorf("struct should combine %s", combineType.String())
+		return
+	}
+
+	field := val.Elem()
+	for i, fieldIndex := range index {
+		field = field.Field(fieldIndex)
 
-	for i := 0; i < typ.NumField(); i++ {
-		if typ.Field(i).Type == combineType {
-			actorVal := reflect.ValueOf(combineValue)
-			val.Elem().Field(i).Set(actorVal)
-			return
+		if i == len(index)-1 {
+			break
+		}
+
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				field.Set(reflect.New(field.Type().Elem()))
+			}
+			field = field.Elem()
 		}
 	}
 
-	err = fmt.Errorf("struct should combine %s", combineType.String())
+	if !field.CanSet() {
+		err = fmt.Errorf("struct should combine %s in an exported field", combineType.String())
+		return
+	}
+
+	actorVal := reflect.ValueOf(combineValue)
+	if field.Type() != combineType {
+		actorVal = actorVal.Elem()
+	}
+
+	field.Set(actorVal)
+
 	return
 }
 
@@ -2,23 +2,27 @@ package actor
 
 import (
 	"errors"
-	"github.com/go-akka/akka"
+	"strings"
 	"testing"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/configuration"
 )
 
-type testUntypedActor struct {
+type TestUntypedActor struct {
 	*UntypedActor
 
 	inited bool
 }
 
-func (p *testUntypedActor) Init(arg1, arg2 int) error {
+func (p *TestUntypedActor) TestUntypedActor(arg1, arg2 int) error {
 	p.inited = true
 
 	return nil
 }
 
-func (p *testUntypedActor) Receive(message interface{}) (handled bool, err error) {
+func (p *TestUntypedActor) Receive(message interface{}) (handled bool, err error) {
 	if p.inited == false {
 		err = errors.New("testUntypedActor args init failure")
 		return
@@ -27,7 +31,7 @@ func (p *testUntypedActor) Receive(message interface{}) (handled bool, err error
 	return
 }
 
-type testReceiveActor struct {
+type TestReceiveActor struct {
 	*ReceiveActor
 
 	inited         bool
@@ -35,7 +39,7 @@ type testReceiveActor struct {
 	intReceived    bool
 }
 
-func (p *testReceiveActor) Init(arg1, arg2 int) error {
+func (p *TestReceiveActor) TestReceiveActor(arg1, arg2 int) error {
 	p.inited = true
 
 	p.SmartReceive(func(message string) {
@@ -49,23 +53,42 @@ func (p *testReceiveActor) Init(arg1, arg2 int) error {
 	return nil
 }
 
-func TestCreateUntypedActor(t *testing.T) {
+type noInitUntypedActor struct {
+	*UntypedActor
+}
+
+func (p *noInitUntypedActor) Receive(message interface{}) (handled bool, err error) {
+	handled = true
+	return
+}
+
+func produceActor(t *testing.T, v interface{}, args ...interface{}) akka.Actor {
 	var err error
-	var producer IndirectActorProducer
+	var producer props.IndirectActorProducer
 
-	producer, err = _CreateProducer((*testUntypedActor)(nil), 1, 2)
+	producer, err = newReflectProducer(v, args...)
 
 	if err != nil {
 		t.Fatalf("producer create failure: %s", err.Error())
-		return
 	}
 
 	var actor akka.Actor
 	if actor, err = producer.Produce(); err != nil {
 		t.Fatalf("produce actor failure: %s", err.Error())
-		return
 	}
 
+	if c, ok := actor.(constructer); ok {
+		if err = c.construct(); err != nil {
+			t.Fatalf("construct actor failure: %s", err.Error())
+		}
+	}
+
+	return actor
+}
+
+func TestCreateUntypedActor(t *testing.T) {
+	actor := produceActor(t, (*TestUntypedActor)(nil), 1, 2)
+
 	if handled, _ := actor.Receive("hello"); !handled {
 		t.Fatalf("testUntypedActor Receive unhandled")
 		return
@@ -73,29 +96,116 @@ func TestCreateUntypedActor(t *testing.T) {
 }
 
 func TestCreateReciveActor(t *testing.T) {
-	var err error
-	var producer IndirectActorProducer
-
-	producer, err = _CreateProducer((*testReceiveActor)(nil), 1, 2)
+	actor := produceActor(t, (*TestReceiveActor)(nil), 1, 2)
 
-	if err != nil {
-		t.Fatalf("producer create failure: %s", err.Error())
+	if handled, _ := actor.Receive("hello"); !handled {
+		t.Fatalf("testReceiveActor Receive unhandled")
 		return
 	}
 
-	var actor akka.Actor
-	if actor, err = producer.Produce(); err != nil {
-		t.Fatalf("produce actor failure: %s", err.Error())
+	if handled, _ := actor.Receive(32); !handled {
 		return
 	}
 
+}
+
+func TestStrictInitFuncsRequireAnInitFuncForArgs(t *testing.T) {
+	config := strings.Replace(testConfig, "actor {", "actor {\n\t\tstrict-init-funcs = on", 1)
+
+	strict, err := NewActorSystem("StrictInitFuncs", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	withArgs, err := props.Create((*noInitUntypedActor)(nil), "arg")
+	if err != nil {
+		t.Fatalf("props creation should not depend on the system, but got %v", err)
+	}
+
+	if _, err = strict.ActorOf(withArgs, "args"); !errors.Is(err, ErrActorInitFuncNotFound) {
+		t.Fatalf("strict system should fail with ErrActorInitFuncNotFound, but got %v", err)
+	}
+
+	withoutArgs, _ := props.Create((*noInitUntypedActor)(nil))
+	if _, err = strict.ActorOf(withoutArgs, "noargs"); err != nil {
+		t.Fatalf("strict system should accept an actor without init func when no args given, but got %v", err)
+	}
+
+	withInitFunc, _ := props.Create((*TestUntypedActor)(nil), 1, 2)
+	if _, err = strict.ActorOf(withInitFunc, "initfunc"); err != nil {
+		t.Fatalf("strict system should accept an actor with init func, but got %v", err)
+	}
+
+	if _, err = newTestActorSystem(t, "LenientInitFuncs").ActorOf(withArgs, "args"); err != nil {
+		t.Fatalf("system without strict-init-funcs should ignore the missing init func, but got %v", err)
+	}
+}
+
+type receiveBase struct {
+	*ReceiveActor
+}
+
+type ReceiveBase struct {
+	receiveBase
+}
+
+// DeepReceiveActor combines the ReceiveActor two embedded structs down, the
+// outer one by pointer
+type DeepReceiveActor struct {
+	*ReceiveBase
+
+	inited bool
+}
+
+func (p *DeepReceiveActor) DeepReceiveActor() {
+	p.inited = true
+
+	p.SmartReceive(func(message string) {})
+}
+
+type ValueUntypedActor struct {
+	UntypedActor
+}
+
+func (p *ValueUntypedActor) Receive(message interface{}) (handled bool, err error) {
+	return true, nil
+}
+
+type unexportedPointerBase struct {
+	*UntypedActor
+}
+
+type HiddenBaseActor struct {
+	*unexportedPointerBase
+}
+
+func TestCreateDeeplyEmbeddedReceiveActor(t *testing.T) {
+	actor := produceActor(t, (*DeepReceiveActor)(nil))
+
+	deep, ok := actor.(*DeepReceiveActor)
+	if !ok {
+		t.Fatalf("produced actor should be *DeepReceiveActor, but got %T", actor)
+	}
+
+	if deep.ReceiveBase == nil || deep.ReceiveActor == nil || !deep.inited {
+		t.Fatalf("the deeply embedded ReceiveActor should be combined and inited")
+	}
+
 	if handled, _ := actor.Receive("hello"); !handled {
-		t.Fatalf("testReceiveActor Receive unhandled")
-		return
+		t.Fatalf("deeply embedded ReceiveActor should handle the string")
 	}
+}
 
-	if handled, _ := actor.Receive(32); !handled {
-		return
+func TestCreateActorEmbeddingUntypedActorByValue(t *testing.T) {
+	actor := produceActor(t, (*ValueUntypedActor)(nil))
+
+	if actor.(*ValueUntypedActor).actor != actor {
+		t.Fatalf("the UntypedActor embedded by value should wrap the produced actor")
 	}
+}
 
+func TestCombineRejectsUnexportedEmbeddedPointers(t *testing.T) {
+	if _, err := newReflectProducer((*HiddenBaseActor)(nil)); err != ErrNoUntypedActorOrReceiveActorCombind {
+		t.Fatalf("a base behind an unexported pointer can not be combined, but got %v", err)
+	}
 }
@@ -22,7 +22,11 @@ func (p *RootGuardianActor) Receive(message interface{}) (handled bool, err erro
 	return
 }
 
-func (p *RootGuardianActor) PreRestart(cause error, message interface{}) {
+func (p *RootGuardianActor) SupervisorStrategy() akka.SupervisorStrategy {
+	return newGuardianSupervisorStrategy(p.Context().System().(*ActorSystemImpl), StoppingStrategy)
+}
+
+func (p *RootGuardianActor) PreRestart(cause error, message interface{}) (err error) {
 	return
 }
 
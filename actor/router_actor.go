@@ -0,0 +1,97 @@
+package actor
+
+import (
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/routing"
+)
+
+// RouterActor is created in place of an actor whose props carry a pool router
+// config, it creates the routees as its children and forwards every message
+// by the routing logic of the pool
+type RouterActor struct {
+	*UntypedActor
+
+	pool        akka.Pool
+	routeeProps akka.Props
+	router      routing.Router
+}
+
+func (p *RouterActor) RouterActor(pool akka.Pool, routeeProps akka.Props) {
+	p.pool = pool
+	p.routeeProps = routeeProps
+}
+
+func (p *RouterActor) PreStart() (err error) {
+	if p.router, err = routing.NewRouter(p.pool.CreateRoutingLogic(p.Context().System())); err != nil {
+		return
+	}
+
+	if logic, ok := p.router.Logic().(routing.RouterAwareRoutingLogic); ok {
+		logic.SetRouter(p.Self())
+	}
+
+	for i := 0; i < p.pool.NrOfInstances(); i++ {
+		var routee akka.ActorRef
+		if routee, err = p.Context().ActorOf(p.routeeProps, ""); err != nil {
+			return
+		}
+
+		if err = p.Context().Watch(routee); err != nil {
+			return
+		}
+
+		p.router = p.router.AddRoutees(routing.ActorRefRoutee{Ref: routee})
+	}
+
+	return
+}
+
+// PostStop hands the work still buffered by a pulling routing logic to the
+// dead letters, no routee is left to take it
+func (p *RouterActor) PostStop() (err error) {
+	logic, ok := p.router.Logic().(routing.PullingRoutingLogic)
+	if !ok {
+		return
+	}
+
+	deadLetters := p.Context().System().DeadLetters()
+	for _, envelope := range logic.DrainWork() {
+		deadLetters.Tell(akka.NewDeadLetter(envelope.Message, envelope.Sender, p.Self()), envelope.Sender)
+	}
+
+	return
+}
+
+func (p *RouterActor) Router() routing.Router {
+	return p.router
+}
+
+func (p *RouterActor) Receive(message interface{}) (handled bool, err error) {
+	switch msg := message.(type) {
+	case *Terminated:
+		{
+			p.router = p.router.RemoveRoutee(routing.ActorRefRoutee{Ref: msg.Actor})
+			if logic, ok := p.router.Logic().(routing.PullingRoutingLogic); ok {
+				logic.RouteeRemoved(routing.ActorRefRoutee{Ref: msg.Actor})
+			}
+			if len(p.router.Routees()) == 0 && p.pool.StopRouterWhenAllRouteesRemoved() {
+				p.Self().(akka.InternalActorRef).Stop()
+			}
+		}
+	case *routing.WorkReady:
+		{
+			logic, ok := p.router.Logic().(routing.PullingRoutingLogic)
+			if !ok {
+				return false, nil
+			}
+			logic.RouteeReady(routing.ActorRefRoutee{Ref: p.Sender()})
+		}
+	default:
+		if p.pool.IsManagementMessage(message) {
+			return false, nil
+		}
+		p.router.Route(message, p.Sender())
+	}
+
+	return true, nil
+}
@@ -0,0 +1,462 @@
+package actor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/configuration"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/pattern"
+	"github.com/go-akka/akka/routing"
+)
+
+type RouteeProbeActor struct {
+	*UntypedActor
+
+	received chan akka.ActorPath
+}
+
+func (p *RouteeProbeActor) RouteeProbeActor(received chan akka.ActorPath) {
+	p.received = received
+}
+
+func (p *RouteeProbeActor) Receive(message interface{}) (handled bool, err error) {
+	p.received <- p.Self().Path()
+	return true, nil
+}
+
+func TestDeploymentConfigTurnsActorIntoRoundRobinPool(t *testing.T) {
+	config := strings.Replace(testConfig, "actor {", `actor {
+		deployment {
+			/workers {
+				router = round-robin-pool
+				nr-of-instances = 3
+			}
+		}
+`, 1)
+
+	system, err := NewActorSystem("DeploymentRoundRobin", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	received := make(chan akka.ActorPath, 10)
+
+	probeProps, err := props.Create((*RouteeProbeActor)(nil), received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	workers, err := system.ActorOf(probeProps, "workers")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	for i := 0; i < 6; i++ {
+		workers.Tell(i)
+	}
+
+	counts := map[string]int{}
+	for i := 0; i < 6; i++ {
+		select {
+		case path := <-received:
+			{
+				if path.Parent().Name() != "workers" {
+					t.Fatalf("message should be handled by a routee of workers, but got %s", path)
+				}
+				counts[path.Name()]++
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("only %d of 6 messages were received", i)
+		}
+	}
+
+	if len(counts) != 3 {
+		t.Fatalf("messages should be spread over 3 routees, but got %v", counts)
+	}
+
+	for name, count := range counts {
+		if count != 2 {
+			t.Fatalf("round robin should give each routee 2 messages, but %s got %d", name, count)
+		}
+	}
+}
+
+func TestActorWithoutDeploymentIsNotRouted(t *testing.T) {
+	system := newTestActorSystem(t, "DeploymentNone")
+
+	received := make(chan akka.ActorPath, 1)
+
+	probeProps, err := props.Create((*RouteeProbeActor)(nil), received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	probe, err := system.ActorOf(probeProps, "probe")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	probe.Tell("ping")
+
+	select {
+	case path := <-received:
+		if path.Name() != "probe" {
+			t.Fatalf("message should be handled by the actor itself, but got %s", path)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("message was not received")
+	}
+}
+
+func TestWithRouterCreatesRoundRobinPool(t *testing.T) {
+	system := newTestActorSystem(t, "WithRouterRoundRobin")
+
+	received := make(chan akka.ActorPath, 10)
+
+	probeProps, err := props.Create((*RouteeProbeActor)(nil), received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	routed := probeProps.WithRouter(routing.NewRoundRobinPool(3))
+
+	if _, isPool := probeProps.RouterConfig().(akka.Pool); isPool {
+		t.Fatalf("WithRouter should not change the original props")
+	}
+
+	router, err := system.ActorOf(routed, "r")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	for i := 0; i < 6; i++ {
+		router.Tell(i)
+	}
+
+	counts := map[string]int{}
+	for i := 0; i < 6; i++ {
+		select {
+		case path := <-received:
+			{
+				if path.Parent().Name() != "r" {
+					t.Fatalf("message should be handled by a routee of r, but got %s", path)
+				}
+				counts[path.Name()]++
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("only %d of 6 messages were received", i)
+		}
+	}
+
+	if len(counts) != 3 {
+		t.Fatalf("messages should be spread over 3 routees, but got %v", counts)
+	}
+
+	for name, count := range counts {
+		if count != 2 {
+			t.Fatalf("round robin should give each routee 2 messages, but %s got %d", name, count)
+		}
+	}
+}
+
+// TailChopRouteeActor replies with its name, the first routee of the pool
+// replies only after slowReply
+type TailChopRouteeActor struct {
+	*UntypedActor
+}
+
+const slowReply = time.Second
+
+func (p *TailChopRouteeActor) Receive(message interface{}) (handled bool, err error) {
+	sender, name := p.Sender(), p.Self().Path().Name()
+	if name == "$a" {
+		go func() {
+			time.Sleep(slowReply)
+			sender.Tell(name)
+		}()
+		return true, nil
+	}
+
+	sender.Tell(name)
+	return true, nil
+}
+
+func TestTailChoppingPoolReturnsTheFirstReply(t *testing.T) {
+	system := newTestActorSystem(t, "TailChopping")
+
+	routeeProps, err := props.Create((*TailChopRouteeActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	router, err := system.ActorOf(routeeProps.WithRouter(routing.NewTailChoppingPool(2, 3*time.Second, 50*time.Millisecond)), "chopper")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	start := time.Now()
+
+	reply, err := pattern.Ask(router, "question", 3*time.Second)
+	if err != nil {
+		t.Fatalf("ask failure: %s", err.Error())
+	}
+
+	if reply != "$b" {
+		t.Fatalf("reply should come from the fast second routee, but got %v", reply)
+	}
+
+	if elapsed := time.Since(start); elapsed >= slowReply {
+		t.Fatalf("reply should not wait for the slow routee, but took %s", elapsed)
+	}
+}
+
+// TailChopClientActor asks the router it is told, and records the sender of
+// the reply
+type TailChopClientActor struct {
+	*UntypedActor
+
+	repliedBy chan akka.ActorRef
+}
+
+func (p *TailChopClientActor) TailChopClientActor(repliedBy chan akka.ActorRef) {
+	p.repliedBy = repliedBy
+}
+
+func (p *TailChopClientActor) Receive(message interface{}) (handled bool, err error) {
+	if router, ok := message.(akka.ActorRef); ok {
+		return true, router.Tell("question", p.Self())
+	}
+
+	p.repliedBy <- p.Sender()
+	return true, nil
+}
+
+func TestTailChoppingPoolRepliesFromTheRouter(t *testing.T) {
+	system := newTestActorSystem(t, "TailChoppingSender")
+
+	routeeProps, err := props.Create((*TailChopRouteeActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	router, err := system.ActorOf(routeeProps.WithRouter(routing.NewTailChoppingPool(2, 3*time.Second, 50*time.Millisecond)), "chopper")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	repliedBy := make(chan akka.ActorRef, 1)
+	clientProps, err := props.Create((*TailChopClientActor)(nil), repliedBy)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	client, err := system.ActorOf(clientProps, "client")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	client.Tell(router)
+
+	select {
+	case sender := <-repliedBy:
+		if sender == nil || sender.CompareTo(router) != 0 {
+			t.Fatalf("the reply should be sent by the router %s, but got %v", router.Path(), sender)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("the client got no reply")
+	}
+}
+
+type sessionMessage struct {
+	session string
+}
+
+func (p *sessionMessage) AffinityKey() interface{} {
+	return p.session
+}
+
+func TestDeploymentConfigSelectsTheAffinityPool(t *testing.T) {
+	config := strings.Replace(testConfig, "actor {", `actor {
+		deployment {
+			/sessions {
+				router = affinity-pool
+				nr-of-instances = 3
+				affinity-router.max-keys = 100
+			}
+		}
+`, 1)
+
+	system, err := NewActorSystem("DeploymentAffinity", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	received := make(chan akka.ActorPath, 10)
+
+	probeProps, err := props.Create((*RouteeProbeActor)(nil), received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	sessions, err := system.ActorOf(probeProps, "sessions")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	routees := map[string]string{}
+	for _, session := range []string{"a", "b", "c"} {
+		for i := 0; i < 10; i++ {
+			sessions.Tell(&sessionMessage{session: session})
+		}
+
+		for i := 0; i < 10; i++ {
+			select {
+			case path := <-received:
+				{
+					if path.Parent().Name() != "sessions" {
+						t.Fatalf("message should be handled by a routee of sessions, but got %s", path)
+					}
+
+					if routee, exist := routees[session]; exist && routee != path.Name() {
+						t.Fatalf("session %s should stick to routee %s, but also went to %s", session, routee, path.Name())
+					}
+					routees[session] = path.Name()
+				}
+			case <-time.After(3 * time.Second):
+				t.Fatalf("only %d of 10 messages of session %s were received", i, session)
+			}
+		}
+	}
+
+	if routees["a"] == routees["b"] || routees["b"] == routees["c"] || routees["a"] == routees["c"] {
+		t.Fatalf("new sessions should go to the routee with the fewest, but got %v", routees)
+	}
+}
+
+type pulledTask struct {
+	cost time.Duration
+}
+
+type pulledTaskDone struct {
+	routee string
+	cost   time.Duration
+}
+
+// PullingWorkerActor asks its router for work when started and after every
+// task it finished
+type PullingWorkerActor struct {
+	*UntypedActor
+
+	done chan pulledTaskDone
+}
+
+func (p *PullingWorkerActor) PullingWorkerActor(done chan pulledTaskDone) {
+	p.done = done
+}
+
+func (p *PullingWorkerActor) PreStart() (err error) {
+	return p.Context().Parent().Tell(&routing.WorkReady{}, p.Self())
+}
+
+func (p *PullingWorkerActor) Receive(message interface{}) (handled bool, err error) {
+	task, ok := message.(*pulledTask)
+	if !ok {
+		return false, nil
+	}
+
+	time.Sleep(task.cost)
+	p.done <- pulledTaskDone{routee: p.Self().Path().Name(), cost: task.cost}
+
+	return true, p.Context().Parent().Tell(&routing.WorkReady{}, p.Self())
+}
+
+func TestWorkPullingPoolKeepsWorkAwayFromTheSlowRoutee(t *testing.T) {
+	system := newTestActorSystem(t, "WorkPulling")
+
+	done := make(chan pulledTaskDone, 20)
+
+	workerProps, err := props.Create((*PullingWorkerActor)(nil), done)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	router, err := system.ActorOf(workerProps.WithRouter(routing.NewWorkPullingPool(2)), "pulling")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	router.Tell(&pulledTask{cost: 500 * time.Millisecond})
+	for i := 0; i < 10; i++ {
+		router.Tell(&pulledTask{cost: time.Millisecond})
+	}
+
+	counts := map[string]int{}
+	var slowRoutee string
+	for i := 0; i < 11; i++ {
+		select {
+		case finished := <-done:
+			{
+				counts[finished.routee]++
+				if finished.cost > time.Millisecond {
+					slowRoutee = finished.routee
+				}
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("only %d of 11 tasks were finished", i)
+		}
+	}
+
+	if counts[slowRoutee] != 1 {
+		t.Fatalf("the routee busy with the slow task should not get a backlog, but finished %v", counts)
+	}
+}
+
+func TestWorkPullingPoolDeadLettersTheBufferedWorkOnStop(t *testing.T) {
+	system := newTestActorSystem(t, "WorkPullingStop")
+	collector := newEventCollector(system, akka.DeadLetter{})
+
+	// echo routees never ask for work, all of it stays buffered
+	echoProps, err := props.Create((*echoActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	router, err := system.ActorOf(echoProps.WithRouter(routing.NewWorkPullingPool(2)), "idle")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	for i := 0; i < 3; i++ {
+		router.Tell(i)
+	}
+
+	ask, err := pattern.Ask(router, "last", 100*time.Millisecond)
+	if err == nil {
+		t.Fatalf("idle routees should not reply, but got %v", ask)
+	}
+
+	router.(akka.InternalActorRef).Stop()
+
+	var lost []interface{}
+	for len(lost) < 4 {
+		select {
+		case e := <-collector.events:
+			{
+				if deadLetter := e.(akka.DeadLetter); deadLetter.Recipient() == router {
+					lost = append(lost, deadLetter.Message())
+				}
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("buffered work should go to the dead letters on stop, but got %v", lost)
+		}
+	}
+
+	if !reflect.DeepEqual(lost[:3], []interface{}{0, 1, 2}) || lost[3] != "last" {
+		t.Fatalf("buffered work should be dead-lettered in order, but got %v", lost)
+	}
+}
@@ -0,0 +1,126 @@
+package actor
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/configuration"
+)
+
+var (
+	_ akka.Scheduler         = (*DefaultScheduler)(nil)
+	_ akka.AdvancedScheduler = (*defaultAdvancedScheduler)(nil)
+)
+
+type DefaultScheduler struct {
+	config *configuration.Config
+
+	random       *rand.Rand
+	randomLocker sync.Mutex
+}
+
+type defaultAdvancedScheduler DefaultScheduler
+
+func NewDefaultScheduler() *DefaultScheduler {
+	return &DefaultScheduler{}
+}
+
+// SetRandom replaces the jitter source, mainly so tests can use a fixed seed
+func (p *DefaultScheduler) SetRandom(random *rand.Rand) {
+	p.randomLocker.Lock()
+	defer p.randomLocker.Unlock()
+
+	p.random = random
+}
+
+func (p *DefaultScheduler) Construct(config *configuration.Config) {
+	p.config = config
+}
+
+func (p *DefaultScheduler) Advanced() akka.AdvancedScheduler {
+	return (*defaultAdvancedScheduler)(p)
+}
+
+func (p *DefaultScheduler) ScheduleTellOnce(delay time.Duration, receiver akka.CanTell, message interface{}, sender akka.ActorRef, cancelable akka.Cancelable) {
+	scheduleOnce(delay, func() { receiver.Tell(message, sender) }, cancelable)
+}
+
+func (p *DefaultScheduler) ScheduleRepeatedly(delay time.Duration, interval time.Duration, receiver akka.CanTell, message interface{}, sender akka.ActorRef, cancelable akka.Cancelable) {
+	scheduleRepeatedly(delay, interval, func() { receiver.Tell(message, sender) }, cancelable)
+}
+
+func (p *defaultAdvancedScheduler) ScheduleOnce(delay time.Duration, action akka.Action, cancelable akka.Cancelable) {
+	scheduleOnce(delay, action.Action, cancelable)
+}
+
+func (p *defaultAdvancedScheduler) ScheduleRepeatedly(initialDelay time.Duration, interval time.Duration, action akka.Action, cancelable akka.Cancelable) {
+	scheduleRepeatedly(initialDelay, interval, action.Action, cancelable)
+}
+
+// ScheduleOnceWithJitter fires the action once at delay ± random(jitter), the
+// resulting delay never goes below zero
+func (p *defaultAdvancedScheduler) ScheduleOnceWithJitter(delay time.Duration, jitter time.Duration, action akka.Action, cancelable akka.Cancelable) {
+	scheduleOnce((*DefaultScheduler)(p).jitteredDelay(delay, jitter), action.Action, cancelable)
+}
+
+func (p *DefaultScheduler) jitteredDelay(delay time.Duration, jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return delay
+	}
+
+	p.randomLocker.Lock()
+	if p.random == nil {
+		p.random = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	offset := time.Duration(p.random.Int63n(2*int64(jitter)+1)) - jitter
+	p.randomLocker.Unlock()
+
+	if delay += offset; delay < 0 {
+		delay = 0
+	}
+
+	return delay
+}
+
+func scheduleOnce(delay time.Duration, fn func(), cancelable akka.Cancelable) {
+	timer := time.AfterFunc(delay, func() {
+		if cancelable != nil && cancelable.IsCancellationRequested() {
+			return
+		}
+		fn()
+	})
+
+	if c, ok := cancelable.(*Cancelable); ok {
+		c.register(func() { timer.Stop() })
+	}
+}
+
+func scheduleRepeatedly(initialDelay time.Duration, interval time.Duration, fn func(), cancelable akka.Cancelable) {
+	done := make(chan struct{})
+
+	if c, ok := cancelable.(*Cancelable); ok {
+		c.register(func() { close(done) })
+	}
+
+	go func() {
+		timer := time.NewTimer(initialDelay)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-timer.C:
+			}
+
+			if cancelable != nil && cancelable.IsCancellationRequested() {
+				return
+			}
+
+			fn()
+			timer.Reset(interval)
+		}
+	}()
+}
@@ -0,0 +1,152 @@
+package actor
+
+import (
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/pkg/class_loader"
+	"github.com/go-akka/configuration"
+)
+
+func TestScheduleOnceWithJitterFiresWithinWindow(t *testing.T) {
+	delay := 100 * time.Millisecond
+	jitter := 50 * time.Millisecond
+
+	scheduler := NewDefaultScheduler()
+	scheduler.SetRandom(rand.New(rand.NewSource(42)))
+
+	expected := rand.New(rand.NewSource(42))
+	want := delay + time.Duration(expected.Int63n(2*int64(jitter)+1)) - jitter
+
+	fired := make(chan time.Time, 1)
+	start := time.Now()
+
+	scheduler.Advanced().ScheduleOnceWithJitter(delay, jitter, akka.ActionFunc(func() { fired <- time.Now() }), nil)
+
+	select {
+	case at := <-fired:
+		{
+			elapsed := at.Sub(start)
+			if elapsed < want || elapsed < delay-jitter {
+				t.Fatalf("fired after %s, expected not before %s", elapsed, want)
+			}
+
+			if elapsed > delay+jitter+100*time.Millisecond {
+				t.Fatalf("fired after %s, expected within %s ± %s", elapsed, delay, jitter)
+			}
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("jittered action was not fired")
+	}
+}
+
+func TestScheduleOnceWithJitterCancelBeforeFiring(t *testing.T) {
+	scheduler := NewDefaultScheduler()
+	scheduler.SetRandom(rand.New(rand.NewSource(7)))
+
+	fired := make(chan struct{}, 1)
+	cancelable := NewCancelable()
+
+	scheduler.Advanced().ScheduleOnceWithJitter(50*time.Millisecond, 20*time.Millisecond, akka.ActionFunc(func() { fired <- struct{}{} }), cancelable)
+	cancelable.Cancel(false)
+
+	select {
+	case <-fired:
+		t.Fatalf("cancelled action should not be fired")
+	case <-time.After(150 * time.Millisecond):
+	}
+}
+
+func TestJitteredDelayIsNeverNegative(t *testing.T) {
+	scheduler := NewDefaultScheduler()
+	scheduler.SetRandom(rand.New(rand.NewSource(1)))
+
+	for i := 0; i < 1000; i++ {
+		if d := scheduler.jitteredDelay(time.Millisecond, time.Second); d < 0 {
+			t.Fatalf("jittered delay should not be negative, but got %s", d)
+		}
+	}
+}
+
+type CountingScheduler struct {
+	*DefaultScheduler
+
+	scheduled int32
+}
+
+func (p *CountingScheduler) Construct(config *configuration.Config) {
+	p.DefaultScheduler = NewDefaultScheduler()
+	p.DefaultScheduler.Construct(config)
+}
+
+func (p *CountingScheduler) ScheduleTellOnce(delay time.Duration, receiver akka.CanTell, message interface{}, sender akka.ActorRef, cancelable akka.Cancelable) {
+	atomic.AddInt32(&p.scheduled, 1)
+	p.DefaultScheduler.ScheduleTellOnce(delay, receiver, message, sender, cancelable)
+}
+
+func TestTimersUseTheDefaultSchedulerWithoutConfig(t *testing.T) {
+	system := newTestActorSystem(t, "DefaultScheduler")
+
+	if _, ok := system.Scheduler().(*DefaultScheduler); !ok {
+		t.Fatalf("system without a configured scheduler should use the DefaultScheduler, but got %T", system.Scheduler())
+	}
+
+	ref, _, received := newSchedulingActor(t, system, "timer")
+	ref.Tell(10 * time.Millisecond)
+
+	select {
+	case msg := <-received:
+		if msg != "tick" {
+			t.Fatalf("expected the scheduled tick, but got %v", msg)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timer of the default scheduler did not fire")
+	}
+}
+
+func TestDefaultSchedulerIsNotCreatedByTheSystemStart(t *testing.T) {
+	system := newTestActorSystem(t, "LazyScheduler")
+
+	if system.scheduler != nil {
+		t.Fatalf("the default scheduler should only be created on first use, but the start created %T", system.scheduler)
+	}
+
+	if system.Scheduler() == nil || system.scheduler == nil {
+		t.Fatalf("the default scheduler should be created on first use")
+	}
+}
+
+func TestSchedulerImplementationIsReadFromConfig(t *testing.T) {
+	class_loader.Default.Register((*CountingScheduler)(nil), "akka.test.counting-scheduler")
+
+	config := strings.Replace(testConfig, "akka {", `akka {
+	scheduler.implementation = "akka.test.counting-scheduler"
+`, 1)
+
+	system, err := NewActorSystem("ConfiguredScheduler", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	scheduler, ok := system.Scheduler().(*CountingScheduler)
+	if !ok {
+		t.Fatalf("system should use the configured scheduler, but got %T", system.Scheduler())
+	}
+
+	ref, _, received := newSchedulingActor(t, system, "timer")
+	ref.Tell(10 * time.Millisecond)
+
+	select {
+	case <-received:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timer of the configured scheduler did not fire")
+	}
+
+	if atomic.LoadInt32(&scheduler.scheduled) == 0 {
+		t.Fatalf("timer should be scheduled by the configured scheduler")
+	}
+}
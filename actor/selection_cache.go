@@ -0,0 +1,104 @@
+package actor
+
+import (
+	"sync"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/dispatch/sysmsg"
+)
+
+var (
+	_ akka.ResolvedRefCache = (*selectionCache)(nil)
+)
+
+// selectionCache is the akka.ResolvedRefCache of a system, it watches every
+// actor it caches and forgets the paths resolved to it once it terminates
+type selectionCache struct {
+	*akka.MinimalActorRef
+
+	refs map[string]akka.InternalActorRef
+	// keys holds the paths cached for each watched actor
+	keys   map[akka.InternalActorRef][]string
+	locker sync.RWMutex
+}
+
+func newSelectionCache(provider akka.ActorRefProvider) *selectionCache {
+	return &selectionCache{
+		MinimalActorRef: akka.NewMinimalActorRef(provider.TempPath(), provider),
+		refs:            make(map[string]akka.InternalActorRef),
+		keys:            make(map[akka.InternalActorRef][]string),
+	}
+}
+
+func (p *selectionCache) Get(path string) (ref akka.InternalActorRef, ok bool) {
+	p.locker.RLock()
+	defer p.locker.RUnlock()
+
+	ref, ok = p.refs[path]
+	return
+}
+
+func (p *selectionCache) Put(path string, ref akka.InternalActorRef) {
+	p.locker.Lock()
+
+	if previous, exist := p.refs[path]; exist {
+		p.forgetKey(previous, path)
+	}
+
+	keys, watched := p.keys[ref]
+	p.refs[path] = ref
+	p.keys[ref] = append(keys, path)
+
+	p.locker.Unlock()
+
+	if !watched {
+		ref.SendSystemMessage(&sysmsg.Watch{Watchee: ref, Watcher: p})
+	}
+}
+
+// Len is the number of paths cached
+func (p *selectionCache) Len() int {
+	p.locker.RLock()
+	defer p.locker.RUnlock()
+
+	return len(p.refs)
+}
+
+func (p *selectionCache) SendSystemMessage(message akka.SystemMessage) (err error) {
+	notification, ok := message.(*sysmsg.DeathWatchNotification)
+	if !ok {
+		return
+	}
+
+	terminated, ok := notification.Actor.(akka.InternalActorRef)
+	if !ok {
+		return
+	}
+
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	for _, path := range p.keys[terminated] {
+		delete(p.refs, path)
+	}
+	delete(p.keys, terminated)
+
+	return
+}
+
+func (p *selectionCache) forgetKey(ref akka.InternalActorRef, path string) {
+	keys := p.keys[ref]
+	for i, key := range keys {
+		if key == path {
+			keys = append(keys[:i], keys[i+1:]...)
+			break
+		}
+	}
+
+	if len(keys) > 0 {
+		p.keys[ref] = keys
+	} else {
+		// still watched, the notification finds no paths to forget
+		delete(p.keys, ref)
+	}
+}
@@ -0,0 +1,116 @@
+package actor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/configuration"
+)
+
+// validateSettings checks the settings a system can not run with before it
+// starts, every problem found is listed in one *ConfigurationError
+func (p *ActorSystemImpl) validateSettings() (err error) {
+	var problems []string
+	problem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	settings := p.settings
+
+	for _, level := range []struct{ path, value string }{
+		{"akka.loglevel", settings.LogLevel},
+		{"akka.stdout-loglevel", settings.StdoutLogLevel},
+	} {
+		if !isLogLevel(level.value) {
+			problem("%s = %q is not one of DEBUG, INFO, WARN, ERROR", level.path, level.value)
+		}
+	}
+
+	for _, logger := range sortedKeys(settings.LoggerLevels) {
+		if level := settings.LoggerLevels[logger]; !isLogLevel(level) {
+			problem("akka.logger-levels of %s = %q is not one of DEBUG, INFO, WARN, ERROR", logger, level)
+		}
+	}
+
+	for _, logger := range settings.Loggers {
+		if _, exist := p.classLoader.ClassNameOf(logger); !exist {
+			problem("akka.loggers: %s is not registered in the class loader", logger)
+		}
+	}
+
+	for _, class := range []struct{ path, name string }{
+		{"akka.actor.provider", settings.ProviderClass},
+		{"akka.actor.guardian-supervisor-strategy", settings.SupervisorStrategyClass},
+		{"akka.scheduler.implementation", settings.SchedulerClass},
+	} {
+		if len(class.name) == 0 {
+			continue
+		}
+
+		if _, exist := p.classLoader.ClassNameOf(class.name); !exist {
+			problem("%s: %s is not registered in the class loader", class.path, class.name)
+		}
+	}
+
+	for _, id := range dispatcherIds(settings.Config()) {
+		dispatcherConfig := settings.Config().GetConfig(id)
+
+		if dispatcherConfig.HasPath("throughput") && dispatcherConfig.GetInt64("throughput") <= 0 {
+			problem("%s.throughput = %d should be positive", id, dispatcherConfig.GetInt64("throughput"))
+		}
+
+		if typ := dispatcherConfig.GetString("type"); typ != "dispatcher" {
+			if _, exist := p.classLoader.ClassNameOf(typ); !exist {
+				problem("%s.type: %s is neither \"dispatcher\" nor registered in the class loader", id, typ)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		err = &ConfigurationError{Problems: problems}
+	}
+
+	return
+}
+
+func isLogLevel(level string) bool {
+	_, ok := akka.ParseLogLevel(level)
+	return ok
+}
+
+// dispatcherIds are the ids of the dispatchers configured at the top level
+// or in akka.actor, the objects with a type
+func dispatcherIds(config *configuration.Config) (ids []string) {
+	for _, prefix := range []string{"", "akka.actor."} {
+		scope := config
+		if prefix != "" {
+			scope = config.GetConfig(prefix[:len(prefix)-1])
+		}
+
+		if scope == nil || scope.IsEmpty() {
+			continue
+		}
+
+		for key, value := range scope.Root().GetObject().Items() {
+			if !value.IsObject() {
+				continue
+			}
+
+			if _, hasType := value.GetObject().Items()["type"]; hasType {
+				ids = append(ids, prefix+key)
+			}
+		}
+	}
+
+	sort.Strings(ids)
+	return
+}
+
+func sortedKeys(items map[string]string) (keys []string) {
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
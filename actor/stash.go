@@ -0,0 +1,144 @@
+package actor
+
+import (
+	"fmt"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/event"
+)
+
+// StashOverflowStrategy decides what Stash does once the stash is full
+type StashOverflowStrategy int
+
+const (
+	// StashOverflowFail rejects the message with ErrStashOverflow
+	StashOverflowFail StashOverflowStrategy = iota
+	// StashOverflowDiscardOldest drops the message stashed first to make room
+	StashOverflowDiscardOldest
+	// StashOverflowDiscardNewest drops the message that should be stashed
+	StashOverflowDiscardNewest
+)
+
+func (p StashOverflowStrategy) String() string {
+	switch p {
+	case StashOverflowFail:
+		{
+			return "fail"
+		}
+	case StashOverflowDiscardOldest:
+		{
+			return "discard-oldest"
+		}
+	case StashOverflowDiscardNewest:
+		{
+			return "discard-newest"
+		}
+	}
+	return fmt.Sprintf("StashOverflowStrategy(%d)", int(p))
+}
+
+type StashSettings struct {
+	// Capacity caps the stashed messages, zero leaves the stash unbounded
+	Capacity int
+	// OverflowStrategy is applied once Capacity is reached
+	OverflowStrategy StashOverflowStrategy
+}
+
+// Stash keeps messages the actor can not handle yet, to be handled again
+// after UnstashAll. It is meant to be embedded by an actor and created with
+// its context in PreStart, it is not safe to use outside of the actor
+type Stash struct {
+	cell     *ActorCell
+	settings StashSettings
+
+	stashed []akka.Envelope
+}
+
+func NewStash(context akka.ActorContext, settings StashSettings) *Stash {
+	cell, _ := context.(*ActorCell)
+
+	return &Stash{
+		cell:     cell,
+		settings: settings,
+	}
+}
+
+func (p *Stash) SetOverflowStrategy(strategy StashOverflowStrategy) {
+	p.settings.OverflowStrategy = strategy
+}
+
+func (p *Stash) OverflowStrategy() StashOverflowStrategy {
+	return p.settings.OverflowStrategy
+}
+
+// Stash keeps the message being received, with its sender and headers
+func (p *Stash) Stash() (err error) {
+	envelope, ok := p.cell.CurrentMessage().(akka.Envelope)
+	if !ok {
+		return ErrStashNothingToStash
+	}
+
+	if p.settings.Capacity <= 0 || len(p.stashed) < p.settings.Capacity {
+		p.stashed = append(p.stashed, envelope)
+		return
+	}
+
+	switch p.settings.OverflowStrategy {
+	case StashOverflowDiscardOldest:
+		{
+			p.discard(p.stashed[0])
+			p.stashed[0] = akka.Envelope{}
+			p.stashed = append(p.stashed[1:], envelope)
+		}
+	case StashOverflowDiscardNewest:
+		{
+			p.discard(envelope)
+		}
+	default:
+		err = fmt.Errorf("%s: %d messages are stashed by %s", ErrStashOverflow, len(p.stashed), p.cell.Self().Path())
+	}
+
+	return
+}
+
+// UnstashAll puts the stashed messages back in front of the mailbox of the
+// actor, in the order they were stashed, so they are handled before the
+// messages that arrived in the meantime
+func (p *Stash) UnstashAll() (err error) {
+	stashed := p.stashed
+	p.stashed = nil
+
+	if len(stashed) == 0 {
+		return
+	}
+
+	if p.cell.IsTerminated() {
+		for _, envelope := range stashed {
+			p.cell.system.deadLetter(envelope.Message, envelope.Sender, p.cell.Self())
+		}
+		return fmt.Errorf("%w: %s", ErrActorTerminated, p.cell.Self().Path())
+	}
+
+	mailbox := p.cell.Mailbox()
+	if err = mailbox.EnqueueFirst(p.cell.Self(), stashed...); err != nil {
+		return
+	}
+
+	p.cell.Dispatcher().RegisterForExecution(mailbox, true, false)
+
+	return
+}
+
+func (p *Stash) ClearStash() {
+	p.stashed = nil
+}
+
+func (p *Stash) NumberOfStashed() int {
+	return len(p.stashed)
+}
+
+func (p *Stash) discard(envelope akka.Envelope) {
+	message := fmt.Sprintf("stash of capacity %d is full, %v is discarded by %s", p.settings.Capacity, envelope.Message, p.settings.OverflowStrategy)
+	p.cell.system.EventStream().Publish(event.NewWarningEvent(p.cell.Self().Path().String(), p, message))
+	p.cell.system.deadLetter(envelope.Message, envelope.Sender, p.cell.Self())
+}
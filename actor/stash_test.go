@@ -0,0 +1,228 @@
+package actor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/akka/event"
+)
+
+// releaseStash unstashes once gate is closed, a nil gate does not wait
+type releaseStash struct {
+	gate chan struct{}
+}
+
+// StashingActor stashes every string until it is released, the strings
+// received afterwards are reported
+type StashingActor struct {
+	*UntypedActor
+	*Stash
+
+	settings StashSettings
+	released bool
+	received chan string
+	results  chan error
+}
+
+func (p *StashingActor) StashingActor(settings StashSettings, received chan string, results chan error) {
+	p.settings = settings
+	p.received = received
+	p.results = results
+}
+
+func (p *StashingActor) PreStart() (err error) {
+	p.Stash = NewStash(p.Context(), p.settings)
+	return
+}
+
+func (p *StashingActor) Receive(message interface{}) (handled bool, err error) {
+	switch msg := message.(type) {
+	case *releaseStash:
+		{
+			if msg.gate != nil {
+				<-msg.gate
+			}
+			p.released = true
+			p.results <- p.UnstashAll()
+		}
+	case string:
+		{
+			if p.released {
+				p.received <- msg
+				return true, nil
+			}
+			p.results <- p.Stash.Stash()
+		}
+	}
+	return true, nil
+}
+
+func runStashAtCapacity(t *testing.T, strategy StashOverflowStrategy) (received []string, results []error, events []interface{}) {
+	system := newTestActorSystem(t, "Stash")
+	collector := newEventCollector(system, event.Warning{}, akka.DeadLetter{})
+
+	receivedCh := make(chan string, 10)
+	resultsCh := make(chan error, 10)
+
+	stashProps, err := props.Create((*StashingActor)(nil), StashSettings{Capacity: 2, OverflowStrategy: strategy}, receivedCh, resultsCh)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(stashProps, "stashing")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	for _, msg := range []string{"a", "b", "c"} {
+		ref.Tell(msg)
+	}
+	ref.Tell(&releaseStash{})
+
+	for i := 0; i < 4; i++ {
+		select {
+		case err := <-resultsCh:
+			results = append(results, err)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("stash result %d was not reported", i)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-receivedCh:
+			received = append(received, msg)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("unstashed message %d was not received", i)
+		}
+	}
+
+	select {
+	case msg := <-receivedCh:
+		t.Fatalf("only 2 messages should be unstashed, but also got %s", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	for len(collector.events) > 0 {
+		events = append(events, <-collector.events)
+	}
+
+	return
+}
+
+func assertStashDiscarded(t *testing.T, events []interface{}, discarded string) {
+	var warned, deadLettered bool
+	for _, e := range events {
+		switch v := e.(type) {
+		case *event.Warning:
+			{
+				warned = warned || strings.Contains(v.Message().(string), discarded+" is discarded")
+			}
+		case akka.DeadLetter:
+			{
+				deadLettered = deadLettered || v.Message() == discarded
+			}
+		}
+	}
+
+	if !warned || !deadLettered {
+		t.Fatalf("discarded %s should be warned about and dead-lettered, but got %v", discarded, events)
+	}
+}
+
+func TestUnstashAllRunsBeforeTheQueuedMessages(t *testing.T) {
+	system := newTestActorSystem(t, "Unstash")
+
+	received := make(chan string, 10)
+	results := make(chan error, 10)
+
+	stashProps, err := props.Create((*StashingActor)(nil), StashSettings{}, received, results)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(stashProps, "stashing")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	gate := make(chan struct{})
+
+	ref.Tell("a")
+	ref.Tell("b")
+	ref.Tell(&releaseStash{gate: gate})
+
+	// c and d are queued while the actor waits to unstash
+	ref.Tell("c")
+	ref.Tell("d")
+	close(gate)
+
+	var order []string
+	for len(order) < 4 {
+		select {
+		case msg := <-received:
+			order = append(order, msg)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("only %v were received", order)
+		}
+	}
+
+	if strings.Join(order, "") != "abcd" {
+		t.Fatalf("unstashed messages should come before the ones already queued, but got %v", order)
+	}
+}
+
+func TestStashOverflowFailRejectsTheNewestMessage(t *testing.T) {
+	received, results, events := runStashAtCapacity(t, StashOverflowFail)
+
+	if results[0] != nil || results[1] != nil || results[3] != nil {
+		t.Fatalf("stashing below capacity and unstashing should succeed, but got %v", results)
+	}
+
+	if results[2] == nil || !strings.HasPrefix(results[2].Error(), ErrStashOverflow.Error()) {
+		t.Fatalf("stashing at capacity should fail, but got %v", results[2])
+	}
+
+	if strings.Join(received, "") != "ab" {
+		t.Fatalf("the stashed messages should be unstashed in order, but got %v", received)
+	}
+
+	if len(events) != 0 {
+		t.Fatalf("failing strategy should not discard anything, but got %v", events)
+	}
+}
+
+func TestStashOverflowDiscardOldestDropsTheFirstStashed(t *testing.T) {
+	received, results, events := runStashAtCapacity(t, StashOverflowDiscardOldest)
+
+	for _, err := range results {
+		if err != nil {
+			t.Fatalf("discarding strategy should not fail, but got %s", err.Error())
+		}
+	}
+
+	if strings.Join(received, "") != "bc" {
+		t.Fatalf("the oldest message should be discarded, but got %v", received)
+	}
+
+	assertStashDiscarded(t, events, "a")
+}
+
+func TestStashOverflowDiscardNewestDropsTheMessageToStash(t *testing.T) {
+	received, results, events := runStashAtCapacity(t, StashOverflowDiscardNewest)
+
+	for _, err := range results {
+		if err != nil {
+			t.Fatalf("discarding strategy should not fail, but got %s", err.Error())
+		}
+	}
+
+	if strings.Join(received, "") != "ab" {
+		t.Fatalf("the newest message should be discarded, but got %v", received)
+	}
+
+	assertStashDiscarded(t, events, "c")
+}
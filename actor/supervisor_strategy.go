@@ -0,0 +1,139 @@
+package actor
+
+import (
+	"time"
+
+	"github.com/go-akka/akka"
+)
+
+var (
+	_ akka.SupervisorStrategy = (*OneForOneStrategy)(nil)
+	_ akka.SupervisorStrategy = (*guardianSupervisorStrategy)(nil)
+)
+
+var (
+	DefaultDecider akka.Decider = func(cause error) akka.Directive {
+		if _, ok := cause.(*ActorInitializationError); ok {
+			return akka.StopDirective
+		}
+		return akka.RestartDirective
+	}
+
+	StoppingDecider akka.Decider = func(cause error) akka.Directive {
+		return akka.StopDirective
+	}
+
+	DefaultSupervisorStrategy akka.SupervisorStrategy = NewOneForOneStrategy(-1, 0, DefaultDecider)
+	StoppingStrategy          akka.SupervisorStrategy = NewOneForOneStrategy(-1, 0, StoppingDecider)
+)
+
+// DefaultSupervisorStrategyConfigurator restarts the failing top-level actors
+type DefaultSupervisorStrategyConfigurator struct{}
+
+func (p *DefaultSupervisorStrategyConfigurator) Create() akka.SupervisorStrategy {
+	return DefaultSupervisorStrategy
+}
+
+// StoppingSupervisorStrategyConfigurator stops the failing top-level actors
+type StoppingSupervisorStrategyConfigurator struct{}
+
+func (p *StoppingSupervisorStrategyConfigurator) Create() akka.SupervisorStrategy {
+	return StoppingStrategy
+}
+
+// childStatsRecorder is implemented by the stats of the children container,
+// the supervision keeps them up to date for introspection
+type childStatsRecorder interface {
+	RecordFailure(cause error, failed *akka.Envelope)
+	RecordResume()
+	RecordRestart()
+	RecordStop()
+}
+
+type OneForOneStrategy struct {
+	maxNrOfRetries  int
+	withinTimeRange time.Duration
+	decider         akka.Decider
+}
+
+func NewOneForOneStrategy(maxNrOfRetries int, withinTimeRange time.Duration, decider akka.Decider) *OneForOneStrategy {
+	if decider == nil {
+		decider = DefaultDecider
+	}
+
+	return &OneForOneStrategy{
+		maxNrOfRetries:  maxNrOfRetries,
+		withinTimeRange: withinTimeRange,
+		decider:         decider,
+	}
+}
+
+func (p *OneForOneStrategy) Decide(cause error) akka.Directive {
+	return p.decider(cause)
+}
+
+func (p *OneForOneStrategy) HandleFailure(context akka.ActorContext, child akka.ActorRef, cause error, stats akka.ChildRestartStats, children []akka.ChildRestartStats) (handled bool) {
+	switch p.Decide(cause) {
+	case akka.ResumeDirective:
+		{
+			if recorder, ok := stats.(childStatsRecorder); ok {
+				recorder.RecordResume()
+			}
+			stats.Child().Resume(cause)
+		}
+	case akka.RestartDirective:
+		{
+			if stats.RequestRestartPermission(p.maxNrOfRetries, int(p.withinTimeRange/time.Millisecond)) {
+				failed := stats.FailedEnvelope()
+				if recorder, ok := stats.(childStatsRecorder); ok {
+					recorder.RecordRestart()
+				}
+				stats.Child().Restart(cause, failed)
+			} else {
+				context.StopChild(child)
+			}
+		}
+	case akka.StopDirective:
+		{
+			context.StopChild(child)
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
+type guardianSupervisorStrategy struct {
+	akka.SupervisorStrategy
+
+	system *ActorSystemImpl
+}
+
+func newGuardianSupervisorStrategy(system *ActorSystemImpl, strategy akka.SupervisorStrategy) *guardianSupervisorStrategy {
+	return &guardianSupervisorStrategy{
+		SupervisorStrategy: strategy,
+		system:             system,
+	}
+}
+
+func (p *guardianSupervisorStrategy) HandleFailure(context akka.ActorContext, child akka.ActorRef, cause error, stats akka.ChildRestartStats, children []akka.ChildRestartStats) (handled bool) {
+	directive := p.SupervisorStrategy.Decide(cause)
+
+	handled = p.SupervisorStrategy.HandleFailure(context, child, cause, stats, children)
+
+	// a top-level actor the strategy resumes or restarts is still alive, only
+	// the failures that stop it or escalate past the guardian are uncaught
+	if !handled || directive == akka.StopDirective || directive == akka.EscalateDirective {
+		p.system.uncaughtFailure(cause, child.Path())
+	}
+
+	return
+}
+
+// HandleEscalatedFailure stops child, whose failure was escalated by every
+// supervisor up to the guardian, together with its subtree
+func (p *guardianSupervisorStrategy) HandleEscalatedFailure(context akka.ActorContext, child akka.ActorRef, cause error) {
+	p.system.uncaughtFailure(cause, child.Path())
+	context.StopChild(child)
+}
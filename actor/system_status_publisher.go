@@ -0,0 +1,68 @@
+package actor
+
+import (
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+)
+
+type systemStatusTick struct{}
+
+// SystemStatusPublisher is the /system/systemStatus actor, it publishes the
+// SystemStatus every interval until it is stopped on termination
+type SystemStatusPublisher struct {
+	*UntypedActor
+
+	interval time.Duration
+}
+
+func (p *SystemStatusPublisher) SystemStatusPublisher(interval time.Duration) {
+	p.interval = interval
+}
+
+func (p *SystemStatusPublisher) PreStart() (err error) {
+	p.Context().Schedule(p.interval, &systemStatusTick{})
+	return
+}
+
+func (p *SystemStatusPublisher) Receive(message interface{}) (handled bool, err error) {
+	if _, ok := message.(*systemStatusTick); !ok {
+		return false, nil
+	}
+
+	system := p.Context().System().(*ActorSystemImpl)
+	system.EventStream().Publish(system.SystemStatus())
+
+	p.Context().Schedule(p.interval, &systemStatusTick{})
+
+	return true, nil
+}
+
+func (p *ActorSystemImpl) startSystemStatusPublisher(interval time.Duration) (err error) {
+	publisherProps, err := props.Create((*SystemStatusPublisher)(nil), interval)
+	if err != nil {
+		return
+	}
+
+	publisher, err := p.SystemActorOf(publisherProps, "systemStatus")
+	if err != nil {
+		return
+	}
+
+	p.RegisterOnTermination(func() {
+		publisher.(akka.InternalActorRef).Stop()
+	})
+
+	return
+}
+
+func countActors(ref *LocalActorRef) (count int) {
+	count = 1
+	for _, child := range ref.Cell().Children() {
+		if childRef, ok := child.(*LocalActorRef); ok {
+			count += countActors(childRef)
+		}
+	}
+	return
+}
@@ -0,0 +1,59 @@
+package actor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/configuration"
+)
+
+func TestSystemStatusIsPublishedEveryInterval(t *testing.T) {
+	config := strings.Replace(testConfig, "actor {", "system-status-interval = 20ms\n\tactor {", 1)
+
+	system, err := NewActorSystem("SystemStatus", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	collector := newEventCollector(system, akka.SystemStatus{})
+
+	echoProps, err := props.Create((*echoActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	if _, err = system.ActorOf(echoProps, "echo"); err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	var status akka.SystemStatus
+	for status.Actors < 5 {
+		select {
+		case e := <-collector.events:
+			status = e.(akka.SystemStatus)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("system status was not published")
+		}
+	}
+
+	if status.StartTime != system.StartTime() || status.Uptime <= 0 || len(status.Metrics.Dispatchers) == 0 {
+		t.Fatalf("system status should carry the start time, uptime and dispatcher metrics, but got %#v", status)
+	}
+
+	system.Terminate()
+
+	// one status might have been published while terminating
+	time.Sleep(50 * time.Millisecond)
+	for len(collector.events) > 0 {
+		<-collector.events
+	}
+
+	select {
+	case e := <-collector.events:
+		t.Fatalf("no status should be published after termination, but got %#v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,107 @@
+package actor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/dispatch"
+	"github.com/go-akka/akka/dispatch/sysmsg"
+)
+
+// terminationCallbacks holds the callbacks registered with
+// RegisterOnTermination, they are run once, in reverse order of registration
+type terminationCallbacks struct {
+	callbacks []func()
+	done      bool
+	finished  chan struct{}
+
+	locker sync.Mutex
+}
+
+// add returns false if the callbacks already ran, fn is not kept then
+func (p *terminationCallbacks) add(fn func()) bool {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	if p.done {
+		return false
+	}
+
+	p.callbacks = append(p.callbacks, fn)
+	return true
+}
+
+func (p *terminationCallbacks) run() {
+	p.locker.Lock()
+	if p.done {
+		p.locker.Unlock()
+		return
+	}
+	callbacks := p.callbacks
+	p.callbacks = nil
+	p.done = true
+	finished := p.finishedLocked()
+	p.locker.Unlock()
+
+	for i := len(callbacks) - 1; i >= 0; i-- {
+		callbacks[i]()
+	}
+
+	close(finished)
+}
+
+// whenFinished is closed once all callbacks returned
+func (p *terminationCallbacks) whenFinished() <-chan struct{} {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return p.finishedLocked()
+}
+
+func (p *terminationCallbacks) finishedLocked() chan struct{} {
+	if p.finished == nil {
+		p.finished = make(chan struct{})
+	}
+	return p.finished
+}
+
+// terminationWatcher is closed once the actor it watches terminated
+type terminationWatcher struct {
+	*akka.MinimalActorRef
+
+	terminated chan struct{}
+	once       sync.Once
+}
+
+func (p *terminationWatcher) SendSystemMessage(message akka.SystemMessage) (err error) {
+	if _, ok := message.(*sysmsg.DeathWatchNotification); ok {
+		p.once.Do(func() { close(p.terminated) })
+	}
+	return
+}
+
+// stopAndAwait stops guardian and waits for its termination, for at most the
+// shutdown-timeout of the default dispatcher
+func (p *ActorSystemImpl) stopAndAwait(guardian akka.LocalActorRef) {
+	watcher := &terminationWatcher{
+		MinimalActorRef: akka.NewMinimalActorRef(p.provider.TempPath(), p.provider),
+		terminated:      make(chan struct{}),
+	}
+
+	guardian.SendSystemMessage(&sysmsg.Watch{Watchee: guardian, Watcher: watcher})
+	guardian.Stop()
+
+	timeout := dispatch.DefaultShutdownTimeout
+	if dispatcher, ok := p.dispatchers.Lookup(dispatch.DefaultDispatcherId).(interface {
+		ShutdownTimeout() time.Duration
+	}); ok {
+		timeout = dispatcher.ShutdownTimeout()
+	}
+
+	select {
+	case <-watcher.terminated:
+	case <-time.After(timeout):
+		p.log.Warning("%s did not stop within the shutdown-timeout of %s, the system terminates without waiting for it", guardian.Path(), timeout)
+	}
+}
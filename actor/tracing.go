@@ -0,0 +1,103 @@
+package actor
+
+import (
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/go-akka/akka"
+)
+
+const (
+	// TraceIdHeader, SpanIdHeader and ParentSpanIdHeader are set on every
+	// message told with akka.actor.tracing on, a message told with them
+	// already, like a forwarded one or one told by Context().Tell, continues
+	// their trace as a child span
+	TraceIdHeader      = "trace-id"
+	SpanIdHeader       = "span-id"
+	ParentSpanIdHeader = "parent-span-id"
+)
+
+// SpanStarted is published when a message is told with akka.actor.tracing
+// on, the span covers the delivery and the handling of the message. The
+// ParentSpanId of the first span of a trace is empty
+type SpanStarted struct {
+	TraceId      string
+	SpanId       string
+	ParentSpanId string
+
+	Sender    akka.ActorRef
+	Recipient akka.ActorRef
+	Message   interface{}
+	At        time.Time
+}
+
+// SpanFinished is published once the recipient of the span handled its
+// message
+type SpanFinished struct {
+	TraceId   string
+	SpanId    string
+	Recipient akka.ActorRef
+	At        time.Time
+}
+
+type spanContext struct {
+	traceId string
+	spanId  string
+}
+
+func newSpanId() string {
+	return strconv.FormatUint(rand.Uint64(), 16)
+}
+
+// startSpan sets the span headers of envelope told to recipient. The parent
+// is the span of the headers already on it, else the span starts a new trace
+func (p *ActorSystemImpl) startSpan(envelope *akka.Envelope, recipient akka.ActorRef) {
+	switch envelope.Message.(type) {
+	case *SpanStarted, *SpanFinished:
+		{
+			// the exporters are not traced
+			return
+		}
+	}
+
+	parent := spanContext{traceId: envelope.Headers.Get(TraceIdHeader), spanId: envelope.Headers.Get(SpanIdHeader)}
+
+	span := spanContext{traceId: parent.traceId, spanId: newSpanId()}
+	if span.traceId == "" {
+		span.traceId = newSpanId()
+	}
+
+	headers := make(akka.Headers, len(envelope.Headers)+3)
+	for k, v := range envelope.Headers {
+		headers[k] = v
+	}
+	headers[TraceIdHeader] = span.traceId
+	headers[SpanIdHeader] = span.spanId
+	headers[ParentSpanIdHeader] = parent.spanId
+	envelope.Headers = headers
+
+	p.eventStream.Publish(&SpanStarted{
+		TraceId:      span.traceId,
+		SpanId:       span.spanId,
+		ParentSpanId: parent.spanId,
+		Sender:       envelope.Sender,
+		Recipient:    recipient,
+		Message:      envelope.Message,
+		At:           time.Now(),
+	})
+}
+
+// beginSpan returns the span of envelope for finishSpan once it is handled,
+// Context().Tell carries it to the messages told meanwhile
+func (p *ActorCell) beginSpan(envelope akka.Envelope) (span spanContext) {
+	return spanContext{traceId: envelope.Headers.Get(TraceIdHeader), spanId: envelope.Headers.Get(SpanIdHeader)}
+}
+
+func (p *ActorCell) finishSpan(span spanContext) {
+	if span.spanId == "" {
+		return
+	}
+
+	p.system.EventStream().Publish(&SpanFinished{TraceId: span.traceId, SpanId: span.spanId, Recipient: p.self, At: time.Now()})
+}
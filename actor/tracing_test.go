@@ -0,0 +1,166 @@
+package actor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/configuration"
+)
+
+func TestTwoHopTellProducesLinkedSpans(t *testing.T) {
+	config := strings.Replace(testConfig, `provider = "LocalActorRefProvider"`, `provider = "LocalActorRefProvider"
+		tracing = on`, 1)
+
+	system, err := NewActorSystem("Tracing", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	started := newEventCollector(system, SpanStarted{})
+	finished := newEventCollector(system, SpanFinished{})
+
+	received := make(chan interface{}, 10)
+	recordingProps, err := props.Create((*RecordingActor)(nil), received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	last, err := system.ActorOf(recordingProps, "last")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	relayProps, err := props.Create((*RelayingActor)(nil), last)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	first, err := system.ActorOf(relayProps, "first")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	first.Tell("traced")
+	expectReceived(t, received, "traced")
+
+	var spans []*SpanStarted
+	for len(spans) < 2 {
+		select {
+		case e := <-started.events:
+			spans = append(spans, e.(*SpanStarted))
+		case <-time.After(3 * time.Second):
+			t.Fatalf("two spans should be started, but got %d", len(spans))
+		}
+	}
+
+	root, child := spans[0], spans[1]
+	if root.Recipient != first || root.ParentSpanId != "" || root.TraceId == "" {
+		t.Fatalf("the first hop should start a trace, but got %+v", root)
+	}
+
+	if child.Recipient != last || child.TraceId != root.TraceId || child.ParentSpanId != root.SpanId || child.SpanId == root.SpanId {
+		t.Fatalf("the second hop should be a child span of the first, but got %+v after %+v", child, root)
+	}
+
+	finishedSpans := map[string]bool{}
+	for len(finishedSpans) < 2 {
+		select {
+		case e := <-finished.events:
+			span := e.(*SpanFinished)
+			if span.TraceId != root.TraceId {
+				t.Fatalf("finished span should belong to the trace %s, but got %+v", root.TraceId, span)
+			}
+			finishedSpans[span.SpanId] = true
+		case <-time.After(3 * time.Second):
+			t.Fatalf("both spans should finish, but got %v", finishedSpans)
+		}
+	}
+
+	if !finishedSpans[root.SpanId] || !finishedSpans[child.SpanId] {
+		t.Fatalf("the started spans should finish, but got %v", finishedSpans)
+	}
+}
+
+func TestTellNotThroughTheContextStartsANewTrace(t *testing.T) {
+	config := strings.Replace(testConfig, `provider = "LocalActorRefProvider"`, `provider = "LocalActorRefProvider"
+		tracing = on`, 1)
+
+	system, err := NewActorSystem("TracingOutside", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+
+	started := newEventCollector(system, SpanStarted{})
+
+	blocked, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+
+	stuckProps, err := props.Create((*StuckActor)(nil), blocked, release)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	stuck, err := system.ActorOf(stuckProps, "stuck")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	received := make(chan interface{}, 10)
+	recordingProps, err := props.Create((*RecordingActor)(nil), received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	other, err := system.ActorOf(recordingProps, "other")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	stuck.Tell("block")
+	<-blocked
+
+	// told on behalf of stuck while it handles block, but not by its context
+	other.Tell("outside", stuck)
+	expectReceived(t, received, "outside")
+
+	var spans []*SpanStarted
+	for len(spans) < 2 {
+		select {
+		case e := <-started.events:
+			spans = append(spans, e.(*SpanStarted))
+		case <-time.After(3 * time.Second):
+			t.Fatalf("two spans should be started, but got %d", len(spans))
+		}
+	}
+
+	if block, outside := spans[0], spans[1]; outside.ParentSpanId != "" || outside.TraceId == block.TraceId {
+		t.Fatalf("a tell not through the context should start a new trace, but got %+v after %+v", outside, block)
+	}
+}
+
+func TestNoSpansWithoutTracing(t *testing.T) {
+	system := newTestActorSystem(t, "NoTracing")
+	started := newEventCollector(system, SpanStarted{})
+
+	received := make(chan interface{}, 10)
+	recordingProps, err := props.Create((*RecordingActor)(nil), received)
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	ref, err := system.ActorOf(recordingProps, "untraced")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	ref.Tell("untraced")
+	expectReceived(t, received, "untraced")
+
+	select {
+	case e := <-started.events:
+		t.Fatalf("no span should be started without tracing, but got %+v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
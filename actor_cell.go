@@ -1,5 +1,9 @@
 package akka
 
+import (
+	"time"
+)
+
 type Cell interface {
 	Self() ActorRef
 	System() ActorSystem
@@ -7,8 +11,9 @@ type Cell interface {
 
 	Start()
 	Suspend()
+	SuspendWithReason(reason string)
 	Resume(causedByFailure error)
-	Restart(err error)
+	Restart(err error, failed *Envelope)
 	Stop() (err error)
 
 	Parent() ActorRef
@@ -24,6 +29,24 @@ type Cell interface {
 	ChildrenRefs() ChildrenContainer
 	GetSingleChild(name string) ActorRef
 	GetChildByName(name string) (stats ChildStats, exist bool)
+
+	// SuspensionReason says why the actor is suspended, it is empty while the
+	// actor is not
+	SuspensionReason() string
+}
+
+// PanicHandler is implemented by actor cells that turn a panic recovered
+// while their mailbox runs into a supervision failure, a mailbox whose cell
+// has no handler drops the panic so the dispatcher keeps running
+type PanicHandler interface {
+	HandlePanic(recovered interface{}, stack []byte)
+}
+
+// BlockingCallHandler is implemented by actor cells that report the user
+// messages whose handling took longer than the
+// blocking-call-warning-threshold of their dispatcher
+type BlockingCallHandler interface {
+	HandleBlockingCall(envelope Envelope, elapsed time.Duration)
 }
 
 type ActorCell interface {
@@ -30,6 +30,17 @@ type ActorContext interface {
 	Self() ActorRef
 	Sender() ActorRef
 
+	// Headers are the headers of the message being received
+	Headers() Headers
+	// Forward tells message to target keeping the sender and the headers of
+	// the message being received
+	Forward(target ActorRef, message interface{}) error
+	// Tell tells message to target from Self, carrying the headers of the
+	// message being received listed in akka.actor.propagated-headers
+	Tell(target ActorRef, message interface{}) error
+
+	Schedule(delay time.Duration, message interface{}) Cancelable
+
 	System() ActorSystem
 
 	StopChild(actor ActorRef)
@@ -1,5 +1,9 @@
 package akka
 
+import (
+	"strings"
+)
+
 type ActorPath interface {
 	Uid() int
 	Address() (addr Address)
@@ -17,3 +21,21 @@ type ActorPath interface {
 	Append(name string) ActorPath
 	String() string
 }
+
+// compareActorPaths orders paths by address and elements, the uid is not part
+// of the path identity
+func compareActorPaths(path, other ActorPath) int {
+	if other == nil {
+		return 1
+	}
+	return strings.Compare(path.ToStringWithAddress(path.Address()), other.ToStringWithAddress(other.Address()))
+}
+
+// renderedAddress is the address a path is rendered with, its own one if that
+// has a host and port already, otherwise the given one
+func renderedAddress(own, address Address) Address {
+	if len(own.host) > 0 && own.port > 0 {
+		return own
+	}
+	return address
+}
@@ -0,0 +1,41 @@
+package akka
+
+import (
+	"testing"
+)
+
+func TestActorPathRendersWithAddress(t *testing.T) {
+	local := NewRootActorPath(NewAddress("akka", "PathTest", "", 0), "")
+	worker := local.Append("user").Append("worker#42")
+
+	remote := NewAddress("akka", "PathTest", "10.0.0.1", 2552)
+
+	expected := []struct{ got, want string }{
+		{local.String(), "akka://PathTest/"},
+		{local.ToStringWithAddress(remote), "akka://PathTest@10.0.0.1:2552/"},
+		{local.ToStringWithoutAddress(), "/"},
+		{worker.String(), "akka://PathTest/user/worker#42"},
+		{worker.ToStringWithoutAddress(), "/user/worker"},
+		{worker.ToStringWithAddress(remote), "akka://PathTest@10.0.0.1:2552/user/worker"},
+		{worker.ToSerializationFormat(), "akka://PathTest/user/worker#42"},
+		{worker.ToSerializationFormatWithAddress(remote), "akka://PathTest@10.0.0.1:2552/user/worker#42"},
+	}
+
+	for _, e := range expected {
+		if e.got != e.want {
+			t.Fatalf("path should render as %s, but got %s", e.want, e.got)
+		}
+	}
+
+	// a path with a host and port of its own keeps it
+	other := NewAddress("akka", "PathTest", "10.0.0.2", 2553)
+	addressed := NewRootActorPath(remote, "").Append("user").Append("worker")
+
+	if got := addressed.ToStringWithAddress(other); got != "akka://PathTest@10.0.0.1:2552/user/worker" {
+		t.Fatalf("addressed path should keep its own address, but got %s", got)
+	}
+
+	if got := addressed.Root().ToStringWithAddress(other); got != "akka://PathTest@10.0.0.1:2552/" {
+		t.Fatalf("addressed root should keep its own address, but got %s", got)
+	}
+}
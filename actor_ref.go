@@ -8,6 +8,7 @@ type ActorRef interface {
 	CanTell
 	Path() ActorPath
 	CompareTo(other ActorRef) int
+	Equals(other ActorRef) bool
 	String() string
 }
 
@@ -21,24 +22,36 @@ type ActorRefWithCell interface {
 
 type LocalActorRef interface {
 	ActorRefWithCell
+	CanTellWithResult
 }
 
+// CanTellWithResult is told like CanTell, but the error also says whether the
+// message went to the dead letters because its target is terminated
+type CanTellWithResult interface {
+	TellWithResult(message interface{}, sender ActorRef) error
+}
+
+// NoSender is the sender of messages told without one, its methods are safe
+// to call on a nil *NoSender
 type NoSender struct {
 }
 
-func (NoSender) Path() (path ActorPath) {
+func (*NoSender) Path() (path ActorPath) {
 	return
 }
-func (NoSender) Tell(message interface{}, sender ...ActorRef) error {
+func (*NoSender) Tell(message interface{}, sender ...ActorRef) error {
 	return nil
 }
-func (NoSender) Forward(message interface{}) {
+func (*NoSender) Forward(message interface{}) {
 	return
 }
-func (NoSender) CompareTo(other ActorRef) int {
+func (*NoSender) CompareTo(other ActorRef) int {
 	return 0
 }
-func (NoSender) String() string {
+func (*NoSender) Equals(other ActorRef) bool {
+	return CompareActorRefs((*NoSender)(nil), other) == 0
+}
+func (*NoSender) String() string {
 	return ""
 }
 
@@ -48,3 +61,40 @@ type LocalRef struct {
 func (p *LocalRef) IsLocal() bool {
 	return true
 }
+
+// CompareActorRefs orders refs by path and then by uid, refs without a path,
+// like NoSender, sort first
+func CompareActorRefs(ref, other ActorRef) int {
+	path, otherPath := actorRefPath(ref), actorRefPath(other)
+
+	if path == nil || otherPath == nil {
+		switch {
+		case path == nil && otherPath == nil:
+			return 0
+		case path == nil:
+			return -1
+		}
+		return 1
+	}
+
+	if x := path.CompareTo(otherPath); x != 0 {
+		return x
+	}
+
+	if path.Uid() < otherPath.Uid() {
+		return -1
+	} else if path.Uid() == otherPath.Uid() {
+		return 0
+	}
+	return 1
+}
+
+func actorRefPath(ref ActorRef) ActorPath {
+	switch ref.(type) {
+	case nil, *NoSender:
+		{
+			return nil
+		}
+	}
+	return ref.Path()
+}
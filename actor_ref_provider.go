@@ -19,7 +19,7 @@ type ActorRefProvider interface {
 	Init(system ActorSystem) error
 
 	RegisterTempActor(actorRef InternalActorRef, path ActorPath)
-	ResolveActorRef(path ActorPath) ActorRef
+	ResolveActorRef(path ActorPath) (ref ActorRef, ok bool)
 
 	RootGuardian() InternalActorRef
 	RootGuardianAt(address Address) ActorRef
@@ -40,5 +40,7 @@ type LocalActorRefProvider interface {
 
 type ActorRefFactory interface {
 	ActorOf(props Props, name string) (ref ActorRef, err error)
-	ActorSelection(path ActorPath) (selection ActorSelection, err error)
+	// ActorSelection selects an absolute path like "/user/a" or
+	// "akka://system/user/a", or a path relative to the factory like "../b"
+	ActorSelection(path string) (selection ActorSelection, err error)
 }
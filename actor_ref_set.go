@@ -0,0 +1,155 @@
+package akka
+
+// ActorRefKey is the comparable identity of an ActorRef, two refs with the
+// same path and uid have the same key, so it can be used as a map key
+type ActorRefKey struct {
+	path string
+	uid  int
+}
+
+func KeyOfActorRef(ref ActorRef) (key ActorRefKey) {
+	path := actorRefPath(ref)
+	if path == nil {
+		return
+	}
+
+	key.path = path.ToStringWithAddress(path.Address())
+	key.uid = path.Uid()
+
+	return
+}
+
+func (p ActorRefKey) Path() string {
+	return p.path
+}
+
+func (p ActorRefKey) Uid() int {
+	return p.uid
+}
+
+// ActorRefSet is a set of refs keyed by their identity instead of the
+// interface value, it is not safe for concurrent use
+type ActorRefSet struct {
+	refs map[ActorRefKey]ActorRef
+}
+
+func NewActorRefSet(refs ...ActorRef) *ActorRefSet {
+	set := &ActorRefSet{
+		refs: make(map[ActorRefKey]ActorRef, len(refs)),
+	}
+
+	for _, ref := range refs {
+		set.Add(ref)
+	}
+
+	return set
+}
+
+// Add returns false if a ref with the same identity is already in the set
+func (p *ActorRefSet) Add(ref ActorRef) bool {
+	key := KeyOfActorRef(ref)
+	if _, exist := p.refs[key]; exist {
+		return false
+	}
+
+	p.refs[key] = ref
+	return true
+}
+
+func (p *ActorRefSet) Remove(ref ActorRef) bool {
+	key := KeyOfActorRef(ref)
+	if _, exist := p.refs[key]; !exist {
+		return false
+	}
+
+	delete(p.refs, key)
+	return true
+}
+
+func (p *ActorRefSet) Contains(ref ActorRef) bool {
+	_, exist := p.refs[KeyOfActorRef(ref)]
+	return exist
+}
+
+func (p *ActorRefSet) Len() int {
+	return len(p.refs)
+}
+
+func (p *ActorRefSet) Refs() []ActorRef {
+	refs := make([]ActorRef, 0, len(p.refs))
+	for _, ref := range p.refs {
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+func (p *ActorRefSet) Clear() {
+	p.refs = make(map[ActorRefKey]ActorRef)
+}
+
+type actorRefMapEntry struct {
+	ref   ActorRef
+	value interface{}
+}
+
+// ActorRefMap maps refs to values by their identity, it is not safe for
+// concurrent use
+type ActorRefMap struct {
+	entries map[ActorRefKey]actorRefMapEntry
+}
+
+func NewActorRefMap() *ActorRefMap {
+	return &ActorRefMap{
+		entries: make(map[ActorRefKey]actorRefMapEntry),
+	}
+}
+
+func (p *ActorRefMap) Put(ref ActorRef, value interface{}) {
+	p.entries[KeyOfActorRef(ref)] = actorRefMapEntry{ref: ref, value: value}
+}
+
+func (p *ActorRefMap) Get(ref ActorRef) (value interface{}, exist bool) {
+	entry, exist := p.entries[KeyOfActorRef(ref)]
+	if !exist {
+		return
+	}
+
+	value = entry.value
+	return
+}
+
+func (p *ActorRefMap) Delete(ref ActorRef) bool {
+	key := KeyOfActorRef(ref)
+	if _, exist := p.entries[key]; !exist {
+		return false
+	}
+
+	delete(p.entries, key)
+	return true
+}
+
+func (p *ActorRefMap) Contains(ref ActorRef) bool {
+	_, exist := p.entries[KeyOfActorRef(ref)]
+	return exist
+}
+
+func (p *ActorRefMap) Len() int {
+	return len(p.entries)
+}
+
+func (p *ActorRefMap) Refs() []ActorRef {
+	refs := make([]ActorRef, 0, len(p.entries))
+	for _, entry := range p.entries {
+		refs = append(refs, entry.ref)
+	}
+	return refs
+}
+
+// Range stops as soon as fn returns false
+func (p *ActorRefMap) Range(fn func(ref ActorRef, value interface{}) bool) {
+	for _, entry := range p.entries {
+		if !fn(entry.ref, entry.value) {
+			return
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package akka
+
+import (
+	"fmt"
+	"testing"
+)
+
+func newTestRefs(count int) []ActorRef {
+	root := NewRootActorPath(NewAddress("akka", "ActorRefSetTest", "", 0), "")
+	user := root.Append("user")
+
+	var refs []ActorRef
+	for i := 0; i < count; i++ {
+		refs = append(refs, NewMinimalActorRef(user.Append(fmt.Sprintf("worker-%d#%d", i, i+1)), nil))
+	}
+	return refs
+}
+
+func TestActorRefIdentityAsMapKey(t *testing.T) {
+	refs := newTestRefs(1000)
+	copies := newTestRefs(1000)
+
+	registry := NewActorRefMap()
+	for i, ref := range refs {
+		registry.Put(ref, i)
+	}
+
+	if registry.Len() != len(refs) {
+		t.Fatalf("registry should hold %d refs, but holds %d", len(refs), registry.Len())
+	}
+
+	for i, ref := range copies {
+		if ref == refs[i] {
+			t.Fatalf("copies should be distinct ref values")
+		}
+
+		if !ref.Equals(refs[i]) || ref.CompareTo(refs[i]) != 0 {
+			t.Fatalf("%s should equal its copy", ref)
+		}
+
+		value, exist := registry.Get(ref)
+		if !exist || value.(int) != i {
+			t.Fatalf("copy of %s should resolve to %d, but got %v, %v", ref, i, value, exist)
+		}
+
+		registry.Put(ref, -i)
+	}
+
+	if registry.Len() != len(refs) {
+		t.Fatalf("putting equal refs should not grow the registry, but it holds %d", registry.Len())
+	}
+
+	set := NewActorRefSet(refs...)
+	for _, ref := range copies {
+		if set.Add(ref) {
+			t.Fatalf("%s should already be in the set", ref)
+		}
+	}
+
+	if set.Len() != len(refs) {
+		t.Fatalf("set should hold %d refs, but holds %d", len(refs), set.Len())
+	}
+
+	if !set.Remove(copies[0]) || set.Contains(refs[0]) {
+		t.Fatalf("removing a copy should remove the original")
+	}
+}
+
+func TestActorRefIdentityIncludesUid(t *testing.T) {
+	user := NewRootActorPath(NewAddress("akka", "ActorRefSetTest", "", 0), "").Append("user")
+
+	first := NewMinimalActorRef(user.Append("worker#1"), nil)
+	second := NewMinimalActorRef(user.Append("worker#2"), nil)
+
+	if first.Equals(second) {
+		t.Fatalf("refs with different uids should not be equal")
+	}
+
+	if KeyOfActorRef(first) == KeyOfActorRef(second) {
+		t.Fatalf("refs with different uids should not share a key")
+	}
+
+	if first.CompareTo(second) >= 0 || second.CompareTo(first) <= 0 {
+		t.Fatalf("refs with the same path should be ordered by uid")
+	}
+
+	set := NewActorRefSet(first, second)
+	if set.Len() != 2 {
+		t.Fatalf("set should hold both incarnations, but holds %d", set.Len())
+	}
+
+	if NoBody.Equals(first) || !(*NoSender)(nil).Equals(nil) {
+		t.Fatalf("NoBody and NoSender should only equal themselves")
+	}
+}
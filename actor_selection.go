@@ -1,13 +1,217 @@
 package akka
 
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ActorSelection resolves its path elements from the anchor every time a
+// message is told, ".." walks up to the parent and an element with wildcards
+// selects all matching children. Messages to a path that does not resolve go
+// to the dead letters of the anchor's provider
 type ActorSelection struct {
+	anchor   InternalActorRef
+	elements []string
+
 	context ActorContext
+	cache   ResolvedRefCache
+}
+
+// ResolvedRefCache remembers the actors selections resolved to, keyed by the
+// path string of the selection. It is shared by the selections of a system,
+// so it has to be safe for concurrent use
+type ResolvedRefCache interface {
+	Get(path string) (ref InternalActorRef, ok bool)
+	Put(path string, ref InternalActorRef)
+}
+
+// NewActorSelection selects path relative to anchor, like "../sibling" or
+// "child/grandchild", context is used by Forward to keep the sender
+func NewActorSelection(anchor InternalActorRef, path string, context ActorContext) ActorSelection {
+	return ActorSelection{
+		anchor:   anchor,
+		elements: strings.Split(path, "/"),
+		context:  context,
+	}
+}
+
+func (p *ActorSelection) Anchor() InternalActorRef {
+	return p.anchor
+}
+
+func (p *ActorSelection) PathString() string {
+	return strings.Join(p.elements, "/")
+}
+
+// WithCache returns a copy of the selection that resolves through cache, nil
+// turns the caching off
+func (p *ActorSelection) WithCache(cache ResolvedRefCache) ActorSelection {
+	selection := *p
+	selection.cache = cache
+	return selection
+}
+
+// Resolve walks the elements from the anchor, ok is false once an element
+// does not lead to an actor. A selection with wildcards resolves to the
+// first of its matches
+func (p *ActorSelection) Resolve() (ref InternalActorRef, ok bool) {
+	refs := p.ResolveAll()
+	if len(refs) == 0 {
+		return nil, false
+	}
+
+	return refs[0], true
+}
+
+// ResolveOne is Resolve through the cache of the selection, the walk is only
+// done when the path is not cached yet or its actor has terminated since, so
+// an actor created again under the same name, with a new uid, is resolved
+// again. Selections with wildcards are never cached
+func (p *ActorSelection) ResolveOne() (ref InternalActorRef, ok bool) {
+	if p.cache == nil || p.anchor == nil || p.hasPattern() {
+		return p.Resolve()
+	}
+
+	key := p.cacheKey()
+	if ref, ok = p.cache.Get(key); ok && !ref.IsTerminated() {
+		return
+	}
+
+	if ref, ok = p.Resolve(); ok {
+		p.cache.Put(key, ref)
+	}
+
+	return
+}
+
+// ResolveAll walks the elements from the anchor and returns every actor they
+// lead to. An element with the wildcards of path.Match, like "worker-*", is
+// matched against the whole name of each child, case-sensitive
+func (p *ActorSelection) ResolveAll() (refs []InternalActorRef) {
+	if p.anchor == nil {
+		return
+	}
+
+	refs = []InternalActorRef{p.anchor}
+	for _, element := range p.elements {
+		var next []InternalActorRef
+
+		for _, ref := range refs {
+			switch {
+			case element == "" || element == ".":
+				{
+					next = append(next, ref)
+				}
+			case element == "..":
+				{
+					next = appendResolved(next, ref.Parent())
+				}
+			case isSelectionPattern(element):
+				{
+					next = append(next, matchChildren(ref, element)...)
+				}
+			default:
+				next = appendResolved(next, ref.GetChild(element))
+			}
+		}
+
+		if refs = next; len(refs) == 0 {
+			return nil
+		}
+	}
+
+	return
 }
 
 func (p *ActorSelection) Tell(message interface{}, sender ActorRef) (err error) {
+	if p.cache != nil && !p.hasPattern() {
+		if ref, ok := p.ResolveOne(); ok {
+			return ref.Tell(message, sender)
+		}
+	} else if refs := p.ResolveAll(); len(refs) > 0 {
+		for _, ref := range refs {
+			if e := ref.Tell(message, sender); e != nil && err == nil {
+				err = e
+			}
+		}
+		return
+	}
+
+	if p.anchor == nil {
+		return
+	}
+
+	if deadLetters := p.anchor.Provider().DeadLetters(); deadLetters != nil {
+		return deadLetters.Tell(NewDeadLetter(message, sender, p.anchor), sender)
+	}
+
 	return
 }
 
 func (p *ActorSelection) Forward(message interface{}) {
-	p.Tell(message, p.context.Sender())
+	var sender ActorRef
+	if p.context != nil {
+		sender = p.context.Sender()
+	}
+	p.Tell(message, sender)
+}
+
+func (p *ActorSelection) String() string {
+	if p.anchor == nil {
+		return "ActorSelection[" + p.PathString() + "]"
+	}
+	return "ActorSelection[Anchor(" + p.anchor.Path().String() + "), Path(" + p.PathString() + ")]"
+}
+
+func (p *ActorSelection) hasPattern() bool {
+	for _, element := range p.elements {
+		if isSelectionPattern(element) {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheKey is the path of the anchor with its uid, relative paths of
+// different incarnations of an anchor are different selections
+func (p *ActorSelection) cacheKey() string {
+	return p.anchor.Path().String() + "#" + strconv.Itoa(p.anchor.Path().Uid()) + "|" + p.PathString()
+}
+
+func isSelectionPattern(element string) bool {
+	return strings.ContainsAny(element, "*?[")
+}
+
+func appendResolved(refs []InternalActorRef, ref InternalActorRef) []InternalActorRef {
+	if ref == nil || ref == InternalActorRef(NoBody) {
+		return refs
+	}
+	return append(refs, ref)
+}
+
+// matchChildren returns the children of ref whose name matches pattern,
+// ordered by name
+func matchChildren(ref InternalActorRef, pattern string) (matches []InternalActorRef) {
+	withCell, ok := ref.(ActorRefWithCell)
+	if !ok {
+		return
+	}
+
+	for _, child := range withCell.Children() {
+		if matched, err := path.Match(pattern, child.Path().Name()); err != nil || !matched {
+			continue
+		}
+
+		if internal, ok := child.(InternalActorRef); ok {
+			matches = append(matches, internal)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Path().Name() < matches[j].Path().Name()
+	})
+
+	return
 }
@@ -1,6 +1,7 @@
 package akka
 
 import (
+	"github.com/go-akka/akka/pkg/class_loader"
 	"github.com/go-akka/akka/pkg/dynamic_access"
 	"sync"
 )
@@ -14,10 +15,17 @@ type ActorSystem interface {
 	DeadLetters() ActorRef
 	Terminate() sync.WaitGroup
 
+	// WhenTerminated is closed once the system has terminated
+	WhenTerminated() <-chan struct{}
+
 	EventStream() EventStream
+	Scheduler() Scheduler
 
 	RegisterOnTermination(fn func())
 
+	// OnUncaughtFailure sets the handler invoked for failures escalated to the guardian.
+	OnUncaughtFailure(fn UncaughtFailureHandler)
+
 	// Child is Create a new child actor path.
 	Child(child string) (path ActorPath, err error)
 
@@ -43,6 +51,7 @@ type ExtendedActorSystem interface {
 	SystemGuardian() LocalActorRef
 
 	SystemActorOf(props Props, name string) (ref ActorRef, err error)
+	ClassLoader() class_loader.ClassLoader
 	DynamicAccess() dynamic_access.DynamicAccess
 
 	LogFilter() LoggingFilter
@@ -75,19 +75,27 @@ func (p *ChildActorPath) Root() (root *RootActorPath) {
 }
 
 func (p *ChildActorPath) CompareTo(other ActorPath) int {
-	return 0
+	return compareActorPaths(p, other)
 }
 
 func (p *ChildActorPath) ToSerializationFormat() string {
-	return ""
+	return p.ToSerializationFormatWithAddress(p.Address())
 }
 
+// ToSerializationFormatWithAddress is ToStringWithAddress with the uid
+// appended, so the ref resolves to the same incarnation elsewhere
 func (p *ChildActorPath) ToSerializationFormatWithAddress(address Address) string {
-	return ""
+	if p.uid == 0 {
+		return p.ToStringWithAddress(address)
+	}
+	return fmt.Sprintf("%s#%d", p.ToStringWithAddress(address), p.uid)
 }
 
+// ToStringWithAddress renders the path with address, unless the path has a
+// host and port of its own, e.g. the local paths are rendered with the
+// address they are reachable at remotely
 func (p *ChildActorPath) ToStringWithAddress(address Address) string {
-	return address.String() + p.Join()
+	return renderedAddress(p.Address(), address).String() + p.Join()
 }
 
 func (p *ChildActorPath) splitNameAndUid(name string) (n string, uid int) {
@@ -125,11 +133,7 @@ func (p *ChildActorPath) Join() string {
 }
 
 func (p *ChildActorPath) String() string {
-
-	if p.Uid() == 0 {
-		return p.ToStringWithAddress(p.Address())
-	}
-	return fmt.Sprintf("%s#%d", p.ToStringWithAddress(p.Address()), p.Uid())
+	return p.ToSerializationFormat()
 }
 
 func (p *ChildActorPath) reverse(values []string) []string {
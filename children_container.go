@@ -1,5 +1,30 @@
 package akka
 
+import (
+	"time"
+)
+
+type ChildState int
+
+const (
+	ChildRunning ChildState = iota
+	// ChildSuspended is a child that failed and waits for its supervisor
+	ChildSuspended
+	ChildStopping
+)
+
+func (p ChildState) String() string {
+	switch p {
+	case ChildRunning:
+		return "Running"
+	case ChildSuspended:
+		return "Suspended"
+	case ChildStopping:
+		return "Stopping"
+	}
+	return "Unknown"
+}
+
 type ChildStats interface {
 	ChildStats()
 }
@@ -14,7 +39,20 @@ type ChildRestartStats interface {
 	ChildStats
 
 	Child() InternalActorRef
+	RequestRestartPermission(maxNrOfRetries, withinTimeMilliseconds int) bool
 	ChildRestartStats()
+
+	RestartCount() int
+	LastFailure() (cause error, at time.Time)
+	// FailedEnvelope is the message the child failed on, until it is resumed
+	// or restarted, nil if the failure was escalated
+	FailedEnvelope() *Envelope
+	CreatedAt() time.Time
+	// Uptime is the time since the child was created or last restarted
+	Uptime() time.Duration
+	State() ChildState
+	// SuspensionReason is the SuspensionReason of the cell of the child
+	SuspensionReason() string
 }
 
 type ChildrenContainer interface {
@@ -1,5 +1,11 @@
 package akka
 
+// DeadLetterSuppression marks messages that should not be published as
+// DeadLetter when they cannot be delivered, like heartbeats during shutdown
+type DeadLetterSuppression interface {
+	DeadLetterSuppression()
+}
+
 type DeadLetter struct {
 	message   interface{}
 	sender    ActorRef
@@ -13,3 +19,38 @@ func NewDeadLetter(message interface{}, sender ActorRef, recipient ActorRef) Dea
 		recipient: recipient,
 	}
 }
+
+func (p DeadLetter) Message() interface{} {
+	return p.message
+}
+
+func (p DeadLetter) Sender() ActorRef {
+	return p.sender
+}
+
+func (p DeadLetter) Recipient() ActorRef {
+	return p.recipient
+}
+
+// NoSerializationVerificationNeeded skips the check of dead letters, they are
+// only published to local subscribers whatever message they carry
+func (p DeadLetter) NoSerializationVerificationNeeded() {}
+
+// SuppressedDeadLetter is published instead of DeadLetter for messages marked
+// with DeadLetterSuppression, it rolls up Count suppressed dead letters and
+// carries the last of them
+type SuppressedDeadLetter struct {
+	DeadLetter
+	count int64
+}
+
+func NewSuppressedDeadLetter(deadLetter DeadLetter, count int64) SuppressedDeadLetter {
+	return SuppressedDeadLetter{
+		DeadLetter: deadLetter,
+		count:      count,
+	}
+}
+
+func (p SuppressedDeadLetter) Count() int64 {
+	return p.count
+}
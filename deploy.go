@@ -4,6 +4,9 @@ import (
 	"github.com/go-akka/configuration"
 )
 
+// Deploy carries the dispatcher, mailbox and router of an actor, it is the
+// same for deployments from config and from code. It is immutable, the With
+// methods return a changed copy
 type Deploy struct {
 	scope        Scope
 	routerConfig RouterConfig
@@ -13,18 +16,43 @@ type Deploy struct {
 	config       *configuration.Config
 }
 
+// NewDeploy creates the deployment of path, empty dispatcher and mailbox fall
+// back to the defaults and a nil routerConfig means NoRouter
+func NewDeploy(path string, config *configuration.Config, routerConfig RouterConfig, dispatcher, mailbox string) Deploy {
+	if routerConfig == nil {
+		routerConfig = NoRouter{}
+	}
+
+	return Deploy{
+		path:         path,
+		config:       config,
+		routerConfig: routerConfig,
+		dispatcher:   dispatcher,
+		mailbox:      mailbox,
+	}
+}
+
 func (p Deploy) WithFallback(other Deploy) (deploy Deploy) {
 
 	newDeploy := Deploy{
+		path:       p.path,
 		dispatcher: p.dispatcher,
 		mailbox:    p.mailbox,
+		config:     p.config,
 	}
 
-	if p.config != other.config {
+	if p.config == nil {
+		newDeploy.config = other.config
+	} else if other.config != nil && p.config != other.config {
 		newDeploy.config = p.config.WithFallback(other.config)
 	}
 
-	newDeploy.routerConfig = p.routerConfig.WithFallback(other.routerConfig)
+	if p.routerConfig == nil {
+		newDeploy.routerConfig = other.routerConfig
+	} else {
+		newDeploy.routerConfig = p.routerConfig.WithFallback(other.routerConfig)
+	}
+
 	newDeploy.scope = p.scope.WithFallback(other.scope)
 
 	if len(p.dispatcher) == 0 {
@@ -58,12 +86,20 @@ func (p Deploy) WithDispatcher(dispatcher string) Deploy {
 	return deploy
 }
 
-func (p *Deploy) WithRouterConfig(routerConfig RouterConfig) Deploy {
+func (p Deploy) WithRouterConfig(routerConfig RouterConfig) Deploy {
 	deploy := p.copy()
 	deploy.routerConfig = routerConfig
 	return deploy
 }
 
+func (p Deploy) Path() string {
+	return p.path
+}
+
+func (p Deploy) Config() *configuration.Config {
+	return p.config
+}
+
 func (p Deploy) Dispatcher() string {
 	return p.dispatcher
 }
@@ -1,11 +1,88 @@
 package akka
 
+import (
+	"strings"
+
+	"github.com/go-akka/akka/pkg/dynamic_access"
+	"github.com/go-akka/configuration"
+)
+
+// Deployer holds the deployments configured under akka.actor.deployment, they
+// are keyed by the actor path below the guardian, e.g. /parent/child.
+// The configured settings take precedence over the Deploy of the Props given
+// in code, which is only used as fallback, a router configured as from-code
+// keeps the router of the Props
 type Deployer struct {
-	settings Settings
+	settings      *Settings
+	dynamicAccess dynamic_access.DynamicAccess
+	deployments   map[string]Deploy
 }
 
-func NewDeployer(settings Settings) Deployer {
-	return Deployer{
-		settings: settings,
+func NewDeployer(settings *Settings, dynamicAccess dynamic_access.DynamicAccess) (deployer Deployer, err error) {
+	deployer = Deployer{
+		settings:      settings,
+		dynamicAccess: dynamicAccess,
+		deployments:   make(map[string]Deploy),
+	}
+
+	deploymentConfig := settings.Config().GetConfig("akka.actor.deployment")
+	if deploymentConfig.IsEmpty() {
+		return
 	}
+
+	for key, value := range deploymentConfig.Root().GetObject().Items() {
+		key = strings.Trim(key, "\"")
+		if !strings.HasPrefix(key, "/") || !value.IsObject() {
+			continue
+		}
+
+		var deploy Deploy
+		if deploy, err = deployer.ParseConfig(key, configuration.NewConfigFromRoot(value)); err != nil {
+			return
+		}
+
+		deployer.deployments[key] = deploy
+	}
+
+	return
+}
+
+// Lookup finds the deployment of path, the guardian element is not part of the key
+func (p Deployer) Lookup(path ActorPath) (deploy Deploy, exist bool) {
+	elements := path.Elements()
+	if len(elements) < 2 {
+		return
+	}
+
+	deploy, exist = p.deployments["/"+strings.Join(elements[1:], "/")]
+	return
+}
+
+func (p Deployer) ParseConfig(key string, config *configuration.Config) (deploy Deploy, err error) {
+	deploy = NewDeploy(key, config, NoRouter{}, config.GetString("dispatcher"), config.GetString("mailbox"))
+
+	routerType := config.GetString("router", "from-code")
+	if routerType == "from-code" {
+		return
+	}
+
+	routerClass := p.settings.Config().GetString("akka.actor.router.type-mapping." + routerType)
+	if len(routerClass) == 0 {
+		routerClass = routerType
+	}
+
+	var ins interface{}
+	if ins, err = p.dynamicAccess.CreateInstanceByName(routerClass, p.settings, config); err != nil {
+		return
+	}
+
+	routerConfig, ok := ins.(RouterConfig)
+	if !ok {
+		err = ErrBadTypeOfRouterConfig
+		return
+	}
+
+	deploy.routerConfig = routerConfig
+
+	return
 }
@@ -34,3 +34,49 @@ func (p *DeadLetterMailbox) Dequeue() (envelope akka.Envelope, ok bool) {
 func (p *DeadLetterMailbox) CleanUp(owner akka.ActorRef, deadLetters akka.MessageQueue) (err error) {
 	return
 }
+
+// deadLetterQueue hands the messages of a force-closed mailbox to the dead
+// letters of the system of its actor, or publishes them as DeadLetter on the
+// event stream if the system has none
+type deadLetterQueue struct {
+	system akka.ActorSystem
+}
+
+func newDeadLetterQueue(actor akka.ActorCell) akka.MessageQueue {
+	queue := &deadLetterQueue{}
+	if cell, ok := actor.(interface{ System() akka.ActorSystem }); ok {
+		queue.system = cell.System()
+	}
+	return queue
+}
+
+func (p *deadLetterQueue) Enqueue(receiver akka.ActorRef, envelope akka.Envelope) (err error) {
+	if p.system == nil {
+		return
+	}
+
+	deadLetter := akka.NewDeadLetter(envelope.Message, envelope.Sender, receiver)
+
+	if deadLetters := p.system.DeadLetters(); deadLetters != nil {
+		return deadLetters.Tell(deadLetter, envelope.Sender)
+	}
+
+	p.system.EventStream().Publish(deadLetter)
+	return
+}
+
+func (p *deadLetterQueue) Dequeue() (envelope akka.Envelope, ok bool) {
+	return
+}
+
+func (p *deadLetterQueue) CleanUp(owner akka.ActorRef, deadLetters akka.MessageQueue) (err error) {
+	return
+}
+
+func (p *deadLetterQueue) NumberOfMessages() int {
+	return 0
+}
+
+func (p *deadLetterQueue) HasMessages() bool {
+	return false
+}
@@ -0,0 +1,314 @@
+package dispatch
+
+import (
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/pkg/class_loader"
+	"github.com/go-akka/akka/serialization"
+	"github.com/go-akka/configuration"
+)
+
+func init() {
+	class_loader.Default.Register((*DiskSpillingMailbox)(nil), "akka.dispatch.disk-spilling-mailbox")
+}
+
+const (
+	DefaultSpillingInMemoryLimit = 1000
+
+	spillRecordHeaderSize = 4 + 8
+)
+
+// Serializers finds the serializer of a spilled message, it is the
+// serialization of the system, so the registered serializers apply
+type Serializers interface {
+	FindSerializerFor(message interface{}) (serializer akka.Serializer, err error)
+}
+
+// SpillFailure is dead-lettered in place of a spilled message that could not
+// be read back, Data is what was read of it
+type SpillFailure struct {
+	Data  []byte
+	Cause error
+}
+
+type DiskSpillingMailbox struct {
+	inMemoryLimit  int
+	spillDirectory string
+}
+
+func NewDiskSpillingMailbox(inMemoryLimit int, spillDirectory string) akka.MailboxType {
+	return &DiskSpillingMailbox{
+		inMemoryLimit:  inMemoryLimit,
+		spillDirectory: spillDirectory,
+	}
+}
+
+func (p *DiskSpillingMailbox) Construct(settings *akka.Settings, config *configuration.Config) (err error) {
+	return p.Init(settings, config)
+}
+
+func (p *DiskSpillingMailbox) Init(settings *akka.Settings, config *configuration.Config) (err error) {
+	p.inMemoryLimit = int(config.GetInt32("in-memory-limit", DefaultSpillingInMemoryLimit))
+	p.spillDirectory = config.GetString("spill-directory", os.TempDir())
+	return
+}
+
+func (p *DiskSpillingMailbox) Create(owner akka.ActorRef, system akka.ActorSystem) akka.MessageQueue {
+	var serializers Serializers
+	if provider, ok := system.(interface {
+		Serialization() *serialization.Serialization
+	}); ok && provider.Serialization() != nil {
+		serializers = provider.Serialization()
+	}
+
+	return NewDiskSpillingMessageQueue(p.inMemoryLimit, p.spillDirectory, serializers, owner, system)
+}
+
+// spilledEnvelope keeps the parts of a spilled envelope that are not written
+// to the spill file, the message is read back into its type with the
+// serializer it was written with
+type spilledEnvelope struct {
+	sender     akka.ActorRef
+	headers    akka.Headers
+	typ        reflect.Type
+	serializer akka.Serializer
+}
+
+// DiskSpillingMessageQueue keeps up to inMemoryLimit envelopes in memory, once
+// the limit is reached every new message is appended to a spill file until the
+// file has been read back completely, so the delivery order is preserved
+type DiskSpillingMessageQueue struct {
+	inMemoryLimit  int
+	spillDirectory string
+	serializers    Serializers
+
+	// owner and system take the messages that could not be read back
+	owner  akka.ActorRef
+	system akka.ActorSystem
+
+	memory []akka.Envelope
+
+	spillFile   *os.File
+	readOffset  int64
+	writeOffset int64
+	spilled     []spilledEnvelope
+
+	locker sync.Mutex
+}
+
+// NewDiskSpillingMessageQueue keeps every message in memory without
+// serializers, a message without a serializer is refused once the limit is
+// reached
+func NewDiskSpillingMessageQueue(inMemoryLimit int, spillDirectory string, serializers Serializers, owner akka.ActorRef, system akka.ActorSystem) *DiskSpillingMessageQueue {
+	if inMemoryLimit <= 0 {
+		inMemoryLimit = DefaultSpillingInMemoryLimit
+	}
+
+	if len(spillDirectory) == 0 {
+		spillDirectory = os.TempDir()
+	}
+
+	return &DiskSpillingMessageQueue{
+		inMemoryLimit:  inMemoryLimit,
+		spillDirectory: spillDirectory,
+		serializers:    serializers,
+		owner:          owner,
+		system:         system,
+	}
+}
+
+func (p *DiskSpillingMessageQueue) Enqueue(receiver akka.ActorRef, envelope akka.Envelope) (err error) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	if p.serializers == nil || (len(p.spilled) == 0 && len(p.memory) < p.inMemoryLimit) {
+		p.memory = append(p.memory, envelope)
+		return
+	}
+
+	return p.spill(envelope)
+}
+
+func (p *DiskSpillingMessageQueue) Dequeue() (envelope akka.Envelope, ok bool) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	if len(p.memory) > 0 {
+		envelope = p.memory[0]
+		p.memory[0] = akka.Envelope{}
+		p.memory = p.memory[1:]
+		return envelope, true
+	}
+
+	for len(p.spilled) > 0 {
+		spilled, data, err := p.unspill()
+		if err == nil {
+			return spilled, true
+		}
+		p.spillFailed(spilled, data, err)
+	}
+
+	return
+}
+
+// spillFailed logs the failure to read a spilled message back and hands what
+// is left of it to the dead letters
+func (p *DiskSpillingMessageQueue) spillFailed(envelope akka.Envelope, data []byte, cause error) {
+	if p.system == nil {
+		return
+	}
+
+	p.system.Log().Error(cause, "spilled message of %v could not be read back, it is sent to the dead letters", p.owner)
+
+	deadLetter := akka.NewDeadLetter(&SpillFailure{Data: data, Cause: cause}, envelope.Sender, p.owner)
+	if deadLetters := p.system.DeadLetters(); deadLetters != nil {
+		deadLetters.Tell(deadLetter, envelope.Sender)
+		return
+	}
+
+	p.system.EventStream().Publish(deadLetter)
+}
+
+func (p *DiskSpillingMessageQueue) CleanUp(owner akka.ActorRef, deadLetters akka.MessageQueue) (err error) {
+	for {
+		msg, ok := p.Dequeue()
+		if !ok {
+			break
+		}
+
+		if deadLetters != nil {
+			deadLetters.Enqueue(owner, msg)
+		}
+	}
+
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return p.removeSpillFile()
+}
+
+func (p *DiskSpillingMessageQueue) NumberOfMessages() int {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return len(p.memory) + len(p.spilled)
+}
+
+func (p *DiskSpillingMessageQueue) HasMessages() bool {
+	return p.NumberOfMessages() > 0
+}
+
+func (p *DiskSpillingMessageQueue) NumberOfSpilledMessages() int {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return len(p.spilled)
+}
+
+func (p *DiskSpillingMessageQueue) spill(envelope akka.Envelope) (err error) {
+	var serializer akka.Serializer
+	if serializer, err = p.serializers.FindSerializerFor(envelope.Message); err != nil {
+		return
+	}
+
+	var data []byte
+	if data, err = serializer.ToBinary(envelope.Message); err != nil {
+		return
+	}
+
+	if p.spillFile == nil {
+		if p.spillFile, err = ioutil.TempFile(p.spillDirectory, "akka-mailbox-"); err != nil {
+			return
+		}
+	}
+
+	// a record is the length of data, the enqueue time and data
+	record := make([]byte, spillRecordHeaderSize+len(data))
+	binary.BigEndian.PutUint32(record, uint32(len(data)))
+	if !envelope.EnqueuedAt.IsZero() {
+		binary.BigEndian.PutUint64(record[4:], uint64(envelope.EnqueuedAt.UnixNano()))
+	}
+	copy(record[spillRecordHeaderSize:], data)
+
+	if _, err = p.spillFile.WriteAt(record, p.writeOffset); err != nil {
+		return
+	}
+
+	p.writeOffset += int64(len(record))
+	p.spilled = append(p.spilled, spilledEnvelope{
+		sender:     envelope.Sender,
+		headers:    envelope.Headers,
+		typ:        reflect.TypeOf(envelope.Message),
+		serializer: serializer,
+	})
+
+	return
+}
+
+// unspill reads the next spilled message back, the envelope keeps its sender
+// and data what was read of it when it fails
+func (p *DiskSpillingMessageQueue) unspill() (envelope akka.Envelope, data []byte, err error) {
+	spilled := p.spilled[0]
+	p.spilled[0] = spilledEnvelope{}
+	p.spilled = p.spilled[1:]
+
+	envelope = akka.Envelope{Sender: spilled.sender, Headers: spilled.headers}
+
+	defer func() {
+		if len(p.spilled) == 0 {
+			p.truncateSpillFile()
+		}
+	}()
+
+	header := make([]byte, spillRecordHeaderSize)
+	if _, err = p.spillFile.ReadAt(header, p.readOffset); err != nil {
+		return
+	}
+
+	if enqueuedAt := int64(binary.BigEndian.Uint64(header[4:])); enqueuedAt != 0 {
+		envelope.EnqueuedAt = time.Unix(0, enqueuedAt)
+	}
+
+	data = make([]byte, binary.BigEndian.Uint32(header))
+	offset := p.readOffset + spillRecordHeaderSize
+	p.readOffset = offset + int64(len(data))
+
+	if _, err = p.spillFile.ReadAt(data, offset); err != nil && err != io.EOF {
+		return
+	}
+
+	envelope.Message, err = spilled.serializer.FromBinaryWithType(data, spilled.typ)
+
+	return
+}
+
+func (p *DiskSpillingMessageQueue) truncateSpillFile() {
+	p.readOffset = 0
+	p.writeOffset = 0
+
+	if p.spillFile != nil {
+		p.spillFile.Truncate(0)
+	}
+}
+
+func (p *DiskSpillingMessageQueue) removeSpillFile() (err error) {
+	if p.spillFile == nil {
+		return
+	}
+
+	name := p.spillFile.Name()
+	p.spillFile.Close()
+	p.spillFile = nil
+	p.spilled = nil
+	p.readOffset = 0
+	p.writeOffset = 0
+
+	return os.Remove(name)
+}
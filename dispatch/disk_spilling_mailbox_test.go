@@ -0,0 +1,98 @@
+package dispatch
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/serialization"
+)
+
+type jsonSerializers struct{}
+
+func (jsonSerializers) FindSerializerFor(message interface{}) (akka.Serializer, error) {
+	return &serialization.JSONSerializer{}, nil
+}
+
+func TestDiskSpillingMessageQueuePreservesOrder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "akka-spill-test")
+	if err != nil {
+		t.Fatalf("create temp dir failure: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	queue := NewDiskSpillingMessageQueue(5, dir, jsonSerializers{}, nil, nil)
+
+	next := 0
+	enqueue := func(n int) {
+		for i := 0; i < n; i++ {
+			if err := queue.Enqueue(nil, akka.Envelope{Message: next}); err != nil {
+				t.Fatalf("enqueue failure: %s", err.Error())
+			}
+			next++
+		}
+	}
+
+	expected := 0
+	dequeue := func(n int) {
+		for i := 0; i < n; i++ {
+			envelope, ok := queue.Dequeue()
+			if !ok {
+				t.Fatalf("message %d should be dequeued", expected)
+			}
+			if envelope.Message != expected {
+				t.Fatalf("message should be %d, but got %v", expected, envelope.Message)
+			}
+			expected++
+		}
+	}
+
+	enqueue(20)
+
+	spilledAt := time.Now().Add(-time.Minute)
+	if err = queue.Enqueue(nil, akka.Envelope{Message: next, EnqueuedAt: spilledAt}); err != nil {
+		t.Fatalf("enqueue failure: %s", err.Error())
+	}
+	next++
+
+	if queue.NumberOfMessages() != 21 {
+		t.Fatalf("queue should have 21 messages, but got %d", queue.NumberOfMessages())
+	}
+
+	if queue.NumberOfSpilledMessages() != 16 {
+		t.Fatalf("queue should spill 16 messages, but got %d", queue.NumberOfSpilledMessages())
+	}
+
+	dequeue(7)
+	enqueue(3)
+	dequeue(13)
+
+	envelope, ok := queue.Dequeue()
+	if !ok || envelope.Message != expected || !envelope.EnqueuedAt.Equal(spilledAt) {
+		t.Fatalf("spilled message %d should keep its enqueue time %s, but got %v at %s", expected, spilledAt, envelope.Message, envelope.EnqueuedAt)
+	}
+	expected++
+
+	dequeue(3)
+
+	if queue.HasMessages() {
+		t.Fatalf("queue should be empty")
+	}
+
+	enqueue(2)
+	if queue.NumberOfSpilledMessages() != 0 {
+		t.Fatalf("queue should keep messages in memory once the spill file is drained")
+	}
+	dequeue(2)
+
+	if err = queue.CleanUp(nil, nil); err != nil {
+		t.Fatalf("clean up failure: %s", err.Error())
+	}
+
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 0 {
+		t.Fatalf("spill file should be removed on clean up")
+	}
+}
@@ -3,19 +3,61 @@ package dispatch
 import (
 	"github.com/go-akka/akka"
 	"github.com/go-akka/akka/pkg/class_loader"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+const (
+	DefaultSystemMessageDrainInterval = 1
+	DefaultSystemMessageQuota         = 100
+	DefaultBlockingCallThreshold      = time.Duration(0)
+	DefaultShutdownTimeout            = time.Second
+)
+
 func init() {
 	class_loader.Default.Register((*Dispatcher)(nil), "dispatcher")
 }
 
 type Dispatcher struct {
+	id                      string
 	configurator            akka.MessageDispatcherConfigurator
 	executorServiceDelegate *LazyExecutorServiceDelegate
 
 	throughput             int
 	throughputDeadlineTime time.Duration
+
+	systemMessageDrainInterval int
+	systemMessageQuota         int
+	blockingCallThreshold      time.Duration
+
+	shutdownTimeout time.Duration
+	shutdown        bool
+	shutdownLocker  sync.RWMutex
+	runningCount    int32
+	// idle is closed once the dispatcher is shut down and no mailbox runs
+	idle     chan struct{}
+	idleOnce sync.Once
+
+	mailboxes         map[akka.Mailbox]akka.ActorCell
+	mailboxesLocker   sync.Mutex
+	detachedProcessed int64
+	detachedDwell     time.Duration
+}
+
+type mailboxRunner struct {
+	mailbox    akka.Mailbox
+	dispatcher *Dispatcher
+}
+
+func (p *mailboxRunner) Run() {
+	defer func() {
+		if atomic.AddInt32(&p.dispatcher.runningCount, -1) == 0 && p.dispatcher.IsShutdown() {
+			p.dispatcher.becomeIdle()
+		}
+	}()
+
+	p.mailbox.Run()
 }
 
 func NewDispatcher(
@@ -26,18 +68,70 @@ func NewDispatcher(
 	executorServiceFactoryProvider ExecutorServiceFactoryProvider,
 ) akka.MessageDispatcher {
 
+	drainInterval := DefaultSystemMessageDrainInterval
+	systemMessageQuota := DefaultSystemMessageQuota
+	blockingCallThreshold := DefaultBlockingCallThreshold
+	shutdownTimeout := DefaultShutdownTimeout
+	if configurator != nil && configurator.Config() != nil {
+		drainInterval = int(configurator.Config().GetInt32("system-message-drain-interval", DefaultSystemMessageDrainInterval))
+		systemMessageQuota = int(configurator.Config().GetInt32("system-message-quota", DefaultSystemMessageQuota))
+		blockingCallThreshold = configurator.Config().GetTimeDuration("blocking-call-warning-threshold", DefaultBlockingCallThreshold)
+		shutdownTimeout = configurator.Config().GetTimeDuration("shutdown-timeout", DefaultShutdownTimeout)
+	}
+
 	return &Dispatcher{
-		configurator:            configurator,
-		executorServiceDelegate: NewLazyExecutorServiceDelegate(executorServiceFactoryProvider.CreateExecutorServiceFactory(id)),
+		id:                         id,
+		configurator:               configurator,
+		throughput:                 throughput,
+		throughputDeadlineTime:     throughputDeadlineTime,
+		mailboxes:                  make(map[akka.Mailbox]akka.ActorCell),
+		idle:                       make(chan struct{}),
+		systemMessageDrainInterval: drainInterval,
+		systemMessageQuota:         systemMessageQuota,
+		blockingCallThreshold:      blockingCallThreshold,
+		shutdownTimeout:            shutdownTimeout,
+		executorServiceDelegate:    NewLazyExecutorServiceDelegate(executorServiceFactoryProvider.CreateExecutorServiceFactory(id)),
 	}
 }
 
 func (p *Dispatcher) Attach(actor akka.ActorCell) {
-	//TODO: register
+	p.mailboxesLocker.Lock()
+	p.mailboxes[actor.Mailbox()] = actor
+	p.mailboxesLocker.Unlock()
+
 	p.RegisterForExecution(actor.Mailbox(), false, true)
 }
 
 func (p *Dispatcher) Detach(actor akka.ActorCell) {
+	mailbox := actor.Mailbox()
+
+	p.mailboxesLocker.Lock()
+	defer p.mailboxesLocker.Unlock()
+
+	if _, exist := p.mailboxes[mailbox]; exist {
+		delete(p.mailboxes, mailbox)
+		p.detachedProcessed += mailbox.ProcessedMessages()
+		p.detachedDwell += mailbox.MessageDwellTime()
+	}
+}
+
+func (p *Dispatcher) Metrics() (metrics akka.DispatcherMetrics) {
+	p.mailboxesLocker.Lock()
+	defer p.mailboxesLocker.Unlock()
+
+	metrics.Id = p.id
+	metrics.Throughput = p.throughput
+	metrics.Mailboxes = len(p.mailboxes)
+	metrics.RunningMailboxes = int(atomic.LoadInt32(&p.runningCount))
+	metrics.ProcessedMessages = p.detachedProcessed
+	metrics.MessageDwellTime = p.detachedDwell
+
+	for mailbox := range p.mailboxes {
+		metrics.QueuedMessages += mailbox.NumberOfMessages()
+		metrics.ProcessedMessages += mailbox.ProcessedMessages()
+		metrics.MessageDwellTime += mailbox.MessageDwellTime()
+	}
+
 	return
 }
 
@@ -55,16 +149,73 @@ func (p *Dispatcher) Mailboxes() {
 
 func (p *Dispatcher) RegisterForExecution(mailbox akka.Mailbox, hasMessageHint bool, hasSystemMessageHint bool) bool {
 
+	p.shutdownLocker.RLock()
+
+	if p.shutdown {
+		p.shutdownLocker.RUnlock()
+		return false
+	}
+
 	if mailbox.CanBeScheduledForExecution(hasMessageHint, hasSystemMessageHint) {
 		if mailbox.SetAsScheduled() {
-			p.executorService().Execute(mailbox)
+			atomic.AddInt32(&p.runningCount, 1)
+			p.shutdownLocker.RUnlock()
+
+			runner := &mailboxRunner{mailbox: mailbox, dispatcher: p}
+			p.executorService().Execute(runner.Run)
 			return true
 		}
 	}
 
+	p.shutdownLocker.RUnlock()
+
 	return false
 }
 
+func (p *Dispatcher) IsShutdown() bool {
+	p.shutdownLocker.RLock()
+	defer p.shutdownLocker.RUnlock()
+
+	return p.shutdown
+}
+
+// Shutdown waits up to timeout, or the shutdown-timeout of the dispatcher if
+// it is not positive, for the running mailboxes. The mailboxes still running
+// then are force-closed and their pending messages go to the dead letters,
+// the executor is shut down either way
+func (p *Dispatcher) Shutdown(timeout time.Duration) (terminated bool) {
+	if timeout <= 0 {
+		timeout = p.shutdownTimeout
+	}
+
+	p.shutdownLocker.Lock()
+	p.shutdown = true
+	p.shutdownLocker.Unlock()
+
+	// no mailbox is scheduled any more, the last one running signals idle
+	if atomic.LoadInt32(&p.runningCount) == 0 {
+		p.becomeIdle()
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-p.idle:
+		terminated = true
+	case <-timer.C:
+		p.forceClose()
+	}
+
+	p.executorService().Shutdown()
+
+	return
+}
+
+func (p *Dispatcher) becomeIdle() {
+	p.idleOnce.Do(func() { close(p.idle) })
+}
+
 func (p *Dispatcher) CreateMailbox(actor akka.Cell, mailboxType akka.MailboxType) akka.Mailbox {
 	return newMailbox(mailboxType.Create(actor.Self(), actor.System()))
 }
@@ -77,6 +228,18 @@ func (p *Dispatcher) ThroughputTimeout() time.Duration {
 	return p.throughputDeadlineTime
 }
 
+func (p *Dispatcher) SystemMessageDrainInterval() int {
+	return p.systemMessageDrainInterval
+}
+
+func (p *Dispatcher) SystemMessageQuota() int {
+	return p.systemMessageQuota
+}
+
+func (p *Dispatcher) BlockingCallWarningThreshold() time.Duration {
+	return p.blockingCallThreshold
+}
+
 func (p *Dispatcher) Dispatch(receiver akka.ActorCell, invocation akka.Envelope) (err error) {
 	mbox := receiver.Mailbox()
 	if err = mbox.Enqueue(receiver.Self(), invocation); err != nil {
@@ -100,3 +263,24 @@ func (p *Dispatcher) SystemDispatch(receiver akka.ActorCell, invocation akka.Sys
 func (p *Dispatcher) executorService() ExecutorServiceDelegate {
 	return p.executorServiceDelegate
 }
+
+func (p *Dispatcher) ShutdownTimeout() time.Duration {
+	return p.shutdownTimeout
+}
+
+// forceClose closes every mailbox still attached, without waiting for the
+// ones that are stuck in a handler
+func (p *Dispatcher) forceClose() {
+	p.mailboxesLocker.Lock()
+	defer p.mailboxesLocker.Unlock()
+
+	for mailbox, actor := range p.mailboxes {
+		mailbox.BecomeClosed()
+		mailbox.CleanUp(actor.Self(), newDeadLetterQueue(actor))
+
+		p.detachedProcessed += mailbox.ProcessedMessages()
+		p.detachedDwell += mailbox.MessageDwellTime()
+	}
+
+	p.mailboxes = make(map[akka.Mailbox]akka.ActorCell)
+}
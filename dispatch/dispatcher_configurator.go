@@ -1,6 +1,8 @@
 package dispatch
 
 import (
+	"fmt"
+
 	"github.com/go-akka/akka"
 	"github.com/go-akka/configuration"
 )
@@ -36,7 +38,7 @@ func NewDispatcherConfigurator(
 		config.GetString("id"),
 		int(config.GetInt64("throughput")),
 		deadlineTime,
-		NewThreadPoolConfig(10, 10),
+		executorServiceFactoryProviderFor(config, prerequisites),
 	)
 
 	configurator.instance = instance
@@ -47,3 +49,31 @@ func NewDispatcherConfigurator(
 func (p *DispatcherConfigurator) Dispatcher() akka.MessageDispatcher {
 	return p.instance
 }
+
+// executorServiceFactoryProviderFor creates the executor named by the executor
+// of config, the class is constructed with config and may either be an
+// ExecutorService or an ExecutorServiceFactoryProvider
+func executorServiceFactoryProviderFor(config *configuration.Config, prerequisites *akka.DispatcherPrerequisites) ExecutorServiceFactoryProvider {
+	executor := config.GetString("executor", DefaultExecutor)
+	if executor == DefaultExecutor {
+		return NewThreadPoolConfig(DefaultPoolSize, DefaultTaskQueueSize)
+	}
+
+	ins, err := prerequisites.DynamicAccess.CreateInstanceByName(executor, config)
+	if err != nil {
+		panic(fmt.Sprintf("create executor %s of dispatcher %s failure: %s", executor, config.GetString("id"), err.Error()))
+	}
+
+	switch v := ins.(type) {
+	case ExecutorServiceFactoryProvider:
+		{
+			return v
+		}
+	case ExecutorService:
+		{
+			return &executorServiceFactoryProvider{executorService: v}
+		}
+	}
+
+	panic(fmt.Sprintf("%s: %s", ErrNotExecutorService, executor))
+}
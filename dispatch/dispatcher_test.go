@@ -0,0 +1,297 @@
+package dispatch
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/pkg/class_loader"
+	"github.com/go-akka/akka/pkg/dynamic_access"
+	"github.com/go-akka/configuration"
+)
+
+type blockingCell struct {
+	dispatcher akka.MessageDispatcher
+	mailbox    akka.Mailbox
+
+	started chan struct{}
+	release chan struct{}
+	invoked int32
+}
+
+func (p *blockingCell) Self() akka.ActorRef                                   { return nil }
+func (p *blockingCell) Mailbox() akka.Mailbox                                 { return p.mailbox }
+func (p *blockingCell) Dispatcher() akka.MessageDispatcher                    { return p.dispatcher }
+func (p *blockingCell) SystemInvoke(message akka.SystemMessage) (bool, error) { return true, nil }
+
+func (p *blockingCell) Invoke(envelope akka.Envelope) (bool, error) {
+	if atomic.AddInt32(&p.invoked, 1) == 1 {
+		close(p.started)
+		<-p.release
+	}
+	return true, nil
+}
+
+func TestDispatcherShutdownWaitsForRunningMailbox(t *testing.T) {
+	dispatcher := NewDispatcher(nil, "test-dispatcher", 1, 0, NewThreadPoolConfig(2, 10))
+
+	cell := &blockingCell{
+		dispatcher: dispatcher,
+		started:    make(chan struct{}),
+		release:    make(chan struct{}),
+	}
+	cell.mailbox = newMailbox(NewUnboundedMessageQueue())
+	cell.mailbox.SetActor(cell)
+
+	dispatcher.Dispatch(cell, akka.Envelope{Message: "first"})
+	dispatcher.Dispatch(cell, akka.Envelope{Message: "second"})
+
+	select {
+	case <-cell.started:
+	case <-time.After(time.Second):
+		t.Fatalf("mailbox was not run")
+	}
+
+	terminated := make(chan bool, 1)
+	go func() {
+		terminated <- dispatcher.Shutdown(3 * time.Second)
+	}()
+
+	for !dispatcher.IsShutdown() {
+		time.Sleep(time.Millisecond)
+	}
+
+	select {
+	case <-terminated:
+		t.Fatalf("shutdown should wait for the running mailbox")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(cell.release)
+
+	select {
+	case ok := <-terminated:
+		if !ok {
+			t.Fatalf("shutdown should report termination")
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("shutdown did not complete")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if n := atomic.LoadInt32(&cell.invoked); n != 1 {
+		t.Fatalf("mailbox should not be re-registered after shutdown, but %d messages were invoked", n)
+	}
+
+	if !cell.mailbox.HasMessages() {
+		t.Fatalf("pending message should stay in the mailbox")
+	}
+
+	if dispatcher.RegisterForExecution(cell.mailbox, true, false) {
+		t.Fatalf("register for execution should be rejected after shutdown")
+	}
+}
+
+// countingExecutorProvider hands out executor to every dispatcher
+type countingExecutorProvider struct {
+	executor *CountingExecutorService
+}
+
+func (p *countingExecutorProvider) CreateExecutorServiceFactory(id string) ExecutorServiceFactory {
+	return p
+}
+
+func (p *countingExecutorProvider) CreateExecutorService() ExecutorService {
+	return p.executor
+}
+
+func TestDispatcherShutdownTimeoutShutsTheExecutorDown(t *testing.T) {
+	executor := &CountingExecutorService{}
+	dispatcher := NewDispatcher(nil, "stuck-dispatcher", 1, 0, &countingExecutorProvider{executor: executor})
+
+	cell := &blockingCell{
+		dispatcher: dispatcher,
+		started:    make(chan struct{}),
+		release:    make(chan struct{}),
+	}
+	cell.mailbox = newMailbox(NewUnboundedMessageQueue())
+	cell.mailbox.SetActor(cell)
+	defer close(cell.release)
+
+	dispatcher.Dispatch(cell, akka.Envelope{Message: "stuck"})
+
+	select {
+	case <-cell.started:
+	case <-time.After(time.Second):
+		t.Fatalf("mailbox was not run")
+	}
+
+	if dispatcher.Shutdown(50 * time.Millisecond) {
+		t.Fatalf("shutdown should time out while the mailbox is stuck")
+	}
+
+	if atomic.LoadInt32(&executor.shutdown) != 1 {
+		t.Fatalf("shutdown should shut the executor down when it timed out")
+	}
+}
+
+type CountingExecutorService struct {
+	executed int32
+	shutdown int32
+}
+
+func (p *CountingExecutorService) Execute(task func()) {
+	atomic.AddInt32(&p.executed, 1)
+	go task()
+}
+
+func (p *CountingExecutorService) Shutdown() {
+	atomic.AddInt32(&p.shutdown, 1)
+}
+
+func TestDispatcherRunsMailboxesOnConfiguredExecutor(t *testing.T) {
+	loader := class_loader.NewClassicClassLoader(class_loader.Default)
+	loader.Register((*CountingExecutorService)(nil), "dispatch.counting-executor")
+
+	settings, err := akka.NewSettings("CountingExecutor", configuration.ParseString(`akka.actor.counting-dispatcher {
+	type = "dispatcher"
+	executor = "dispatch.counting-executor"
+}`))
+	if err != nil {
+		t.Fatalf("create settings failure: %s", err.Error())
+	}
+
+	dispatchers := NewDispatchers(settings, NewDefaultDispatcherPrerequisites(nil, nil, dynamic_access.NewReflectiveDynamicAccess(loader), settings, nil, nil))
+	dispatcher := dispatchers.Lookup("akka.actor.counting-dispatcher").(*Dispatcher)
+
+	executor, ok := dispatcher.executorServiceDelegate.Executor().(*CountingExecutorService)
+	if !ok {
+		t.Fatalf("dispatcher should use the configured executor, but got %T", dispatcher.executorServiceDelegate.Executor())
+	}
+
+	cell := &blockingCell{
+		dispatcher: dispatcher,
+		started:    make(chan struct{}),
+		release:    make(chan struct{}),
+	}
+	cell.mailbox = newMailbox(NewUnboundedMessageQueue())
+	cell.mailbox.SetActor(cell)
+	close(cell.release)
+
+	dispatcher.Dispatch(cell, akka.Envelope{Message: "counted"})
+
+	select {
+	case <-cell.started:
+	case <-time.After(time.Second):
+		t.Fatalf("mailbox was not run")
+	}
+
+	if n := atomic.LoadInt32(&executor.executed); n == 0 {
+		t.Fatalf("mailbox should be run by the configured executor")
+	}
+
+	if !dispatcher.Shutdown(time.Second) || atomic.LoadInt32(&executor.shutdown) != 1 {
+		t.Fatalf("dispatcher shutdown should shut the executor down")
+	}
+
+	if _, ok := dispatchers.Lookup(DefaultDispatcherId).(*Dispatcher).executorServiceDelegate.Executor().(*RoutinePoolExecutorService); !ok {
+		t.Fatalf("default dispatcher should use the default executor")
+	}
+}
+
+type interleavingCell struct {
+	name       string
+	dispatcher akka.MessageDispatcher
+	mailbox    akka.Mailbox
+
+	start       chan struct{}
+	invocations chan string
+}
+
+func (p *interleavingCell) Self() akka.ActorRef                                   { return nil }
+func (p *interleavingCell) Mailbox() akka.Mailbox                                 { return p.mailbox }
+func (p *interleavingCell) Dispatcher() akka.MessageDispatcher                    { return p.dispatcher }
+func (p *interleavingCell) SystemInvoke(message akka.SystemMessage) (bool, error) { return true, nil }
+
+func (p *interleavingCell) Invoke(envelope akka.Envelope) (bool, error) {
+	<-p.start
+	p.invocations <- p.name
+	return true, nil
+}
+
+func runInterleaved(throughput int) string {
+	dispatcher := NewDispatcher(nil, "test-dispatcher", throughput, 0, NewThreadPoolConfig(1, 10))
+	defer dispatcher.Shutdown(time.Second)
+
+	// both mailboxes are registered before the first message is handled
+	start := make(chan struct{})
+	invocations := make(chan string, 6)
+
+	var cells []*interleavingCell
+	for _, name := range []string{"a", "b"} {
+		cell := &interleavingCell{name: name, dispatcher: dispatcher, start: start, invocations: invocations}
+		cell.mailbox = newMailbox(NewUnboundedMessageQueue())
+		cell.mailbox.SetActor(cell)
+
+		for i := 0; i < 3; i++ {
+			cell.mailbox.Enqueue(nil, akka.Envelope{Message: i})
+		}
+
+		cells = append(cells, cell)
+	}
+
+	for _, cell := range cells {
+		dispatcher.RegisterForExecution(cell.mailbox, true, false)
+	}
+	close(start)
+
+	order := ""
+	for i := 0; i < 6; i++ {
+		select {
+		case name := <-invocations:
+			order += name
+		case <-time.After(time.Second):
+			return order
+		}
+	}
+
+	return order
+}
+
+func TestDispatcherThroughputOfOneYieldsAfterEveryMessage(t *testing.T) {
+	if got := runInterleaved(1); got != "ababab" {
+		t.Fatalf("mailboxes should yield after every message, but got %s", got)
+	}
+
+	if got := runInterleaved(3); got != "aaabbb" {
+		t.Fatalf("mailboxes should process up to the throughput in one run, but got %s", got)
+	}
+}
+
+func TestDispatcherThroughputIsReadFromConfig(t *testing.T) {
+	settings, err := akka.NewSettings("Throughput", configuration.ParseString(`akka.actor.io-dispatcher {
+	throughput = 2
+}`))
+	if err != nil {
+		t.Fatalf("create settings failure: %s", err.Error())
+	}
+
+	dispatchers := NewDispatchers(settings, NewDefaultDispatcherPrerequisites(nil, nil, dynamic_access.NewReflectiveDynamicAccess(class_loader.Default), settings, nil, nil))
+
+	expected := map[string]int{
+		DefaultDispatcherId:           5,
+		DefaultBlockingIODispatcherId: 1,
+		"akka.actor.io-dispatcher":    2,
+	}
+
+	for id, throughput := range expected {
+		dispatcher := dispatchers.Lookup(id)
+		if dispatcher.Throughput() != throughput || dispatcher.Metrics().Throughput != throughput {
+			t.Fatalf("throughput of %s should be %d, but got %d", id, throughput, dispatcher.Throughput())
+		}
+	}
+
+	dispatchers.Shutdown(time.Second)
+}
@@ -1,23 +1,30 @@
 package dispatch
 
 import (
+	"fmt"
 	"github.com/go-akka/akka"
 	"github.com/go-akka/akka/pkg/dynamic_access"
 	"github.com/go-akka/configuration"
 	"github.com/orcaman/concurrent-map"
 	"strings"
+	"time"
 )
 
 const (
 	DefaultDispatcherId = "akka.actor.default-dispatcher"
+
+	// DefaultBlockingIODispatcherId is meant for actors that block on I/O, its
+	// throughput of 1 makes them yield after every message
+	DefaultBlockingIODispatcherId = "akka.actor.default-blocking-io-dispatcher"
 )
 
 func NewDefaultDispatcherPrerequisites(
 	eventStream akka.EventStream,
-	scheduler akka.Scheduler,
+	scheduler func() akka.Scheduler,
 	dynamicAccess dynamic_access.DynamicAccess,
 	settings *akka.Settings,
 	mailboxes akka.Mailboxes,
+	log akka.LoggingAdapter,
 ) *akka.DispatcherPrerequisites {
 
 	return &akka.DispatcherPrerequisites{
@@ -26,6 +33,7 @@ func NewDefaultDispatcherPrerequisites(
 		DynamicAccess: dynamicAccess,
 		Settings:      settings,
 		Mailboxes:     mailboxes,
+		Log:           log,
 	}
 }
 
@@ -35,6 +43,7 @@ type Dispatchers struct {
 
 	defaultDispatcherConfig *configuration.Config
 	dispatcherConfigurators cmap.ConcurrentMap
+	resolvedAliases         cmap.ConcurrentMap
 }
 
 func NewDispatchers(settings *akka.Settings, prerequisites *akka.DispatcherPrerequisites) akka.Dispatchers {
@@ -42,6 +51,7 @@ func NewDispatchers(settings *akka.Settings, prerequisites *akka.DispatcherPrere
 		settings:                settings,
 		prerequisites:           prerequisites,
 		dispatcherConfigurators: cmap.New(),
+		resolvedAliases:         cmap.New(),
 	}
 
 	dispatcher.defaultDispatcherConfig =
@@ -51,22 +61,86 @@ func NewDispatchers(settings *akka.Settings, prerequisites *akka.DispatcherPrere
 	return dispatcher
 }
 
+// Lookup returns the dispatcher of id, or of the id it is an alias of while
+// id is not configured, it panics if neither is configured, HasDispatcher
+// tells beforehand
 func (p *Dispatchers) Lookup(id string) akka.MessageDispatcher {
-	return p.lookupConfigurator(id).Dispatcher()
+	resolved, err := p.resolve(id)
+	if err != nil {
+		panic(err.Error())
+	}
+	return p.lookupConfigurator(resolved).Dispatcher()
 }
 
 func (p *Dispatchers) HasDispatcher(id string) bool {
-	return true
+	_, err := p.resolve(id)
+	return err == nil
 }
 
 func (p *Dispatchers) RegisterConfigurator(id string, configurator akka.MessageDispatcherConfigurator) bool {
 	return false
 }
 
+func (p *Dispatchers) Metrics() (metrics []akka.DispatcherMetrics) {
+	for _, v := range p.dispatcherConfigurators.Items() {
+		if configurator, ok := v.(akka.MessageDispatcherConfigurator); ok && configurator != nil {
+			metrics = append(metrics, configurator.Dispatcher().Metrics())
+		}
+	}
+	return
+}
+
+func (p *Dispatchers) Shutdown(timeout time.Duration) (terminated bool) {
+	terminated = true
+	for _, v := range p.dispatcherConfigurators.Items() {
+		if configurator, ok := v.(akka.MessageDispatcherConfigurator); ok && configurator != nil {
+			if !configurator.Dispatcher().Shutdown(timeout) {
+				terminated = false
+			}
+		}
+	}
+	return
+}
+
+// ShutdownOnTermination registers the shutdown of dispatchers on the
+// termination of system, it has to be the first callback registered so it
+// runs last, once nothing is left to run on the dispatchers
+func ShutdownOnTermination(system akka.ActorSystem, dispatchers akka.Dispatchers) {
+	system.RegisterOnTermination(func() {
+		if !dispatchers.Shutdown(0) {
+			system.Log().Warning("dispatchers did not shut down within their shutdown-timeout, the mailboxes still running are force-closed")
+		}
+	})
+}
+
 func (p *Dispatchers) defaultGlobalDispatcher() akka.MessageDispatcher {
 	return p.Lookup(DefaultDispatcherId)
 }
 
+func (p *Dispatchers) isConfigured(id string) bool {
+	return p.dispatcherConfigurators.Has(id) || p.settings.Config().HasPath(id)
+}
+
+// resolve returns id itself if it is configured, otherwise the id of
+// akka.actor.dispatcher-aliases it stands for, warning once per alias
+func (p *Dispatchers) resolve(id string) (resolved string, err error) {
+	if p.isConfigured(id) {
+		return id, nil
+	}
+
+	target, exist := p.settings.DispatcherAliases[id]
+	if !exist || !p.isConfigured(target) {
+		err = fmt.Errorf("%s: %s", ErrDispatcherNotConfigured, id)
+		return
+	}
+
+	if p.resolvedAliases.SetIfAbsent(id, target) && p.prerequisites.Log != nil {
+		p.prerequisites.Log.Warning("dispatcher [%s] is not configured, using [%s] it is an alias of, see akka.actor.dispatcher-aliases", id, target)
+	}
+
+	return target, nil
+}
+
 func (p *Dispatchers) lookupConfigurator(id string) akka.MessageDispatcherConfigurator {
 	configurator, exist := p.dispatcherConfigurators.Get(id)
 
@@ -74,7 +148,10 @@ func (p *Dispatchers) lookupConfigurator(id string) akka.MessageDispatcherConfig
 
 		newConfigurator := p.configuratorFrom(p.config(id, p.settings.Config().GetConfig(id)))
 
-		p.dispatcherConfigurators.SetIfAbsent(id, newConfigurator)
+		if !p.dispatcherConfigurators.SetIfAbsent(id, newConfigurator) {
+			configurator, _ = p.dispatcherConfigurators.Get(id)
+			return configurator.(akka.MessageDispatcherConfigurator)
+		}
 
 		return newConfigurator
 	}
@@ -100,12 +177,26 @@ func (p *Dispatchers) configuratorFrom(cfg *configuration.Config) akka.MessageDi
 		panic("Missing dispatcher 'id' property in config: " + cfg.Root().String())
 	}
 
-	switch cfg.GetString("type") {
+	typ := cfg.GetString("type")
+
+	switch typ {
 	case "dispatcher":
 		{
 			return NewDispatcherConfigurator(cfg, p.prerequisites)
 		}
 	}
 
-	return nil
+	// any other type is the class name of a MessageDispatcherConfigurator,
+	// constructed with the dispatcher config and the prerequisites
+	ins, err := p.prerequisites.DynamicAccess.CreateInstanceByName(typ, cfg, p.prerequisites)
+	if err != nil {
+		panic(fmt.Sprintf("create dispatcher configurator %s of dispatcher %s failure: %s", typ, cfg.GetString("id"), err.Error()))
+	}
+
+	configurator, ok := ins.(akka.MessageDispatcherConfigurator)
+	if !ok {
+		panic(fmt.Sprintf("%s: %s", ErrNotDispatcherConfigurator, typ))
+	}
+
+	return configurator
 }
@@ -0,0 +1,16 @@
+package dispatch
+
+import (
+	"errors"
+)
+
+var (
+	ErrDrainOpenMailbox      = errors.New("only a suspended mailbox could be drained")
+	ErrDrainScheduledMailbox = errors.New("mailbox is still running, it could be drained once it is idle")
+	ErrDrainIncomplete       = errors.New("the target queue refused some of the drained messages")
+	ErrCleanUpOpenMailbox    = errors.New("only a closed mailbox could be cleaned up")
+	ErrNotExecutorService    = errors.New("dispatcher executor should be a dispatch.ExecutorService or dispatch.ExecutorServiceFactoryProvider")
+
+	ErrNotDispatcherConfigurator = errors.New("dispatcher type should be dispatcher or the class name of an akka.MessageDispatcherConfigurator")
+	ErrDispatcherNotConfigured   = errors.New("dispatcher is neither configured nor an alias of a configured one")
+)
@@ -1,13 +1,31 @@
 package dispatch
 
-import (
-	"github.com/go-akka/concurrent"
-)
+// ExecutorService runs the mailboxes of a dispatcher, the executor of the
+// dispatcher config selects it, so bounded queues, other goroutine pools or
+// instrumented executors can be plugged in
+type ExecutorService interface {
+	Execute(task func())
+	Shutdown()
+}
 
 type ExecutorServiceFactory interface {
-	CreateExecutorService() concurrent.ExecutorService
+	CreateExecutorService() ExecutorService
 }
 
 type ExecutorServiceFactoryProvider interface {
 	CreateExecutorServiceFactory(id string) ExecutorServiceFactory
 }
+
+// executorServiceFactoryProvider hands out a configured ExecutorService to the
+// dispatcher it is configured for
+type executorServiceFactoryProvider struct {
+	executorService ExecutorService
+}
+
+func (p *executorServiceFactoryProvider) CreateExecutorServiceFactory(id string) ExecutorServiceFactory {
+	return p
+}
+
+func (p *executorServiceFactoryProvider) CreateExecutorService() ExecutorService {
+	return p.executorService
+}
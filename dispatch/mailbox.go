@@ -1,9 +1,15 @@
 package dispatch
 
 import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/dispatch/sysmsg"
 	"github.com/go-akka/akka/pkg/lfqueue"
-	"sync/atomic"
 )
 
 const (
@@ -23,11 +29,24 @@ type Mailbox struct {
 	messageQueue akka.MessageQueue
 	dispatcher   akka.MessageDispatcher
 
-	deadLetterMailbox DeadLetterMailbox
-
 	systemMailbox *lfqueue.LockfreeQueue
 
-	status int32
+	status    int32
+	processed int64
+	dwell     int64
+
+	// lastRunProcessed is the number of user messages handled by the latest run
+	lastRunProcessed int32
+
+	// enqueueLocker is held exclusively by DrainTo, so no message is
+	// enqueued while the pending ones are moved
+	enqueueLocker sync.RWMutex
+
+	// first holds the envelopes put in front of the message queue by
+	// EnqueueFirst, numFirst lets Dequeue skip the locker while it is empty
+	first       []akka.Envelope
+	numFirst    int32
+	firstLocker sync.Mutex
 }
 
 func newMailbox(messageQueue akka.MessageQueue) akka.Mailbox {
@@ -51,49 +70,243 @@ func (p *Mailbox) MessageQueue() akka.MessageQueue {
 }
 
 func (p *Mailbox) Enqueue(receiver akka.ActorRef, envelope akka.Envelope) (err error) {
+	p.enqueueLocker.RLock()
+	defer p.enqueueLocker.RUnlock()
+
+	envelope.EnqueuedAt = time.Now()
 	return p.messageQueue.Enqueue(receiver, envelope)
 }
 
+// EnqueueFirst puts envelopes in front of the queued user messages, in their
+// order, the ones put in front before are dequeued after them
+func (p *Mailbox) EnqueueFirst(receiver akka.ActorRef, envelopes ...akka.Envelope) (err error) {
+	if len(envelopes) == 0 {
+		return
+	}
+
+	now := time.Now()
+	first := make([]akka.Envelope, 0, len(envelopes))
+	for _, envelope := range envelopes {
+		envelope.EnqueuedAt = now
+		first = append(first, envelope)
+	}
+
+	p.firstLocker.Lock()
+	p.first = append(first, p.first...)
+	atomic.StoreInt32(&p.numFirst, int32(len(p.first)))
+	p.firstLocker.Unlock()
+
+	return
+}
+
+// SystemEnqueue queues message on the system queue, every run of the mailbox
+// handles the system messages before any user message, the dispatcher
+// schedules the mailbox for it in SystemDispatch. A message enqueued to a
+// closed mailbox is cleaned up right away, so a Watch of a terminated actor
+// is still answered
 func (p *Mailbox) SystemEnqueue(receiver akka.ActorRef, message akka.SystemMessage) (err error) {
 	p.systemMailbox.Push(message)
+
+	// the mailbox may have been cleaned up before the push
+	if p.IsClosed() && p.actor != nil {
+		p.cleanUpSystemMessages(receiver, newDeadLetterQueue(p.actor))
+	}
+
 	return
 }
 
 func (p *Mailbox) Dequeue() (envelope akka.Envelope, ok bool) {
+	if envelope, ok = p.dequeue(); ok && !envelope.EnqueuedAt.IsZero() {
+		atomic.AddInt64(&p.dwell, int64(time.Since(envelope.EnqueuedAt)))
+	}
+	return
+}
+
+// dequeue takes the envelopes put in front by EnqueueFirst before the ones of
+// the message queue
+func (p *Mailbox) dequeue() (envelope akka.Envelope, ok bool) {
+	if atomic.LoadInt32(&p.numFirst) > 0 {
+		p.firstLocker.Lock()
+		if ok = len(p.first) > 0; ok {
+			envelope = p.first[0]
+			p.first[0] = akka.Envelope{}
+			p.first = p.first[1:]
+			atomic.StoreInt32(&p.numFirst, int32(len(p.first)))
+		}
+		p.firstLocker.Unlock()
+
+		if ok {
+			return
+		}
+	}
+
 	return p.messageQueue.Dequeue()
 }
 
 func (p *Mailbox) NumberOfMessages() int {
-	return p.messageQueue.NumberOfMessages()
+	return int(atomic.LoadInt32(&p.numFirst)) + p.messageQueue.NumberOfMessages()
 }
 
 func (p *Mailbox) HasMessages() bool {
-	return p.messageQueue.HasMessages()
+	return atomic.LoadInt32(&p.numFirst) > 0 || p.messageQueue.HasMessages()
 }
 
 func (p *Mailbox) HasSystemMessages() bool {
 	return !p.systemMailbox.IsEmpty()
 }
 
-func (p *Mailbox) CleanUp(owner akka.ActorRef, deadLetters akka.MessageQueue) (err error) {
+func (p *Mailbox) ProcessedMessages() int64 {
+	return atomic.LoadInt64(&p.processed)
+}
+
+func (p *Mailbox) MessageDwellTime() time.Duration {
+	return time.Duration(atomic.LoadInt64(&p.dwell))
+}
+
+func (p *Mailbox) LastRunProcessed() int {
+	return int(atomic.LoadInt32(&p.lastRunProcessed))
+}
+
+// DrainTo moves every pending user message to other in arrival order and
+// returns how many were moved. The mailbox has to be suspended and idle, so
+// no message is processed while it is drained, and no message is enqueued
+// until it is done. The messages other refuses stay in the mailbox in their
+// order, the error tells how many
+func (p *Mailbox) DrainTo(other akka.MessageQueue) (moved int, err error) {
+	if !p.isSuspended() || p.IsClosed() {
+		err = ErrDrainOpenMailbox
+		return
+	}
+
+	if p.isScheduled() {
+		err = ErrDrainScheduledMailbox
+		return
+	}
 
-	if p.messageQueue != nil {
-		p.messageQueue.CleanUp(p.actor.Self(), p.deadLetterMailbox.MessageQueue())
+	var receiver akka.ActorRef
+	if p.actor != nil {
+		receiver = p.actor.Self()
 	}
 
+	p.enqueueLocker.Lock()
+	defer p.enqueueLocker.Unlock()
+
+	var refused []akka.Envelope
+	var cause error
+
+	for {
+		envelope, ok := p.dequeue()
+		if !ok {
+			break
+		}
+
+		if enqueueErr := other.Enqueue(receiver, envelope); enqueueErr != nil {
+			refused = append(refused, envelope)
+			cause = enqueueErr
+			continue
+		}
+
+		moved++
+	}
+
+	if len(refused) == 0 {
+		return
+	}
+
+	for _, envelope := range refused {
+		p.messageQueue.Enqueue(receiver, envelope)
+	}
+
+	err = fmt.Errorf("%w: %d messages kept, %s", ErrDrainIncomplete, len(refused), cause)
 	return
 }
 
+// CleanUp hands what is left in a closed mailbox to deadLetters, the system
+// messages first and then the user messages, they are dropped if deadLetters
+// is nil. A pending Watch of owner is answered with a DeathWatchNotification,
+// so the watcher does not wait for a termination it will never see
+func (p *Mailbox) CleanUp(owner akka.ActorRef, deadLetters akka.MessageQueue) (err error) {
+	if !p.IsClosed() {
+		err = ErrCleanUpOpenMailbox
+		return
+	}
+
+	p.cleanUpSystemMessages(owner, deadLetters)
+
+	p.firstLocker.Lock()
+	first := p.first
+	p.first = nil
+	atomic.StoreInt32(&p.numFirst, 0)
+	p.firstLocker.Unlock()
+
+	if deadLetters != nil {
+		for _, envelope := range first {
+			deadLetters.Enqueue(owner, envelope)
+		}
+	}
+
+	if p.messageQueue == nil {
+		return
+	}
+
+	if deadLetters == nil {
+		for {
+			if _, ok := p.messageQueue.Dequeue(); !ok {
+				break
+			}
+		}
+	}
+
+	return p.messageQueue.CleanUp(owner, deadLetters)
+}
+
+func (p *Mailbox) cleanUpSystemMessages(owner akka.ActorRef, deadLetters akka.MessageQueue) {
+	for !p.systemMailbox.IsEmpty() {
+		msg, ok := p.systemMailbox.Pop().(akka.SystemMessage)
+		if !ok || msg == nil {
+			break
+		}
+
+		if watch, ok := msg.(*sysmsg.Watch); ok && watch.Watchee != nil && watch.Watcher != nil && watch.Watcher.CompareTo(watch.Watchee) != 0 {
+			if watcher, ok := watch.Watcher.(akka.InternalActorRef); ok {
+				watcher.SendSystemMessage(&sysmsg.DeathWatchNotification{Actor: watch.Watchee, ExistenceConfirmed: false})
+			}
+			continue
+		}
+
+		if deadLetters != nil {
+			deadLetters.Enqueue(owner, akka.Envelope{Message: msg})
+		}
+	}
+}
+
 func (p *Mailbox) Run() {
+	throughput := p.max(1, p.Dispatcher().Throughput())
+	processed := 0
+
 	defer func() {
+		if recovered := recover(); recovered != nil {
+			p.recoverPanic(recovered, debug.Stack())
+		}
+
 		p.SetAsIdle()
-		p.Dispatcher().RegisterForExecution(p, false, false)
+		if !p.Dispatcher().IsShutdown() {
+			// a run that used up its throughput most likely left messages behind
+			p.Dispatcher().RegisterForExecution(p, processed >= throughput, false)
+		}
 	}()
 
 	if !p.IsClosed() {
-		p.processAllSystemMessages()
+		p.processSystemMessages()
 		//TODO: add timeout
-		p.processMailbox(p.max(1, p.Dispatcher().Throughput()))
+		processed = p.processMailbox(throughput)
+		atomic.StoreInt32(&p.lastRunProcessed, int32(processed))
+	}
+}
+
+func (p *Mailbox) recoverPanic(recovered interface{}, stack []byte) {
+	if handler, ok := p.actor.(akka.PanicHandler); ok {
+		handler.HandlePanic(recovered, stack)
 	}
 }
 
@@ -116,10 +329,19 @@ func (p *Mailbox) CanBeScheduledForExecution(hasMessageHint bool, hasSystemMessa
 	}
 }
 
-func (p *Mailbox) processAllSystemMessages() {
-	for !p.systemMailbox.IsEmpty() {
-		msg := p.systemMailbox.Pop().(akka.SystemMessage)
-		if msg != nil {
+// processSystemMessages handles the queued system messages, at most the
+// system-message-quota of the dispatcher at once, the ones left behind make
+// the mailbox scheduled again after the user messages of this run
+func (p *Mailbox) processSystemMessages() {
+	quota := p.Dispatcher().SystemMessageQuota()
+
+	for processed := 0; quota <= 0 || processed < quota; processed++ {
+		if p.systemMailbox.IsEmpty() || p.IsClosed() {
+			return
+		}
+
+		msg, ok := p.systemMailbox.Pop().(akka.SystemMessage)
+		if ok && msg != nil {
 			p.actor.SystemInvoke(msg)
 			continue
 		}
@@ -128,16 +350,38 @@ func (p *Mailbox) processAllSystemMessages() {
 	return
 }
 
-func (p *Mailbox) processMailbox(left int) {
+// processMailbox handles at most left user messages and returns how many it
+// handled
+func (p *Mailbox) processMailbox(left int) (processed int) {
+
+	drainInterval := p.max(1, p.Dispatcher().SystemMessageDrainInterval())
+	sinceDrain := 0
+
+	blockingThreshold := p.Dispatcher().BlockingCallWarningThreshold()
+	blockingHandler, _ := p.actor.(akka.BlockingCallHandler)
 
 	for p.shouldProcessMessage() {
 		next, ok := p.Dequeue()
 		if !ok {
-			return
+			break
 		}
 
-		p.actor.Invoke(next)
-		p.processAllSystemMessages()
+		if blockingThreshold > 0 && blockingHandler != nil {
+			started := time.Now()
+			p.actor.Invoke(next)
+			if elapsed := time.Since(started); elapsed > blockingThreshold {
+				blockingHandler.HandleBlockingCall(next, elapsed)
+			}
+		} else {
+			p.actor.Invoke(next)
+		}
+		atomic.AddInt64(&p.processed, 1)
+		processed++
+
+		if sinceDrain++; sinceDrain >= drainInterval {
+			p.processSystemMessages()
+			sinceDrain = 0
+		}
 
 		if left > 1 {
 			left--
@@ -147,6 +391,11 @@ func (p *Mailbox) processMailbox(left int) {
 		break
 	}
 
+	// system messages never wait for the next run of the mailbox
+	if sinceDrain > 0 {
+		p.processSystemMessages()
+	}
+
 	return
 }
 
@@ -174,7 +423,10 @@ func (p *Mailbox) isScheduled() bool {
 	return (p.currentStatus() & MailboxStatusScheduled) != 0
 }
 
-func (p *Mailbox) resume() bool {
+// Resume removes one level of suspension, it reports whether the mailbox
+// processes user messages again. The user messages held while suspended stay
+// in the queue in arrival order and are scheduled once it is resumed
+func (p *Mailbox) Resume() bool {
 	status := p.currentStatus()
 	if status == MailboxStatusClosed {
 		p.setStatus(MailboxStatusClosed)
@@ -186,14 +438,23 @@ func (p *Mailbox) resume() bool {
 		next = status - MailboxStatusSuspendUnit
 	}
 
-	if p.updateStatus(status, next) {
-		return next < MailboxStatusSuspendUnit
+	if !p.updateStatus(status, next) {
+		return p.Resume()
+	}
+
+	resumed := next < MailboxStatusSuspendUnit
+
+	// a running mailbox is scheduled again by Run once it is done
+	if resumed && status >= MailboxStatusSuspendUnit && p.HasMessages() && p.actor != nil {
+		p.Dispatcher().RegisterForExecution(p, true, false)
 	}
 
-	return p.resume()
+	return resumed
 }
 
-func (p *Mailbox) suspend() bool {
+// Suspend adds one level of suspension, it reports whether the mailbox was
+// not suspended before
+func (p *Mailbox) Suspend() bool {
 	status := p.currentStatus()
 	if status == MailboxStatusClosed {
 		p.setStatus(MailboxStatusClosed)
@@ -204,17 +465,17 @@ func (p *Mailbox) suspend() bool {
 		return status < MailboxStatusSuspendUnit
 	}
 
-	return p.suspend()
+	return p.Suspend()
 }
 
-func (p *Mailbox) becomeClosed() bool {
+func (p *Mailbox) BecomeClosed() bool {
 	status := p.currentStatus()
 	if status == MailboxStatusClosed {
 		p.setStatus(MailboxStatusClosed)
 		return false
 	}
 
-	return p.updateStatus(status, MailboxStatusClosed) || p.becomeClosed()
+	return p.updateStatus(status, MailboxStatusClosed) || p.BecomeClosed()
 }
 
 func (p *Mailbox) SetAsIdle() bool {
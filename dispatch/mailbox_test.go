@@ -0,0 +1,463 @@
+package dispatch
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/dispatch/sysmsg"
+)
+
+type recordingCell struct {
+	dispatcher akka.MessageDispatcher
+	mailbox    akka.Mailbox
+
+	invocations []string
+}
+
+func (p *recordingCell) Self() akka.ActorRef                { return nil }
+func (p *recordingCell) Mailbox() akka.Mailbox              { return p.mailbox }
+func (p *recordingCell) Dispatcher() akka.MessageDispatcher { return p.dispatcher }
+
+func (p *recordingCell) SystemInvoke(message akka.SystemMessage) (bool, error) {
+	p.invocations = append(p.invocations, "s")
+	return true, nil
+}
+
+// every user message triggers a system message, like a child reporting back
+func (p *recordingCell) Invoke(envelope akka.Envelope) (bool, error) {
+	p.invocations = append(p.invocations, "u")
+	p.mailbox.SystemEnqueue(nil, &sysmsg.NoMessage{})
+	return true, nil
+}
+
+func runMixedLoad(drainInterval int) string {
+	dispatcher := NewDispatcher(nil, "test-dispatcher", 1, 0, NewThreadPoolConfig(1, 1)).(*Dispatcher)
+	dispatcher.systemMessageDrainInterval = drainInterval
+
+	cell := &recordingCell{dispatcher: dispatcher}
+	mailbox := newMailbox(NewUnboundedMessageQueue()).(*Mailbox)
+	mailbox.SetActor(cell)
+	cell.mailbox = mailbox
+
+	for i := 0; i < 6; i++ {
+		mailbox.Enqueue(nil, akka.Envelope{Message: i})
+	}
+
+	mailbox.processMailbox(100)
+
+	return strings.Join(cell.invocations, "")
+}
+
+func TestSystemMessagesDrainedAfterEveryUserMessage(t *testing.T) {
+	if got := runMixedLoad(1); got != "usususususus" {
+		t.Fatalf("system messages should be drained after every user message, but got %s", got)
+	}
+}
+
+func TestSystemMessagesDrainedEveryNUserMessages(t *testing.T) {
+	if got := runMixedLoad(4); got != "uuuussssuuss" {
+		t.Fatalf("system messages should be drained every 4 user messages and at the end of the run, but got %s", got)
+	}
+}
+
+type channelCell struct {
+	dispatcher akka.MessageDispatcher
+	mailbox    akka.Mailbox
+
+	received chan interface{}
+}
+
+func (p *channelCell) Self() akka.ActorRef                { return nil }
+func (p *channelCell) Mailbox() akka.Mailbox              { return p.mailbox }
+func (p *channelCell) Dispatcher() akka.MessageDispatcher { return p.dispatcher }
+
+func (p *channelCell) SystemInvoke(message akka.SystemMessage) (bool, error) {
+	return true, nil
+}
+
+func (p *channelCell) Invoke(envelope akka.Envelope) (bool, error) {
+	p.received <- envelope.Message
+	return true, nil
+}
+
+func TestResumeSchedulesMessagesHeldWhileSuspended(t *testing.T) {
+	dispatcher := NewDispatcher(nil, "test-dispatcher", 1, 0, NewThreadPoolConfig(1, 1)).(*Dispatcher)
+
+	cell := &channelCell{dispatcher: dispatcher, received: make(chan interface{}, 10)}
+	mailbox := newMailbox(NewUnboundedMessageQueue()).(*Mailbox)
+	mailbox.SetActor(cell)
+	cell.mailbox = mailbox
+
+	mailbox.Suspend()
+
+	for i := 0; i < 3; i++ {
+		mailbox.Enqueue(nil, akka.Envelope{Message: i})
+		dispatcher.RegisterForExecution(mailbox, true, false)
+	}
+
+	select {
+	case msg := <-cell.received:
+		t.Fatalf("suspended mailbox should not process %v", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if !mailbox.Resume() {
+		t.Fatalf("mailbox should not be suspended after a single resume")
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case msg := <-cell.received:
+			if msg != i {
+				t.Fatalf("message %d should be processed in arrival order, but got %v", i, msg)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("message %d was not processed after resume", i)
+		}
+	}
+}
+
+// orderingCell reports the system and user messages it handles in order
+type orderingCell struct {
+	dispatcher akka.MessageDispatcher
+	mailbox    akka.Mailbox
+
+	handled chan interface{}
+}
+
+func (p *orderingCell) Self() akka.ActorRef                { return nil }
+func (p *orderingCell) Mailbox() akka.Mailbox              { return p.mailbox }
+func (p *orderingCell) Dispatcher() akka.MessageDispatcher { return p.dispatcher }
+
+func (p *orderingCell) SystemInvoke(message akka.SystemMessage) (bool, error) {
+	p.handled <- message
+	return true, nil
+}
+
+func (p *orderingCell) Invoke(envelope akka.Envelope) (bool, error) {
+	p.handled <- envelope.Message
+	return true, nil
+}
+
+func TestSystemMessageRunsAheadOfQueuedUserMessages(t *testing.T) {
+	dispatcher := NewDispatcher(nil, "test-dispatcher", 10, 0, NewThreadPoolConfig(1, 1)).(*Dispatcher)
+
+	cell := &orderingCell{dispatcher: dispatcher, handled: make(chan interface{}, 10)}
+	mailbox := newMailbox(NewUnboundedMessageQueue()).(*Mailbox)
+	mailbox.SetActor(cell)
+	cell.mailbox = mailbox
+
+	for i := 0; i < 3; i++ {
+		mailbox.Enqueue(nil, akka.Envelope{Message: i})
+	}
+
+	system := &sysmsg.NoMessage{}
+	if err := dispatcher.SystemDispatch(cell, system); err != nil {
+		t.Fatalf("system dispatch failure: %s", err.Error())
+	}
+
+	expected := []interface{}{system, 0, 1, 2}
+	for i, want := range expected {
+		select {
+		case got := <-cell.handled:
+			if got != want {
+				t.Fatalf("message %d should be %v, but got %v", i, want, got)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("message %d was not handled", i)
+		}
+	}
+}
+
+func TestSystemEnqueueToClosedMailboxAnswersWatch(t *testing.T) {
+	user := akka.NewRootActorPath(akka.NewAddress("akka", "MailboxTest", "", 0), "").Append("user")
+
+	owner := akka.NewMinimalActorRef(user.Append("owner#1"), nil)
+	watcher := &recordingWatcher{MinimalActorRef: akka.NewMinimalActorRef(user.Append("watcher#2"), nil)}
+
+	cell := &orderingCell{handled: make(chan interface{}, 10)}
+	mailbox := newMailbox(NewUnboundedMessageQueue()).(*Mailbox)
+	mailbox.SetActor(cell)
+	cell.mailbox = mailbox
+
+	mailbox.BecomeClosed()
+	mailbox.CleanUp(owner, nil)
+
+	mailbox.SystemEnqueue(owner, &sysmsg.Watch{Watchee: owner, Watcher: watcher})
+
+	if mailbox.HasSystemMessages() {
+		t.Fatalf("system message to a closed mailbox should not be left behind")
+	}
+
+	if len(watcher.received) != 1 {
+		t.Fatalf("watcher of a closed mailbox should be notified once, but got %v", watcher.received)
+	}
+
+	if notification, ok := watcher.received[0].(*sysmsg.DeathWatchNotification); !ok || notification.Actor != owner {
+		t.Fatalf("watcher should get a death watch notification of the owner, but got %v", watcher.received[0])
+	}
+}
+
+// floodingCell answers every system message with the next one, a flood that
+// never ends on its own
+type floodingCell struct {
+	channelCell
+
+	systemInvoked int64
+}
+
+func (p *floodingCell) SystemInvoke(message akka.SystemMessage) (bool, error) {
+	atomic.AddInt64(&p.systemInvoked, 1)
+	p.mailbox.SystemEnqueue(nil, &sysmsg.NoMessage{})
+	return true, nil
+}
+
+func TestSystemMessageQuotaKeepsUserMessagesProgressing(t *testing.T) {
+	dispatcher := NewDispatcher(nil, "test-dispatcher", 1, 0, NewThreadPoolConfig(1, 1)).(*Dispatcher)
+	dispatcher.systemMessageQuota = 10
+
+	cell := &floodingCell{channelCell: channelCell{dispatcher: dispatcher, received: make(chan interface{}, 10)}}
+	mailbox := newMailbox(NewUnboundedMessageQueue()).(*Mailbox)
+	mailbox.SetActor(cell)
+	cell.mailbox = mailbox
+
+	defer mailbox.BecomeClosed()
+
+	mailbox.SystemEnqueue(nil, &sysmsg.NoMessage{})
+
+	for i := 0; i < 5; i++ {
+		mailbox.Enqueue(nil, akka.Envelope{Message: i})
+	}
+	dispatcher.RegisterForExecution(mailbox, true, true)
+
+	for i := 0; i < 5; i++ {
+		select {
+		case msg := <-cell.received:
+			if msg != i {
+				t.Fatalf("message %d should be processed in arrival order, but got %v", i, msg)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("user message %d starved by the system message flood", i)
+		}
+	}
+
+	if invoked := atomic.LoadInt64(&cell.systemInvoked); invoked < 5 {
+		t.Fatalf("system messages should keep progressing too, but only %d were processed", invoked)
+	}
+}
+
+func TestProcessMailboxReturnsProcessedCountUpToThroughput(t *testing.T) {
+	dispatcher := NewDispatcher(nil, "test-dispatcher", 1, 0, NewThreadPoolConfig(1, 1)).(*Dispatcher)
+
+	cell := &channelCell{dispatcher: dispatcher, received: make(chan interface{}, 20)}
+	mailbox := newMailbox(NewUnboundedMessageQueue()).(*Mailbox)
+	mailbox.SetActor(cell)
+	cell.mailbox = mailbox
+
+	for i := 0; i < 8; i++ {
+		mailbox.Enqueue(nil, akka.Envelope{Message: i})
+	}
+
+	if processed := mailbox.processMailbox(5); processed != 5 {
+		t.Fatalf("run should stop at the throughput limit of 5, but processed %d", processed)
+	}
+
+	if processed := mailbox.processMailbox(5); processed != 3 {
+		t.Fatalf("run should process the 3 remaining messages, but processed %d", processed)
+	}
+
+	if processed := mailbox.processMailbox(5); processed != 0 {
+		t.Fatalf("run of an empty mailbox should process nothing, but processed %d", processed)
+	}
+
+	if total := mailbox.ProcessedMessages(); total != 8 {
+		t.Fatalf("mailbox should count 8 processed messages, but counted %d", total)
+	}
+}
+
+func TestDrainSuspendedMailboxIntoFreshQueue(t *testing.T) {
+	dispatcher := NewDispatcher(nil, "test-dispatcher", 1, 0, NewThreadPoolConfig(1, 1)).(*Dispatcher)
+
+	cell := &channelCell{dispatcher: dispatcher, received: make(chan interface{}, 20)}
+	mailbox := newMailbox(NewUnboundedMessageQueue()).(*Mailbox)
+	mailbox.SetActor(cell)
+	cell.mailbox = mailbox
+
+	for i := 0; i < 10; i++ {
+		mailbox.Enqueue(nil, akka.Envelope{Message: i})
+	}
+
+	if _, err := mailbox.DrainTo(NewUnboundedMessageQueue()); err != ErrDrainOpenMailbox {
+		t.Fatalf("draining an open mailbox should be rejected, but got %v", err)
+	}
+
+	if mailbox.NumberOfMessages() != 10 {
+		t.Fatalf("rejected drain should keep all messages, but %d are left", mailbox.NumberOfMessages())
+	}
+
+	mailbox.Suspend()
+
+	target := NewUnboundedMessageQueue()
+	moved, err := mailbox.DrainTo(target)
+	if err != nil {
+		t.Fatalf("drain failure: %s", err.Error())
+	}
+
+	if moved != 10 || target.NumberOfMessages() != 10 {
+		t.Fatalf("all 10 messages should be moved, but moved %d and target holds %d", moved, target.NumberOfMessages())
+	}
+
+	if mailbox.HasMessages() {
+		t.Fatalf("drained mailbox should be empty")
+	}
+
+	for i := 0; i < 10; i++ {
+		envelope, ok := target.Dequeue()
+		if !ok || envelope.Message != i {
+			t.Fatalf("message %d should keep its position, but got %v", i, envelope.Message)
+		}
+	}
+}
+
+func TestEnqueueFirstRunsAheadOfQueuedMessages(t *testing.T) {
+	mailbox := newMailbox(NewUnboundedMessageQueue()).(*Mailbox)
+
+	mailbox.Enqueue(nil, akka.Envelope{Message: 3})
+	mailbox.EnqueueFirst(nil, akka.Envelope{Message: 2})
+	mailbox.EnqueueFirst(nil, akka.Envelope{Message: 0}, akka.Envelope{Message: 1})
+
+	if mailbox.NumberOfMessages() != 4 {
+		t.Fatalf("mailbox should count the messages put in front, but holds %d", mailbox.NumberOfMessages())
+	}
+
+	for i := 0; i < 4; i++ {
+		envelope, ok := mailbox.Dequeue()
+		if !ok || envelope.Message != i {
+			t.Fatalf("message %d should be dequeued in order, but got %v", i, envelope.Message)
+		}
+	}
+
+	if mailbox.HasMessages() {
+		t.Fatalf("mailbox should be empty")
+	}
+}
+
+// refusingQueue refuses the odd messages
+type refusingQueue struct {
+	akka.MessageQueue
+}
+
+func (p *refusingQueue) Enqueue(receiver akka.ActorRef, envelope akka.Envelope) error {
+	if envelope.Message.(int)%2 == 1 {
+		return errors.New("odd message refused")
+	}
+	return p.MessageQueue.Enqueue(receiver, envelope)
+}
+
+func TestDrainKeepsTheMessagesTheTargetRefuses(t *testing.T) {
+	dispatcher := NewDispatcher(nil, "test-dispatcher", 1, 0, NewThreadPoolConfig(1, 1)).(*Dispatcher)
+
+	cell := &channelCell{dispatcher: dispatcher, received: make(chan interface{}, 20)}
+	mailbox := newMailbox(NewUnboundedMessageQueue()).(*Mailbox)
+	mailbox.SetActor(cell)
+	cell.mailbox = mailbox
+
+	for i := 0; i < 6; i++ {
+		mailbox.Enqueue(nil, akka.Envelope{Message: i})
+	}
+
+	mailbox.Suspend()
+
+	target := &refusingQueue{MessageQueue: NewUnboundedMessageQueue()}
+	moved, err := mailbox.DrainTo(target)
+	if !errors.Is(err, ErrDrainIncomplete) {
+		t.Fatalf("drain into a refusing queue should fail with ErrDrainIncomplete, but got %v", err)
+	}
+
+	if moved != 3 || target.NumberOfMessages() != 3 {
+		t.Fatalf("3 messages should be moved, but moved %d and target holds %d", moved, target.NumberOfMessages())
+	}
+
+	for _, expected := range []int{1, 3, 5} {
+		envelope, ok := mailbox.Dequeue()
+		if !ok || envelope.Message != expected {
+			t.Fatalf("refused message %d should be kept in order, but got %v", expected, envelope.Message)
+		}
+	}
+
+	if mailbox.HasMessages() {
+		t.Fatalf("only the refused messages should be kept")
+	}
+}
+
+type recordingWatcher struct {
+	*akka.MinimalActorRef
+
+	received []akka.SystemMessage
+}
+
+func (p *recordingWatcher) SendSystemMessage(message akka.SystemMessage) error {
+	p.received = append(p.received, message)
+	return nil
+}
+
+func TestCleanUpAccountsForSystemMessages(t *testing.T) {
+	user := akka.NewRootActorPath(akka.NewAddress("akka", "MailboxTest", "", 0), "").Append("user")
+
+	owner := akka.NewMinimalActorRef(user.Append("owner#1"), nil)
+	watcher := &recordingWatcher{MinimalActorRef: akka.NewMinimalActorRef(user.Append("watcher#2"), nil)}
+
+	mailbox := newMailbox(NewUnboundedMessageQueue()).(*Mailbox)
+
+	mailbox.SystemEnqueue(owner, &sysmsg.Watch{Watchee: owner, Watcher: watcher})
+	mailbox.SystemEnqueue(owner, &sysmsg.NoMessage{})
+
+	for i := 0; i < 3; i++ {
+		mailbox.Enqueue(owner, akka.Envelope{Message: i})
+	}
+
+	deadLetters := NewUnboundedMessageQueue()
+
+	if err := mailbox.CleanUp(owner, deadLetters); err != ErrCleanUpOpenMailbox {
+		t.Fatalf("cleaning up an open mailbox should be rejected, but got %v", err)
+	}
+
+	mailbox.BecomeClosed()
+
+	if err := mailbox.CleanUp(owner, deadLetters); err != nil {
+		t.Fatalf("clean up failure: %s", err.Error())
+	}
+
+	if mailbox.HasSystemMessages() || mailbox.HasMessages() {
+		t.Fatalf("cleaned up mailbox should be empty")
+	}
+
+	if len(watcher.received) != 1 {
+		t.Fatalf("watcher should be notified once, but got %v", watcher.received)
+	}
+
+	notification, ok := watcher.received[0].(*sysmsg.DeathWatchNotification)
+	if !ok || notification.Actor != owner || notification.ExistenceConfirmed {
+		t.Fatalf("watcher should get a death watch notification of the owner, but got %v", watcher.received[0])
+	}
+
+	if deadLetters.NumberOfMessages() != 4 {
+		t.Fatalf("dead letters should hold 1 system and 3 user messages, but holds %d", deadLetters.NumberOfMessages())
+	}
+
+	envelope, _ := deadLetters.Dequeue()
+	if _, ok := envelope.Message.(*sysmsg.NoMessage); !ok {
+		t.Fatalf("system messages should be dead-lettered first, but got %v", envelope.Message)
+	}
+
+	for i := 0; i < 3; i++ {
+		envelope, ok := deadLetters.Dequeue()
+		if !ok || envelope.Message != i {
+			t.Fatalf("user message %d should keep its position, but got %v", i, envelope.Message)
+		}
+	}
+}
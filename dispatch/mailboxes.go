@@ -67,6 +67,7 @@ func (p *Mailboxes) lookupConfigurator(id string) (t akka.MailboxType, exist boo
 	}
 
 	t = v.(akka.MailboxType)
+	exist = true
 
 	return
 }
@@ -38,6 +38,14 @@ type Failed struct {
 	Child akka.ActorRef
 	Cause error
 	Uid   int
+
+	// Envelope is the message Child failed on, nil if the failure was
+	// escalated
+	Envelope *akka.Envelope
+
+	// Escalated is set when the supervisor of Child did not handle the
+	// failure of one of its own children
+	Escalated bool
 }
 
 func (p *Failed) SystemMessage() {}
@@ -57,6 +65,26 @@ type DeathWatchNotification struct {
 
 func (p *DeathWatchNotification) SystemMessage() {}
 
+type Watch struct {
+	Watchee akka.ActorRef
+	Watcher akka.ActorRef
+}
+
+func (p *Watch) SystemMessage() {}
+func (p *Watch) String() string {
+	return "<Watch>: " + p.Watcher.Path().String() + " -> " + p.Watchee.Path().String()
+}
+
+type Unwatch struct {
+	Watchee akka.ActorRef
+	Watcher akka.ActorRef
+}
+
+func (p *Unwatch) SystemMessage() {}
+func (p *Unwatch) String() string {
+	return "<Unwatch>: " + p.Watcher.Path().String() + " -> " + p.Watchee.Path().String()
+}
+
 type Stop struct{}
 
 func (p *Stop) SystemMessage() {}
@@ -68,6 +96,10 @@ type StopChild struct {
 	child akka.ActorRef
 }
 
+func NewStopChild(child akka.ActorRef) *StopChild {
+	return &StopChild{child: child}
+}
+
 func (p *StopChild) Child() akka.ActorRef {
 	return p.child
 }
@@ -82,3 +114,40 @@ func (p *Terminate) SystemMessage() {}
 func (p *Terminate) String() string {
 	return "<ActorSelectionMessage>"
 }
+
+type Suspend struct {
+	Reason string
+}
+
+func (p *Suspend) SystemMessage() {}
+func (p *Suspend) String() string {
+	if p.Reason == "" {
+		return "<Suspend>"
+	}
+	return "<Suspend>: " + p.Reason
+}
+
+type Resume struct {
+	CausedByFailure error
+}
+
+func (p *Resume) SystemMessage() {}
+func (p *Resume) String() string {
+	return "<Resume>"
+}
+
+type Recreate struct {
+	Cause error
+	// Envelope is the message the failed instance failed on, it is handed to
+	// PreRestart and PostRestart
+	Envelope *akka.Envelope
+}
+
+func (p *Recreate) SystemMessage() {}
+func (p *Recreate) String() string {
+	str := "<Recreate>"
+	if p.Cause != nil {
+		str += ": Cause=" + p.Cause.Error()
+	}
+	return str
+}
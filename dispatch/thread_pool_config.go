@@ -6,6 +6,14 @@ import (
 
 var (
 	_ ExecutorServiceFactoryProvider = (*ThreadPoolConfig)(nil)
+	_ ExecutorService                = (*RoutinePoolExecutorService)(nil)
+)
+
+const (
+	DefaultExecutor = "default-executor"
+
+	DefaultPoolSize      = 10
+	DefaultTaskQueueSize = 10
 )
 
 type ThreadPoolConfig struct {
@@ -18,8 +26,8 @@ type ThreadPoolExecutorServiceFactory struct {
 	queueSize int
 }
 
-func (p *ThreadPoolExecutorServiceFactory) CreateExecutorService() concurrent.ExecutorService {
-	return concurrent.NewFixedRoutinePool(p.nRoutine, p.queueSize)
+func (p *ThreadPoolExecutorServiceFactory) CreateExecutorService() ExecutorService {
+	return &RoutinePoolExecutorService{pool: concurrent.NewFixedRoutinePool(p.nRoutine, p.queueSize)}
 }
 
 func NewThreadPoolConfig(nRoutine, queueSize int) ExecutorServiceFactoryProvider {
@@ -35,3 +43,23 @@ func (p *ThreadPoolConfig) CreateExecutorServiceFactory(id string) ExecutorServi
 		queueSize: p.queueSize,
 	}
 }
+
+// RoutinePoolExecutorService is the default executor, it runs the tasks on a
+// fixed number of goroutines
+type RoutinePoolExecutorService struct {
+	pool concurrent.ExecutorService
+}
+
+type runnableTask func()
+
+func (p runnableTask) Run() {
+	p()
+}
+
+func (p *RoutinePoolExecutorService) Execute(task func()) {
+	p.pool.Execute(runnableTask(task))
+}
+
+func (p *RoutinePoolExecutorService) Shutdown() {
+	p.pool.Shutdown()
+}
@@ -44,6 +44,14 @@ func (p *UnboundedMessageQueue) CleanUp(owner akka.ActorRef, deadLetters akka.Me
 	return
 }
 
+// Queue is a best-effort snapshot of the queued envelopes, in order
+func (p *UnboundedMessageQueue) Queue() (envelopes []akka.Envelope) {
+	for _, v := range p.queue.Snapshot() {
+		envelopes = append(envelopes, v.(akka.Envelope))
+	}
+	return
+}
+
 func (p *UnboundedMessageQueue) NumberOfMessages() int {
 	return int(p.queue.Size())
 }
@@ -6,11 +6,19 @@ import (
 )
 
 type DispatcherPrerequisites struct {
-	EventStream   EventStream
-	Scheduler     Scheduler
+	EventStream EventStream
+
+	// Scheduler returns the scheduler of the system, which is only created
+	// on its first use
+	Scheduler func() Scheduler
+
 	DynamicAccess dynamic_access.DynamicAccess
 	Settings      *Settings
 	Mailboxes     Mailboxes
+
+	// Log gets the warnings of the dispatchers, e.g. about a dispatcher id
+	// resolved through an alias, it may be nil
+	Log LoggingAdapter
 }
 
 type MessageDispatcherConfigurator interface {
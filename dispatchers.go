@@ -1,7 +1,15 @@
 package akka
 
+import (
+	"time"
+)
+
 type Dispatchers interface {
 	Lookup(id string) MessageDispatcher
 	HasDispatcher(id string) bool
 	RegisterConfigurator(id string, configurator MessageDispatcherConfigurator) bool
+	Metrics() []DispatcherMetrics
+
+	// Shutdown shuts down every dispatcher looked up so far
+	Shutdown(timeout time.Duration) (terminated bool)
 }
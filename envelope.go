@@ -1,6 +1,60 @@
 package akka
 
+import (
+	"time"
+)
+
 type Envelope struct {
 	Message interface{}
 	Sender  ActorRef
+	Headers Headers
+
+	// EnqueuedAt is stamped by the mailbox the envelope was enqueued to, it is
+	// the zero time for an envelope that was never in one
+	EnqueuedAt time.Time
+}
+
+// Headers are key/value metadata, like trace ids, carried along with a
+// message. They are read in a receive by Context().Headers(), kept by
+// Context().Forward and the propagated ones by Context().Tell
+type Headers map[string]string
+
+func (p Headers) Get(key string) string {
+	return p[key]
+}
+
+// With returns a copy of the headers with key set to value
+func (p Headers) With(key, value string) Headers {
+	headers := make(Headers, len(p)+1)
+	for k, v := range p {
+		headers[k] = v
+	}
+	headers[key] = value
+	return headers
+}
+
+// MessageWithHeaders is unwrapped by the receiving actor ref, its headers end
+// up in the envelope of the message
+type MessageWithHeaders struct {
+	Message interface{}
+	Headers Headers
+}
+
+// WithHeaders attaches a copy of headers to message for one Tell
+func WithHeaders(message interface{}, headers Headers) *MessageWithHeaders {
+	copied := make(Headers, len(headers))
+	for k, v := range headers {
+		copied[k] = v
+	}
+
+	return &MessageWithHeaders{Message: message, Headers: copied}
+}
+
+// NewEnvelope builds the envelope of a message, a MessageWithHeaders is
+// unwrapped into the message and its headers
+func NewEnvelope(message interface{}, sender ActorRef) Envelope {
+	if withHeaders, ok := message.(*MessageWithHeaders); ok {
+		return Envelope{Message: withHeaders.Message, Sender: sender, Headers: withHeaders.Headers}
+	}
+	return Envelope{Message: message, Sender: sender}
 }
@@ -14,4 +14,6 @@ var (
 	ErrCreateActorRefProviderFailure        = errors.New("create actore ref provider failure")
 	ErrBadTypeOfScheduler                   = errors.New("basd scheduler type")
 	ErrTypeNotExistInClassLoader            = errors.New("type not in class loader")
+	ErrBadTypeOfRouterConfig                = errors.New("bad router config type")
+	ErrBadTypeOfSupervisorConfigurator      = errors.New("bad supervisor strategy configurator type")
 )
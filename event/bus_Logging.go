@@ -5,13 +5,13 @@ import (
 	"reflect"
 )
 
+// BusLogging asks the bus for the enabled levels, so they follow SetLogLevel
+// and the levels of the loggers
 type BusLogging struct {
 	bus                 akka.LoggingBus
 	logClass            reflect.Type
 	logSource           string
 	logMessageFormatter akka.LogMessageFormatter
-
-	isErrorEnabled, isWarningEnabled, isInfoEnabled, isDebugEnabled bool
 }
 
 func NewBusLogging(bus akka.LoggingBus, logSource string, logClass reflect.Type, logMessageFormatter akka.LogMessageFormatter) akka.LoggingAdapter {
@@ -21,10 +21,6 @@ func NewBusLogging(bus akka.LoggingBus, logSource string, logClass reflect.Type,
 		logSource:           logSource,
 		logClass:            logClass,
 		logMessageFormatter: logMessageFormatter,
-		isErrorEnabled:      bus.LogLevel() <= akka.ErrorLevel,
-		isWarningEnabled:    bus.LogLevel() <= akka.WarningLevel,
-		isInfoEnabled:       bus.LogLevel() <= akka.InfoLevel,
-		isDebugEnabled:      bus.LogLevel() <= akka.DebugLevel,
 	}
 
 	adapter := NewLoggingAdapter(bugLogging, logMessageFormatter)
@@ -33,19 +29,19 @@ func NewBusLogging(bus akka.LoggingBus, logSource string, logClass reflect.Type,
 }
 
 func (p *BusLogging) IsDebugEnabled() bool {
-	return p.isDebugEnabled
+	return p.bus.IsLevelEnabled(akka.DebugLevel)
 }
 
 func (p *BusLogging) IsErrorEnabled() bool {
-	return p.isErrorEnabled
+	return p.bus.IsLevelEnabled(akka.ErrorLevel)
 }
 
 func (p *BusLogging) IsInfoEnabled() bool {
-	return p.isInfoEnabled
+	return p.bus.IsLevelEnabled(akka.InfoLevel)
 }
 
 func (p *BusLogging) IsWarningEnabled() bool {
-	return p.isWarningEnabled
+	return p.bus.IsLevelEnabled(akka.WarningLevel)
 }
 
 func (p *BusLogging) NotifyError(cause error, message interface{}) {
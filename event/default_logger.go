@@ -17,8 +17,19 @@ type DefaultLogger struct {
 }
 
 func (p *DefaultLogger) Receive(context akka.ActorContext, message interface{}) (wasHandled bool, err error) {
-	if event, ok := message.(akka.LogEvent); ok {
-		p.Print(event)
+	switch event := message.(type) {
+	case *InitializeLogger:
+		{
+			context.Sender().Tell(&LoggerInitialized{}, context.Self())
+		}
+	case *FlushLogger:
+		{
+			context.Sender().Tell(&LoggerFlushed{}, context.Self())
+		}
+	case akka.LogEvent:
+		{
+			p.Print(event)
+		}
 	}
 	wasHandled = true
 	return
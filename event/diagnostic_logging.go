@@ -0,0 +1,121 @@
+package event
+
+import (
+	"reflect"
+
+	"github.com/go-akka/akka"
+)
+
+const (
+	MDCActorPath   = "akkaSource"
+	MDCMessageType = "akkaMessageType"
+)
+
+type diagnosticLogEvent interface {
+	setMDC(mdc akka.Headers)
+}
+
+type currentMessageProvider interface {
+	CurrentMessage() interface{}
+}
+
+// DiagnosticLoggingAdapter tags every event it logs with a mapped diagnostic
+// context, made of the path of the actor, the type of the message being
+// received if enabled, the headers of that message and the MDC set by the actor
+type DiagnosticLoggingAdapter struct {
+	*LoggingAdapter
+
+	logging *diagnosticBusLogging
+}
+
+type diagnosticBusLogging struct {
+	*BusLogging
+
+	context        akka.ActorContext
+	logMessageType bool
+	mdc            akka.Headers
+}
+
+func NewDiagnosticLoggingAdapter(bus akka.LoggingBus, context akka.ActorContext, logMessageType bool, logMessageFormatter akka.LogMessageFormatter) *DiagnosticLoggingAdapter {
+	logging := &diagnosticBusLogging{
+		BusLogging: &BusLogging{
+			bus:                 bus,
+			logSource:           context.Self().String(),
+			logClass:            context.Props().Type(),
+			logMessageFormatter: logMessageFormatter,
+		},
+		context:        context,
+		logMessageType: logMessageType,
+	}
+
+	return &DiagnosticLoggingAdapter{
+		LoggingAdapter: NewLoggingAdapter(logging, logMessageFormatter),
+		logging:        logging,
+	}
+}
+
+// SetMDC replaces the entries the actor added to the context, they are kept
+// until ClearMDC, so they are usually set and cleared within one receive
+func (p *DiagnosticLoggingAdapter) SetMDC(mdc akka.Headers) {
+	p.logging.mdc = mdc
+}
+
+func (p *DiagnosticLoggingAdapter) MDC() akka.Headers {
+	return p.logging.mdc
+}
+
+func (p *DiagnosticLoggingAdapter) ClearMDC() {
+	p.logging.mdc = nil
+}
+
+func (p *diagnosticBusLogging) NotifyError(cause error, message interface{}) {
+	p.publish(NewErrorEvent(cause, p.logSource, p.logClass, message))
+}
+
+func (p *diagnosticBusLogging) NotifyWarning(message interface{}) {
+	p.publish(NewWarningEvent(p.logSource, p.logClass, message))
+}
+
+func (p *diagnosticBusLogging) NotifyInfo(message interface{}) {
+	p.publish(NewInfoEvent(p.logSource, p.logClass, message))
+}
+
+func (p *diagnosticBusLogging) NotifyDebug(message interface{}) {
+	p.publish(NewDebugEvent(p.logSource, p.logClass, message))
+}
+
+func (p *diagnosticBusLogging) publish(e akka.LogEvent) {
+	if diagnostic, ok := e.(diagnosticLogEvent); ok {
+		diagnostic.setMDC(p.currentMDC())
+	}
+	p.bus.Publish(e)
+}
+
+func (p *diagnosticBusLogging) currentMDC() akka.Headers {
+	mdc := akka.Headers{}
+
+	for k, v := range p.context.Headers() {
+		mdc[k] = v
+	}
+
+	for k, v := range p.mdc {
+		mdc[k] = v
+	}
+
+	mdc[MDCActorPath] = p.context.Self().Path().String()
+
+	if p.logMessageType {
+		if provider, ok := p.context.(currentMessageProvider); ok {
+			message := provider.CurrentMessage()
+			if envelope, ok := message.(akka.Envelope); ok {
+				message = envelope.Message
+			}
+
+			if message != nil {
+				mdc[MDCMessageType] = reflect.TypeOf(message).String()
+			}
+		}
+	}
+
+	return mdc
+}
@@ -24,11 +24,15 @@ func (p *Error) LogLevel() akka.LogLevel {
 	return akka.ErrorLevel
 }
 
+func (p *Error) Cause() error {
+	return p.cause
+}
+
 func (p *Error) String() string {
 	causeStr := "Unknown"
 	if p.cause != nil {
 		causeStr = p.cause.Error()
 	}
 
-	return fmt.Sprintf("[%s ][%s][%s] [%s]\nCause: [%s]", p.LogLevel(), p.Timestamp(), p.LogSource(), p.Message(), causeStr)
+	return fmt.Sprintf("[%s ][%s][%s] [%s]\nCause: [%s]", p.LogLevel(), p.Timestamp(), p.source(), p.Message(), causeStr)
 }
@@ -0,0 +1,9 @@
+package event
+
+import (
+	"errors"
+)
+
+var (
+	ErrLoggerStartTimeout = errors.New("logger did not reply LoggerInitialized within logger-startup-timeout")
+)
@@ -1,18 +1,35 @@
 package event
 
 import (
-	"github.com/go-akka/akka"
+	"fmt"
+	"path/filepath"
 	"reflect"
+	"runtime"
+
+	"github.com/go-akka/akka"
 )
 
+type logEventSource interface {
+	setLogSource(logSource string)
+	setCaller(caller string)
+}
+
 type EventStream struct {
 	akka.LoggingBus
 
-	system akka.ActorSystem
+	system     akka.ActorSystem
+	callerInfo bool
+
+	// debug traces the subscriptions and publications, see akka.actor.debug.event-stream
+	debug bool
 }
 
 func NewEventStream(sys akka.ActorSystem, debug bool) akka.EventStream {
-	eventStream := &EventStream{system: sys}
+	eventStream := &EventStream{system: sys, debug: debug}
+
+	if sys != nil && sys.Settings() != nil {
+		eventStream.callerInfo = sys.Settings().LogCallerInfo
+	}
 
 	eventBus := NewSubchannelClassification(eventStream, eventStream)
 	eventStream.LoggingBus = NewLoggingBus(eventBus)
@@ -37,6 +54,25 @@ func (p *EventStream) PublishToSubscriber(event interface{}, subscriber interfac
 	sub.Tell(event)
 }
 
+// PublishWithLogSource publishes the event with the path of logSource as its
+// source, the file and line it is called from are only looked up with
+// akka.log-caller-info on, so hot paths don't pay for runtime.Caller
+func (p *EventStream) PublishWithLogSource(event akka.LogEvent, logSource akka.ActorRef) {
+	if source, ok := event.(logEventSource); ok {
+		if logSource != nil && logSource.Path() != nil {
+			source.setLogSource(logSource.Path().String())
+		}
+
+		if p.callerInfo {
+			if _, file, line, ok := runtime.Caller(1); ok {
+				source.setCaller(fmt.Sprintf("%s:%d", filepath.Base(file), line))
+			}
+		}
+	}
+
+	p.Publish(event)
+}
+
 func (p *EventStream) GetClassifier(event interface{}) interface{} {
 	t := reflect.TypeOf(event)
 	for t.Kind() == reflect.Ptr {
@@ -45,14 +81,62 @@ func (p *EventStream) GetClassifier(event interface{}) interface{} {
 	return t
 }
 
+// Classify matches the exact class of the event, or an interface class that
+// the event implements either by value or by pointer
 func (p *EventStream) Classify(event interface{}, classifier interface{}) bool {
-	return p.GetClassifier(event) == classifier.(reflect.Type)
+	class, ok := classifier.(reflect.Type)
+	if !ok || event == nil {
+		return false
+	}
+
+	t := p.GetClassifier(event).(reflect.Type)
+	if t == class {
+		return true
+	}
+
+	if class.Kind() != reflect.Interface {
+		return false
+	}
+
+	return t.Implements(class) || reflect.PtrTo(t).Implements(class)
+}
+
+// Publish traces the event as a Debug event in debug mode, the Debug events
+// are not traced themselves
+func (p *EventStream) Publish(event interface{}) {
+	if p.debug {
+		if _, isDebug := event.(*Debug); !isDebug {
+			p.trace(fmt.Sprintf("publishing %T", event))
+		}
+	}
+
+	p.LoggingBus.Publish(event)
 }
 
 func (p *EventStream) Subscribe(subscriber akka.ActorRef, channel interface{}) bool {
-	return p.LoggingBus.TSubscribe(subscriber, channel)
+	subscribed := p.LoggingBus.TSubscribe(subscriber, channel)
+
+	if p.debug {
+		p.trace(fmt.Sprintf("subscribing %s to channel %v", subscriber, channel))
+	}
+
+	return subscribed
 }
 
 func (p *EventStream) Unsubscribe(subscriber akka.ActorRef, channels ...interface{}) bool {
-	return p.LoggingBus.TUnsubscribe(subscriber, channels...)
+	unsubscribed := p.LoggingBus.TUnsubscribe(subscriber, channels...)
+
+	if p.debug {
+		if len(channels) == 0 {
+			p.trace(fmt.Sprintf("unsubscribing %s from all channels", subscriber))
+		} else {
+			p.trace(fmt.Sprintf("unsubscribing %s from channels %v", subscriber, channels))
+		}
+	}
+
+	return unsubscribed
+}
+
+func (p *EventStream) trace(message string) {
+	p.LoggingBus.Publish(NewDebugEvent(simpleName(p), p, message))
 }
@@ -2,7 +2,11 @@ package event
 
 import (
 	"reflect"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/go-akka/akka"
 )
 
 type LogEventBase struct {
@@ -11,9 +15,15 @@ type LogEventBase struct {
 	logClass   reflect.Type
 	message    interface{}
 	stacktrace string
+	caller     string
+	mdc        akka.Headers
 }
 
 func newLogEventBase(logSource string, logClass, message interface{}) *LogEventBase {
+	if source, ok := logClass.(akka.LogSource); ok {
+		logSource = source.LogSource()
+	}
+
 	return &LogEventBase{
 		timestamp: time.Now(),
 		logSource: logSource,
@@ -24,6 +34,10 @@ func newLogEventBase(logSource string, logClass, message interface{}) *LogEventB
 	//TODO add stacktrace
 }
 
+// NoSerializationVerificationNeeded skips the check of log events, they are
+// only delivered to the loggers of the system publishing them
+func (p *LogEventBase) NoSerializationVerificationNeeded() {}
+
 func (p *LogEventBase) Timestamp() time.Time {
 	return p.timestamp
 }
@@ -43,3 +57,51 @@ func (p *LogEventBase) Stacktrace() string {
 func (p *LogEventBase) Message() interface{} {
 	return p.message
 }
+
+// Caller is the file:line the event was published from, it is only recorded
+// by PublishWithLogSource with akka.log-caller-info on
+func (p *LogEventBase) Caller() string {
+	return p.caller
+}
+
+// MDC is the mapped diagnostic context of the event, it is only set by a
+// DiagnosticLoggingAdapter
+func (p *LogEventBase) MDC() akka.Headers {
+	return p.mdc
+}
+
+func (p *LogEventBase) setMDC(mdc akka.Headers) {
+	p.mdc = mdc
+}
+
+func (p *LogEventBase) setLogSource(logSource string) {
+	p.logSource = logSource
+}
+
+func (p *LogEventBase) setCaller(caller string) {
+	p.caller = caller
+}
+
+func (p *LogEventBase) source() string {
+	source := p.logSource
+	if len(p.caller) > 0 {
+		source += " (" + p.caller + ")"
+	}
+
+	if len(p.mdc) > 0 {
+		keys := make([]string, 0, len(p.mdc))
+		for k := range p.mdc {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		tags := make([]string, len(keys))
+		for i, k := range keys {
+			tags[i] = k + "=" + p.mdc[k]
+		}
+
+		source += " {" + strings.Join(tags, ", ") + "}"
+	}
+
+	return source
+}
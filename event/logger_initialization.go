@@ -0,0 +1,68 @@
+package event
+
+import (
+	"sync"
+
+	"github.com/go-akka/akka"
+)
+
+// InitializeLogger is the first message a logger receives, the logger must
+// reply with LoggerInitialized to the sender once it is ready to print events
+type InitializeLogger struct {
+	Bus akka.LoggingBus
+}
+
+type LoggerInitialized struct{}
+
+// FlushLogger is sent to a logger when the system terminates, the logger must
+// reply with LoggerFlushed to the sender once the events it buffered are written
+type FlushLogger struct{}
+
+type LoggerFlushed struct{}
+
+// the logger handshake never leaves the system, it is not checked by
+// akka.actor.serialize-messages
+func (p *InitializeLogger) NoSerializationVerificationNeeded()  {}
+func (p *LoggerInitialized) NoSerializationVerificationNeeded() {}
+func (p *FlushLogger) NoSerializationVerificationNeeded()       {}
+func (p *LoggerFlushed) NoSerializationVerificationNeeded()     {}
+
+// loggerAckReceiver is the sender of the messages the LoggingBus waits on,
+// ready is closed when the expected reply arrives
+type loggerAckReceiver struct {
+	*akka.MinimalActorRef
+
+	isAck func(message interface{}) bool
+	ready chan struct{}
+	once  sync.Once
+}
+
+func newLoggerReadyReceiver(loggerName string) *loggerAckReceiver {
+	return newLoggerAckReceiver(loggerName+"-ready", func(message interface{}) bool {
+		_, ok := message.(*LoggerInitialized)
+		return ok
+	})
+}
+
+func newLoggerFlushedReceiver(loggerName string) *loggerAckReceiver {
+	return newLoggerAckReceiver(loggerName+"-flushed", func(message interface{}) bool {
+		_, ok := message.(*LoggerFlushed)
+		return ok
+	})
+}
+
+func newLoggerAckReceiver(name string, isAck func(message interface{}) bool) *loggerAckReceiver {
+	path := akka.NewRootActorPath(akka.NewAddress("akka", "all-systems", "", 0), "/"+name)
+	return &loggerAckReceiver{
+		MinimalActorRef: akka.NewMinimalActorRef(path, nil),
+		isAck:           isAck,
+		ready:           make(chan struct{}),
+	}
+}
+
+func (p *loggerAckReceiver) Tell(message interface{}, sender ...akka.ActorRef) (err error) {
+	if p.isAck(message) {
+		p.once.Do(func() { close(p.ready) })
+	}
+	return
+}
@@ -52,13 +52,26 @@ func (p *logging) GetLogger(context akka.ActorContext, logMessageFormatter ...ak
 	return NewBusLogging(context.System().EventStream(), logSource, logClass, formatter)
 }
 
+// GetDiagnosticLogger is like GetLogger, but every event is tagged with the
+// path of the actor and, with logMessageType, the type of the current message
+func (p *logging) GetDiagnosticLogger(context akka.ActorContext, logMessageType bool, logMessageFormatter ...akka.LogMessageFormatter) *DiagnosticLoggingAdapter {
+	var formatter akka.LogMessageFormatter
+	if len(logMessageFormatter) == 0 {
+		formatter = &DefaultLogMessageFormatter{}
+	} else {
+		formatter = logMessageFormatter[0]
+	}
+
+	return NewDiagnosticLoggingAdapter(context.System().EventStream(), context, logMessageType, formatter)
+}
+
 func (p *logging) GetLoggerWithActorSystem(system akka.ActorSystem, logSource interface{}, logMessageFormatter ...akka.LogMessageFormatter) akka.LoggingAdapter {
 	logSourceStr := ""
 
 	if str, ok := logSource.(string); ok {
 		logSourceStr = str
 	} else {
-		logSourceStr = reflect.TypeOf(logSource).String()
+		logSourceStr = simpleName(logSource)
 	}
 
 	var formatter akka.LogMessageFormatter
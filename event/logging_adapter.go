@@ -77,6 +77,35 @@ func (p *LoggingAdapter) Warning(format string, args ...interface{}) {
 	}
 }
 
+// Debugf formats the message right away, but only when debug is enabled
+func (p *LoggingAdapter) Debugf(format string, args ...interface{}) {
+	if !p.IsDebugEnabled() {
+		return
+	}
+	p.NotifyDebug(p.logMessageFormatter.Format(format, args...))
+}
+
+func (p *LoggingAdapter) Errorf(cause error, format string, args ...interface{}) {
+	if !p.IsErrorEnabled() {
+		return
+	}
+	p.NotifyError(cause, p.logMessageFormatter.Format(format, args...))
+}
+
+func (p *LoggingAdapter) Infof(format string, args ...interface{}) {
+	if !p.IsInfoEnabled() {
+		return
+	}
+	p.NotifyInfo(p.logMessageFormatter.Format(format, args...))
+}
+
+func (p *LoggingAdapter) Warningf(format string, args ...interface{}) {
+	if !p.IsWarningEnabled() {
+		return
+	}
+	p.NotifyWarning(p.logMessageFormatter.Format(format, args...))
+}
+
 func (p *LoggingAdapter) Log(level akka.LogLevel, format string, args ...interface{}) {
 	if len(args) == 0 {
 		p.NotifyLog(level, format)
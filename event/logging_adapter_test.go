@@ -0,0 +1,130 @@
+package event
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-akka/akka"
+)
+
+type countingEventBus struct {
+	published int64
+}
+
+func (p *countingEventBus) TSubscribe(subscriber interface{}, classifier interface{}) bool {
+	return true
+}
+
+func (p *countingEventBus) TUnsubscribe(subscriber interface{}, classifiers ...interface{}) bool {
+	return true
+}
+
+func (p *countingEventBus) Publish(event interface{}) {
+	atomic.AddInt64(&p.published, 1)
+}
+
+type countingFormatter struct {
+	formatted int64
+}
+
+func (p *countingFormatter) Format(format string, args ...interface{}) string {
+	atomic.AddInt64(&p.formatted, 1)
+	return fmt.Sprintf(format, args...)
+}
+
+func newCountingLogger(level akka.LogLevel) (akka.LoggingAdapter, *countingEventBus, *countingFormatter) {
+	bus := &countingEventBus{}
+	loggingBus := NewLoggingBus(bus)
+	loggingBus.SetLogLevel(level)
+
+	formatter := &countingFormatter{}
+
+	return NewBusLogging(loggingBus, "test", reflect.TypeOf(bus), formatter), bus, formatter
+}
+
+func TestSuppressedLevelIsNotFormatted(t *testing.T) {
+	log, bus, formatter := newCountingLogger(akka.InfoLevel)
+
+	log.Debugf("value %d", 42)
+
+	if formatter.formatted != 0 || bus.published != 0 {
+		t.Fatalf("suppressed debug should neither format nor publish, but formatted %d and published %d", formatter.formatted, bus.published)
+	}
+
+	log.Infof("value %d", 42)
+
+	if formatter.formatted != 1 || bus.published != 1 {
+		t.Fatalf("enabled info should format and publish once, but formatted %d and published %d", formatter.formatted, bus.published)
+	}
+}
+
+func TestEnabledLevelsFollowBusLogLevel(t *testing.T) {
+	bus := NewLoggingBus(&countingEventBus{})
+	bus.SetLogLevel(akka.WarningLevel)
+
+	log := NewBusLogging(bus, "test", nil, &DefaultLogMessageFormatter{})
+
+	if log.IsDebugEnabled() || log.IsInfoEnabled() || !log.IsWarningEnabled() {
+		t.Fatalf("only warning and above should be enabled at warning level")
+	}
+
+	bus.SetLogLevel(akka.DebugLevel)
+
+	if !log.IsDebugEnabled() {
+		t.Fatalf("debug should be enabled after the bus level changed to debug")
+	}
+}
+
+func BenchmarkSuppressedDebugf(b *testing.B) {
+	log, _, formatter := newCountingLogger(akka.InfoLevel)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		log.Debugf("value %d of %s", i, "benchmark")
+	}
+
+	if formatter.formatted != 0 {
+		b.Fatalf("suppressed debug calls should not format, but formatted %d times", formatter.formatted)
+	}
+}
+
+func BenchmarkEnabledDebugf(b *testing.B) {
+	log, _, _ := newCountingLogger(akka.DebugLevel)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		log.Debugf("value %d of %s", i, "benchmark")
+	}
+}
+
+type orderBook struct {
+	market string
+}
+
+func (p *orderBook) LogSource() string {
+	return "OrderBook(" + p.market + ")"
+}
+
+func TestLogSourceNamesTheEventSource(t *testing.T) {
+	book := &orderBook{market: "EUR"}
+
+	events := []akka.LogEvent{
+		NewDebugEvent(simpleName(book), book, "opened"),
+		NewInfoEvent("github.com/example/market.orderBook", book, "opened"),
+		NewWarningEvent(simpleName(book), book, "opened"),
+		NewErrorEvent(nil, simpleName(book), book, "opened"),
+	}
+
+	for _, e := range events {
+		if line := fmt.Sprint(e); !strings.Contains(line, "[OrderBook(EUR)]") {
+			t.Fatalf("log line should name the source OrderBook(EUR), but got %s", line)
+		}
+	}
+
+	if line := fmt.Sprint(NewInfoEvent("custom", &countingFormatter{}, "opened")); !strings.Contains(line, "[custom]") {
+		t.Fatalf("types without LogSource should keep the given source, but got %s", line)
+	}
+}
@@ -4,8 +4,9 @@ import (
 	"fmt"
 	"github.com/go-akka/akka"
 	"github.com/go-akka/akka/actor/props"
-	"github.com/go-akka/akka/pkg/class_loader"
 	"reflect"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -40,31 +41,156 @@ type LoggingBus struct {
 	akka.EventBus
 
 	loggers  []akka.ActorRef
-	logLevel akka.LogLevel
+	logLevel int32
+
+	// enabledLevel is the lowest of logLevel and the levels of loggerLevels,
+	// the adapters build no event below it
+	enabledLevel int32
+
+	// loggerLevels holds the loggers configured with their own level, they
+	// keep it when the level of the bus changes
+	loggerLevels *akka.ActorRefMap
+
+	// levelSubscribers holds the subscribers of the class of each level and
+	// subscribed their count, read by Publish without the locker
+	levelSubscribers [akka.ErrorLevel + 1]map[interface{}]bool
+	subscribed       [akka.ErrorLevel + 1]int32
+	subscribeLocker  sync.Mutex
+
+	// fallback is told the Error events nothing is subscribed to until the
+	// default loggers started
+	fallback              akka.ActorRef
+	defaultLoggersStarted int32
 }
 
 func NewLoggingBus(classification akka.EventBus) *LoggingBus {
-	return &LoggingBus{
-		EventBus: classification,
+	bus := &LoggingBus{
+		EventBus:     classification,
+		loggerLevels: akka.NewActorRefMap(),
+		fallback:     StandardOutLoggerInstance,
 	}
+
+	for i := range bus.levelSubscribers {
+		bus.levelSubscribers[i] = make(map[interface{}]bool)
+	}
+
+	return bus
+}
+
+// Publish short-circuits the log events below the level of the bus that
+// nothing is subscribed to. An Error nothing is subscribed to still goes to
+// the standard out logger until the default loggers started, so the failures
+// of the start of a system are not lost
+func (p *LoggingBus) Publish(event interface{}) {
+	logEvent, ok := event.(akka.LogEvent)
+	if !ok {
+		p.EventBus.Publish(event)
+		return
+	}
+
+	level := logEvent.LogLevel()
+	if level < akka.DebugLevel || level > akka.ErrorLevel {
+		p.EventBus.Publish(event)
+		return
+	}
+
+	if atomic.LoadInt32(&p.subscribed[level]) == 0 {
+		if level == akka.ErrorLevel && atomic.LoadInt32(&p.defaultLoggersStarted) == 0 {
+			p.fallback.Tell(event)
+		}
+
+		if level < p.LogLevel() {
+			return
+		}
+	}
+
+	p.EventBus.Publish(event)
+}
+
+func (p *LoggingBus) TSubscribe(subscriber interface{}, classifier interface{}) bool {
+	subscribed := p.EventBus.TSubscribe(subscriber, classifier)
+
+	p.subscribeLocker.Lock()
+	defer p.subscribeLocker.Unlock()
+
+	for _, level := range levelsOfClass(classifier) {
+		p.levelSubscribers[level][subscriber] = true
+		atomic.StoreInt32(&p.subscribed[level], int32(len(p.levelSubscribers[level])))
+	}
+
+	return subscribed
+}
+
+// TUnsubscribe without classifiers unsubscribes from every level
+func (p *LoggingBus) TUnsubscribe(subscriber interface{}, classifiers ...interface{}) bool {
+	unsubscribed := p.EventBus.TUnsubscribe(subscriber, classifiers...)
+
+	p.subscribeLocker.Lock()
+	defer p.subscribeLocker.Unlock()
+
+	var levels []akka.LogLevel
+	if len(classifiers) == 0 {
+		levels = akka.AllLogLevels()
+	}
+
+	for _, classifier := range classifiers {
+		levels = append(levels, levelsOfClass(classifier)...)
+	}
+
+	for _, level := range levels {
+		delete(p.levelSubscribers[level], subscriber)
+		atomic.StoreInt32(&p.subscribed[level], int32(len(p.levelSubscribers[level])))
+	}
+
+	return unsubscribed
 }
 
 func (p *LoggingBus) SetLogLevel(logLevel akka.LogLevel) {
-	p.logLevel = logLevel
+	atomic.StoreInt32(&p.logLevel, int32(logLevel))
 
 	for _, logger := range p.loggers {
-		p.subscribeLogLevelAndAbove(logLevel, logger)
-
-		for _, level := range akka.AllLogLevels() {
-			if level < logLevel {
-				p.TUnsubscribe(logger, (*akka.LogLevel)(nil))
-			}
+		if p.loggerLevels.Contains(logger) {
+			continue
 		}
+		p.subscribeLogLevelAndAbove(logLevel, logger)
 	}
+
+	p.updateEnabledLevel()
 }
 
 func (p *LoggingBus) LogLevel() akka.LogLevel {
-	return p.logLevel
+	return akka.LogLevel(atomic.LoadInt32(&p.logLevel))
+}
+
+// IsLevelEnabled tells the adapters if an event of level would reach a
+// logger, so they build no event Publish would drop: one below the enabled
+// level needs a subscriber of its level, an Error goes to the fallback until
+// the default loggers started
+func (p *LoggingBus) IsLevelEnabled(level akka.LogLevel) bool {
+	if level >= akka.LogLevel(atomic.LoadInt32(&p.enabledLevel)) {
+		return true
+	}
+
+	if level < akka.DebugLevel || level > akka.ErrorLevel {
+		return false
+	}
+
+	if level == akka.ErrorLevel && atomic.LoadInt32(&p.defaultLoggersStarted) == 0 {
+		return true
+	}
+
+	return atomic.LoadInt32(&p.subscribed[level]) > 0
+}
+
+func (p *LoggingBus) updateEnabledLevel() {
+	enabled := p.LogLevel()
+	p.loggerLevels.Range(func(_ akka.ActorRef, level interface{}) bool {
+		if level.(akka.LogLevel) < enabled {
+			enabled = level.(akka.LogLevel)
+		}
+		return true
+	})
+	atomic.StoreInt32(&p.enabledLevel, int32(enabled))
 }
 
 func (p *LoggingBus) StartStdoutLogger(config *akka.Settings) {
@@ -77,24 +203,39 @@ func (p *LoggingBus) StartDefaultLoggers(system akka.ExtendedActorSystem) (err e
 	logName := simpleName(p) + "(" + system.Name() + ")"
 	logLevel := akka.LogLevelFor(system.Settings().LogLevel)
 	loggerTypes := system.Settings().Loggers
+	loggerLevels := system.Settings().LoggerLevels
 	timeout := system.Settings().LoggerStartTimeout
 	shouldRemoveStandardOutLogger := true
 
 	for _, strLoggerType := range loggerTypes {
-		loggerType, exist := class_loader.Default.ClassNameOf(strLoggerType)
+		loggerType, exist := system.ClassLoader().ClassNameOf(strLoggerType)
 		if !exist {
 			panic("Logger specified in config cannot be found: " + strLoggerType)
 		}
 
+		level, hasLevel := loggerLevels[strLoggerType]
+
 		if loggerType == StandardOutLoggerType {
 			shouldRemoveStandardOutLogger = false
+			if hasLevel {
+				p.subscribeLogLevelAndAbove(akka.LogLevelFor(level), StandardOutLoggerInstance)
+			}
 			continue
 		}
 
-		err = p.addLogger(system, loggerType, logLevel, logName, timeout)
-		if err != nil {
+		loggerLevel := logLevel
+		if hasLevel {
+			loggerLevel = akka.LogLevelFor(level)
+		}
+
+		var logger akka.ActorRef
+		if logger, err = p.addLogger(system, loggerType, loggerLevel, logName, timeout); err != nil {
 			return
 		}
+
+		if hasLevel {
+			p.loggerLevels.Put(logger, loggerLevel)
+		}
 	}
 
 	if system.Settings().DebugUnhandledMessage {
@@ -119,50 +260,138 @@ func (p *LoggingBus) StartDefaultLoggers(system akka.ExtendedActorSystem) (err e
 
 	p.Publish(NewDebugEvent(logName, p, "Default Loggers started"))
 
+	atomic.StoreInt32(&p.logLevel, int32(logLevel))
+	p.updateEnabledLevel()
+	atomic.StoreInt32(&p.defaultLoggersStarted, 1)
+
 	return
 }
 
-func (p *LoggingBus) addLogger(system akka.ExtendedActorSystem, loggerType reflect.Type, logLevel akka.LogLevel, loggingBusName string, timeout time.Duration) error {
+// StopDefaultLoggers unsubscribes and stops the loggers started by
+// StartDefaultLoggers, the standard out logger takes over again. Every logger
+// is asked to flush the events it buffered and is stopped once it replied, or
+// after akka.logger-flush-timeout
+func (p *LoggingBus) StopDefaultLoggers(system akka.ExtendedActorSystem) {
+	logLevel := p.LogLevel()
+
+	atomic.StoreInt32(&p.defaultLoggersStarted, 0)
+	p.subscribeLogLevelAndAbove(logLevel, StandardOutLoggerInstance)
+
+	loggers := p.loggers
+	p.loggers = nil
+	p.loggerLevels = akka.NewActorRefMap()
+	p.updateEnabledLevel()
+
+	for _, logger := range loggers {
+		p.TUnsubscribe(logger)
+		p.flushLogger(logger, system.Settings().LoggerFlushTimeout)
+
+		if internalRef, ok := logger.(akka.InternalActorRef); ok {
+			internalRef.Stop()
+		}
+	}
+
+	p.Publish(NewDebugEvent(simpleName(p), p, "all default loggers stopped"))
+}
+
+func (p *LoggingBus) addLogger(system akka.ExtendedActorSystem, loggerType reflect.Type, logLevel akka.LogLevel, loggingBusName string, timeout time.Duration) (loggerActorRef akka.ActorRef, err error) {
 	loggerName := p.createLoggerName(loggerType)
 	props, err := props.Create(loggerType)
-	loggerProps := props.WithDispatcher(system.Settings().LoggersDispatcher)
-
 	if err != nil {
-		return err
+		return
 	}
 
-	var loggerActorRef akka.ActorRef
+	loggerProps := props.WithDispatcher(system.Settings().LoggersDispatcher)
+
 	loggerActorRef, err = system.SystemActorOf(loggerProps, loggerName)
 	if err != nil {
-		return err
+		return
 	}
 
-	// TODO: inital timeout
+	// InitializeLogger is enqueued before the logger is subscribed, so events
+	// published while it starts are buffered in its mailbox behind it
+	readyReceiver := newLoggerReadyReceiver(loggerName)
+	loggerActorRef.Tell(&InitializeLogger{Bus: p}, readyReceiver)
 
 	p.loggers = append(p.loggers, loggerActorRef)
 	p.subscribeLogLevelAndAbove(logLevel, loggerActorRef)
+
+	if timeout > 0 {
+		select {
+		case <-readyReceiver.ready:
+		case <-time.After(timeout):
+			{
+				p.Publish(NewErrorEvent(ErrLoggerStartTimeout, loggingBusName, p, fmt.Sprintf("Logger %s [%s] did not respond within %s to InitializeLogger", loggerName, simpleName(loggerType), timeout)))
+				err = ErrLoggerStartTimeout
+				return
+			}
+		}
+	}
+
 	p.Publish(NewDebugEvent(loggingBusName, p, fmt.Sprintf("Logger %s [%s] started", loggerName, simpleName(loggerType))))
 
-	return nil
+	return
+}
+
+func (p *LoggingBus) flushLogger(logger akka.ActorRef, timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	flushedReceiver := newLoggerFlushedReceiver(logger.Path().Name())
+	logger.Tell(&FlushLogger{}, flushedReceiver)
 
+	select {
+	case <-flushedReceiver.ready:
+	case <-time.After(timeout):
+		{
+			p.Publish(NewWarningEvent(simpleName(p), p, fmt.Sprintf("Logger %s did not respond within %s to FlushLogger", logger.Path().Name(), timeout)))
+		}
+	}
 }
 
 func (p *LoggingBus) setUpStdoutLogger(config *akka.Settings) {
 	logLevel := akka.LogLevelFor(config.StdoutLogLevel)
 	p.subscribeLogLevelAndAbove(logLevel, StandardOutLoggerInstance)
 
+	atomic.StoreInt32(&p.logLevel, int32(logLevel))
+	p.updateEnabledLevel()
 }
 
+// subscribeLogLevelAndAbove also unsubscribes the levels below logLevel, so it
+// can move a subscribed logger to another level
 func (p *LoggingBus) subscribeLogLevelAndAbove(logLevel akka.LogLevel, logger akka.ActorRef) {
 	for _, level := range akka.AllLogLevels() {
 		if level >= logLevel {
 			p.TSubscribe(logger, LogClassFor(level))
+		} else {
+			p.TUnsubscribe(logger, LogClassFor(level))
 		}
 	}
 }
 
 func (p *LoggingBus) createLoggerName(actor interface{}) string {
 	id := atomic.AddInt64(&_loggerId, 1)
-	name := fmt.Sprintf("log%d-%s", id, simpleName(actor))
+	typeName := simpleName(actor)
+	typeName = typeName[strings.LastIndex(typeName, ".")+1:]
+	name := fmt.Sprintf("log%d-%s", id, typeName)
 	return name
 }
+
+// levelsOfClass are the levels whose log class is classifier, or implements
+// it when it is an interface
+func levelsOfClass(classifier interface{}) (levels []akka.LogLevel) {
+	class, ok := classifier.(reflect.Type)
+	if !ok {
+		return
+	}
+
+	for _, level := range akka.AllLogLevels() {
+		logClass := LogClassFor(level)
+		if logClass == class || (class.Kind() == reflect.Interface && (logClass.Implements(class) || reflect.PtrTo(logClass).Implements(class))) {
+			levels = append(levels, level)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,96 @@
+package event
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-akka/akka"
+)
+
+type recordingLogger struct {
+	*akka.MinimalActorRef
+
+	received []interface{}
+}
+
+func newRecordingLogger() *recordingLogger {
+	return &recordingLogger{
+		MinimalActorRef: akka.NewMinimalActorRef(akka.NewRootActorPath(akka.NewAddress("akka", "test", "", 0), "/recording"), nil),
+	}
+}
+
+func (p *recordingLogger) Tell(message interface{}, sender ...akka.ActorRef) (err error) {
+	p.received = append(p.received, message)
+	return
+}
+
+func TestErrorReachesTheFallbackBeforeTheDefaultLoggersStart(t *testing.T) {
+	bus := NewLoggingBus(&countingEventBus{})
+	fallback := newRecordingLogger()
+	bus.fallback = fallback
+
+	failure := NewErrorEvent(nil, "test", nil, "failed to start")
+	bus.Publish(failure)
+
+	if len(fallback.received) != 1 || fallback.received[0] != failure {
+		t.Fatalf("error without subscriber should reach the fallback, but got %v", fallback.received)
+	}
+
+	logger := newRecordingLogger()
+	bus.TSubscribe(logger, LogClassFor(akka.ErrorLevel))
+	bus.Publish(NewErrorEvent(nil, "test", nil, "subscribed"))
+
+	if len(fallback.received) != 1 {
+		t.Fatalf("error with a subscriber should not reach the fallback, but got %v", fallback.received)
+	}
+
+	bus.TUnsubscribe(logger)
+	atomic.StoreInt32(&bus.defaultLoggersStarted, 1)
+	bus.Publish(NewErrorEvent(nil, "test", nil, "started"))
+
+	if len(fallback.received) != 1 {
+		t.Fatalf("error should not reach the fallback once the default loggers started, but got %v", fallback.received)
+	}
+}
+
+func TestEventsBelowTheLevelAreDroppedWithoutSubscribers(t *testing.T) {
+	counting := &countingEventBus{}
+	bus := NewLoggingBus(counting)
+	bus.SetLogLevel(akka.WarningLevel)
+
+	log := NewBusLogging(bus, "test", reflect.TypeOf(bus), &DefaultLogMessageFormatter{})
+	if log.IsDebugEnabled() || !log.IsWarningEnabled() {
+		t.Fatalf("adapter should build no debug event below the level of the bus")
+	}
+
+	bus.Publish(NewDebugEvent("test", nil, "dropped"))
+	bus.Publish(NewWarningEvent("test", nil, "published"))
+
+	if counting.published != 1 {
+		t.Fatalf("only the warning should be published, but published %d", counting.published)
+	}
+
+	collector := newRecordingLogger()
+	bus.TSubscribe(collector, reflect.TypeOf((*akka.LogEvent)(nil)).Elem())
+	bus.Publish(NewDebugEvent("test", nil, "subscribed"))
+
+	if counting.published != 2 {
+		t.Fatalf("debug with a subscriber should be published below the level, but published %d", counting.published)
+	}
+
+	if !log.IsDebugEnabled() {
+		t.Fatalf("adapter should build debug events once they have a subscriber")
+	}
+
+	bus.TUnsubscribe(collector, LogClassFor(akka.DebugLevel))
+	bus.Publish(NewDebugEvent("test", nil, "unsubscribed"))
+
+	if counting.published != 2 {
+		t.Fatalf("debug should be dropped again once unsubscribed, but published %d", counting.published)
+	}
+
+	if log.IsDebugEnabled() {
+		t.Fatalf("adapter should build no debug event once unsubscribed")
+	}
+}
@@ -27,6 +27,22 @@ func (p *NoLogger) Warning(foramt string, args ...interface{}) {
 	return
 }
 
+func (p *NoLogger) Debugf(foramt string, args ...interface{}) {
+	return
+}
+
+func (p *NoLogger) Errorf(cause error, foramt string, args ...interface{}) {
+	return
+}
+
+func (p *NoLogger) Infof(foramt string, args ...interface{}) {
+	return
+}
+
+func (p *NoLogger) Warningf(foramt string, args ...interface{}) {
+	return
+}
+
 func (p *NoLogger) Log(Level akka.LogLevel, foramt string, args ...interface{}) {
 	return
 }
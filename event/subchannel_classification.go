@@ -10,6 +10,10 @@ type SubchannelClassification struct {
 	classifier akka.Classifier
 	classes    map[interface{}][]interface{}
 
+	// cache holds the resolved subscribers of every published class, it is
+	// dropped whenever the subscriptions change
+	cache map[interface{}][]interface{}
+
 	locker sync.Mutex
 }
 
@@ -18,6 +22,7 @@ func NewSubchannelClassification(publisher akka.Publisher, classifier akka.Class
 		publisher:  publisher,
 		classifier: classifier,
 		classes:    make(map[interface{}][]interface{}),
+		cache:      make(map[interface{}][]interface{}),
 	}
 }
 
@@ -28,11 +33,13 @@ func (p *SubchannelClassification) Publish(event interface{}) {
 
 	c := p.classifier.GetClassifier(event)
 
-	subscribers, exist := p.classes[c]
-
+	p.locker.Lock()
+	subscribers, exist := p.cache[c]
 	if !exist {
-		return
+		subscribers = p.subscribersOf(event, c)
+		p.cache[c] = subscribers
 	}
+	p.locker.Unlock()
 
 	for i := 0; i < len(subscribers); i++ {
 		p.publisher.PublishToSubscriber(event, subscribers[i])
@@ -54,6 +61,7 @@ func (p *SubchannelClassification) TSubscribe(subscriber interface{}, class inte
 	}
 
 	p.classes[class] = append(p.classes[class], subscriber)
+	p.cache = make(map[interface{}][]interface{})
 
 	return true
 }
@@ -79,6 +87,7 @@ func (p *SubchannelClassification) TUnsubscribe(subscriber interface{}, classes
 				newSubs = append(newSubs, oldsubs[0:j]...)
 				newSubs = append(newSubs, oldsubs[j+1:]...)
 				p.classes[classes[i]] = newSubs
+				p.cache = make(map[interface{}][]interface{})
 				break
 			}
 		}
@@ -86,3 +95,26 @@ func (p *SubchannelClassification) TUnsubscribe(subscriber interface{}, classes
 
 	return false
 }
+
+// subscribersOf collects the subscribers of c and of every other class the
+// classifier matches the event with, e.g. an interface it implements. Each
+// subscriber is listed once
+func (p *SubchannelClassification) subscribersOf(event interface{}, c interface{}) []interface{} {
+	var subscribers []interface{}
+	seen := make(map[interface{}]bool)
+
+	for class, subs := range p.classes {
+		if class != c && !p.classifier.Classify(event, class) {
+			continue
+		}
+
+		for i := 0; i < len(subs); i++ {
+			if !seen[subs[i]] {
+				seen[subs[i]] = true
+				subscribers = append(subscribers, subs[i])
+			}
+		}
+	}
+
+	return subscribers
+}
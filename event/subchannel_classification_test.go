@@ -0,0 +1,57 @@
+package event
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-akka/akka"
+)
+
+type recordingPublisher struct {
+	received map[interface{}][]interface{}
+}
+
+func (p *recordingPublisher) PublishToSubscriber(event interface{}, subscriber interface{}) {
+	p.received[subscriber] = append(p.received[subscriber], event)
+}
+
+func TestSubscribeToInterfaceReceivesImplementations(t *testing.T) {
+	publisher := &recordingPublisher{received: make(map[interface{}][]interface{})}
+	bus := NewSubchannelClassification(publisher, &EventStream{})
+
+	logEventClass := reflect.TypeOf((*akka.LogEvent)(nil)).Elem()
+	debugClass := reflect.TypeOf((*Debug)(nil)).Elem()
+
+	bus.TSubscribe("all-logs", logEventClass)
+	bus.TSubscribe("debug-only", debugClass)
+	bus.TSubscribe("debug-only", logEventClass)
+
+	bus.Publish(NewDebugEvent("source", nil, "debug message"))
+	bus.Publish(NewErrorEvent(errors.New("failure"), "source", nil, "error message"))
+	bus.Publish("not a log event")
+
+	all := publisher.received["all-logs"]
+	if len(all) != 2 {
+		t.Fatalf("interface subscriber should receive both log events, but got %v", all)
+	}
+
+	if _, ok := all[0].(*Debug); !ok {
+		t.Fatalf("first event should be *Debug, but got %T", all[0])
+	}
+
+	if _, ok := all[1].(*Error); !ok {
+		t.Fatalf("second event should be *Error, but got %T", all[1])
+	}
+
+	if got := len(publisher.received["debug-only"]); got != 2 {
+		t.Fatalf("subscriber of class and interface should get each event once, but got %d events", got)
+	}
+
+	bus.TUnsubscribe("all-logs", logEventClass)
+	bus.Publish(NewDebugEvent("source", nil, "after unsubscribe"))
+
+	if got := len(publisher.received["all-logs"]); got != 2 {
+		t.Fatalf("unsubscribed interface subscriber should not receive subtypes, but got %d events", got)
+	}
+}
@@ -2,11 +2,17 @@ package event
 
 import (
 	"reflect"
+
+	"github.com/go-akka/akka"
 )
 
 func simpleName(v interface{}) string {
 
 	switch item := v.(type) {
+	case akka.LogSource:
+		{
+			return item.LogSource()
+		}
 	case reflect.Type:
 		{
 			return item.String()
@@ -7,4 +7,5 @@ type EventStream interface {
 	Subscribe(subscriber ActorRef, channel interface{}) bool
 	Unsubscribe(subscriber ActorRef, channels ...interface{}) bool
 	PublishToSubscriber(event interface{}, subscriber interface{})
+	PublishWithLogSource(event LogEvent, logSource ActorRef)
 }
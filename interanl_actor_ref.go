@@ -13,7 +13,8 @@ type InternalActorRef interface {
 	Start()
 	Resume(err error)
 	Suspend()
-	Restart(err error)
+	// Restart recreates the actor, failed is the message it failed on
+	Restart(err error, failed *Envelope)
 	Stop()
 
 	SendSystemMessage(message SystemMessage) error
@@ -21,6 +21,10 @@ func (p *InternalActorRefBase) CompareTo(other ActorRef) int {
 	return 0
 }
 
+func (p *InternalActorRefBase) Equals(other ActorRef) bool {
+	return p.CompareTo(other) == 0
+}
+
 func (p *InternalActorRefBase) String() string {
 	return ""
 }
@@ -12,6 +12,12 @@ type LogEvent interface {
 	LogLevel() LogLevel
 }
 
+// LogSource lets a type name itself in the log events it is the source or
+// class of, instead of the name derived from its Go type
+type LogSource interface {
+	LogSource() string
+}
+
 type LoggingAdapter interface {
 	LoggingFilter
 
@@ -22,6 +28,11 @@ type LoggingAdapter interface {
 	Info(format string, args ...interface{})
 	Warning(format string, args ...interface{})
 	Log(Level LogLevel, format string, args ...interface{})
+
+	Debugf(format string, args ...interface{})
+	Errorf(cause error, format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warningf(format string, args ...interface{})
 }
 
 type LoggingFilter interface {
@@ -63,18 +74,25 @@ func (p LogLevel) String() string {
 }
 
 func LogLevelFor(level string) LogLevel {
-	lv := strings.ToUpper(level)
+	logLevel, ok := ParseLogLevel(level)
+	if !ok {
+		panic("Unknown LogLevel: " + strings.ToUpper(level) + ". Valid values are: DEBUG, INFO, WARN, ERROR")
+	}
+	return logLevel
+}
 
-	switch lv {
+// ParseLogLevel is LogLevelFor reporting an unknown level instead of
+// panicking
+func ParseLogLevel(level string) (logLevel LogLevel, ok bool) {
+	switch strings.ToUpper(level) {
 	case "DEBUG":
-		return DebugLevel
+		return DebugLevel, true
 	case "INFO":
-		return InfoLevel
-	case "WARN":
-		return WarningLevel
+		return InfoLevel, true
+	case "WARN", "WARNING":
+		return WarningLevel, true
 	case "ERROR":
-		return ErrorLevel
-	default:
-		panic("Unknown LogLevel: " + lv + ". Valid values are: DEBUG, INFO, WARN, ERROR")
+		return ErrorLevel, true
 	}
+	return
 }
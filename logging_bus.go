@@ -6,6 +6,11 @@ type LoggingBus interface {
 	SetLogLevel(level LogLevel)
 	LogLevel() LogLevel
 
+	// IsLevelEnabled tells if a logger is subscribed at level, that is level
+	// is at least the lowest of the bus level and the levels of the loggers
+	IsLevelEnabled(level LogLevel) bool
+
 	StartStdoutLogger(config *Settings)
 	StartDefaultLoggers(system ExtendedActorSystem) (err error)
+	StopDefaultLoggers(system ExtendedActorSystem)
 }
@@ -1,6 +1,8 @@
 package akka
 
 import (
+	"time"
+
 	"github.com/go-akka/configuration"
 )
 
@@ -11,12 +13,23 @@ type Mailbox interface {
 
 	SystemEnqueue(receiver ActorRef, message SystemMessage) error
 	Enqueue(receiver ActorRef, message Envelope) error
+	EnqueueFirst(receiver ActorRef, envelopes ...Envelope) error
+	DrainTo(other MessageQueue) (int, error)
 
 	NumberOfMessages() int
 	HasMessages() bool
 	HasSystemMessages() bool
+	ProcessedMessages() int64
+	// MessageDwellTime is the total time the dequeued messages waited in the mailbox
+	MessageDwellTime() time.Duration
+	LastRunProcessed() int
 
 	IsClosed() bool
+	BecomeClosed() bool
+	CleanUp(owner ActorRef, deadLetters MessageQueue) error
+
+	Suspend() bool
+	Resume() bool
 
 	CanBeScheduledForExecution(hasMessageHint bool, hasSystemMessageHint bool) bool
 	SetAsScheduled() bool
@@ -8,6 +8,30 @@ type AutoReceivedMessage interface {
 	AutoReceivedMessage()
 }
 
+// NoSerializationVerificationNeeded marks messages that are never sent to
+// another system, they are skipped by akka.actor.serialize-messages
+type NoSerializationVerificationNeeded interface {
+	NoSerializationVerificationNeeded()
+}
+
+// NotInfluenceReceiveTimeout marks messages that do not reset the receive
+// timeout of the actor receiving them
+type NotInfluenceReceiveTimeout interface {
+	NotInfluenceReceiveTimeout()
+}
+
+// InvalidMessage stands for a message that was rejected when it was told,
+// like a nil message, it is delivered to the dead letters
+type InvalidMessage struct {
+	Reason    string
+	Sender    ActorRef
+	Recipient ActorRef
+}
+
+func (p *InvalidMessage) String() string {
+	return "InvalidMessage(" + p.Reason + ")"
+}
+
 type UnhandledMessage struct {
 	Message   interface{}
 	Sender    ActorRef
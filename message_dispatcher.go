@@ -17,6 +17,25 @@ type MessageDispatcher interface {
 	Throughput() int
 	ThroughputTimeout() time.Duration
 
+	// SystemMessageDrainInterval is the number of user messages a mailbox
+	// processes between draining its system messages
+	SystemMessageDrainInterval() int
+
+	// SystemMessageQuota is the most system messages a mailbox processes in
+	// one go before it gets back to its user messages, 0 for no quota
+	SystemMessageQuota() int
+
+	// BlockingCallWarningThreshold is how long the handling of a user message
+	// may take before the cell is told it blocks the dispatcher, 0 disables it
+	BlockingCallWarningThreshold() time.Duration
+
 	Dispatch(receiver ActorCell, invocation Envelope) error
 	SystemDispatch(receiver ActorCell, invocation SystemMessage) error
+
+	// Shutdown stops accepting new executions and waits for the mailboxes
+	// currently running to finish, it reports whether they did within timeout
+	Shutdown(timeout time.Duration) (terminated bool)
+	IsShutdown() bool
+
+	Metrics() DispatcherMetrics
 }
@@ -0,0 +1,37 @@
+package akka
+
+import (
+	"time"
+)
+
+type DispatcherMetrics struct {
+	Id                string
+	Throughput        int
+	Mailboxes         int
+	RunningMailboxes  int
+	QueuedMessages    int
+	ProcessedMessages int64
+	// MessageDwellTime is the total time the processed messages waited in
+	// the mailboxes, divided by ProcessedMessages it is the mailbox latency
+	MessageDwellTime time.Duration
+}
+
+type SystemMetrics struct {
+	Mailboxes         int
+	RunningMailboxes  int
+	QueuedMessages    int
+	ProcessedMessages int64
+	MessageDwellTime  time.Duration
+
+	Dispatchers []DispatcherMetrics
+}
+
+// SystemStatus is published on the event stream every
+// akka.system-status-interval
+type SystemStatus struct {
+	StartTime int64
+	Uptime    time.Duration
+	Actors    int
+
+	Metrics SystemMetrics
+}
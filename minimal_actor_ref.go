@@ -35,16 +35,11 @@ func (p *noBodyActorRef) Provider() ActorRefProvider {
 }
 
 func (p *noBodyActorRef) CompareTo(other ActorRef) int {
-	x := p.path.CompareTo(other.Path())
-	if x == 0 {
-		if p.path.Uid() < other.Path().Uid() {
-			return -1
-		} else if p.path.Uid() == other.Path().Uid() {
-			return 0
-		}
-		return 1
-	}
-	return x
+	return CompareActorRefs(p, other)
+}
+
+func (p *noBodyActorRef) Equals(other ActorRef) bool {
+	return CompareActorRefs(p, other) == 0
 }
 
 type MinimalActorRef struct {
@@ -70,16 +65,11 @@ func (p *MinimalActorRef) Path() ActorPath {
 }
 
 func (p *MinimalActorRef) CompareTo(other ActorRef) int {
-	x := p.path.CompareTo(other.Path())
-	if x == 0 {
-		if p.path.Uid() < other.Path().Uid() {
-			return -1
-		} else if p.path.Uid() == other.Path().Uid() {
-			return 0
-		}
-		return 1
-	}
-	return x
+	return CompareActorRefs(p, other)
+}
+
+func (p *MinimalActorRef) Equals(other ActorRef) bool {
+	return CompareActorRefs(p, other) == 0
 }
 
 func (p *MinimalActorRef) Provider() ActorRefProvider {
@@ -128,7 +118,7 @@ func (p *MinimalActorRef) Suspend() {
 	return
 }
 
-func (p *MinimalActorRef) Restart(err error) {
+func (p *MinimalActorRef) Restart(err error, failed *Envelope) {
 	return
 }
 
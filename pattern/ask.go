@@ -0,0 +1,79 @@
+package pattern
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-akka/akka"
+)
+
+var (
+	promiseId int64
+)
+
+// PromiseActorRef is a temporary ref that is completed by the first message
+// told to it, the messages after that are dropped
+type PromiseActorRef struct {
+	*akka.MinimalActorRef
+
+	result chan interface{}
+	done   int32
+	once   sync.Once
+}
+
+func NewPromiseActorRef() *PromiseActorRef {
+	name := "$ask-" + strconv.FormatInt(atomic.AddInt64(&promiseId, 1), 10)
+	path := akka.NewRootActorPath(akka.NewAddress("akka", "all-systems", "", 0), "/temp/"+name)
+
+	return &PromiseActorRef{
+		MinimalActorRef: akka.NewMinimalActorRef(path, nil),
+		result:          make(chan interface{}, 1),
+	}
+}
+
+func (p *PromiseActorRef) Tell(message interface{}, sender ...akka.ActorRef) (err error) {
+	p.once.Do(func() {
+		atomic.StoreInt32(&p.done, 1)
+		p.result <- message
+	})
+	return
+}
+
+func (p *PromiseActorRef) IsCompleted() bool {
+	return atomic.LoadInt32(&p.done) == 1
+}
+
+// Await waits up to timeout for the reply, it returns ErrAskTimeout if none
+// arrived, the promise is completed then so a late reply is dropped
+func (p *PromiseActorRef) Await(timeout time.Duration) (reply interface{}, err error) {
+	select {
+	case reply = <-p.result:
+		return
+	case <-time.After(timeout):
+		{
+			p.once.Do(func() { atomic.StoreInt32(&p.done, 1) })
+
+			select {
+			case reply = <-p.result:
+			default:
+				err = ErrAskTimeout
+			}
+		}
+	}
+
+	return
+}
+
+// Ask tells message to target with a PromiseActorRef as sender and waits up to
+// timeout for the reply
+func Ask(target akka.ActorRef, message interface{}, timeout time.Duration) (reply interface{}, err error) {
+	promise := NewPromiseActorRef()
+
+	if err = target.Tell(message, promise); err != nil {
+		return
+	}
+
+	return promise.Await(timeout)
+}
@@ -0,0 +1,223 @@
+package pattern
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-akka/akka"
+)
+
+type CircuitBreakerState int
+
+const (
+	Closed CircuitBreakerState = iota
+	Open
+	HalfOpen
+)
+
+func (p CircuitBreakerState) String() string {
+	switch p {
+	case Closed:
+		return "Closed"
+	case Open:
+		return "Open"
+	case HalfOpen:
+		return "HalfOpen"
+	}
+	return fmt.Sprintf("CircuitBreakerState(%d)", int(p))
+}
+
+// CircuitBreakerStateChanged is published to the event stream on every
+// transition of a CircuitBreaker
+type CircuitBreakerStateChanged struct {
+	Breaker *CircuitBreaker
+	From    CircuitBreakerState
+	To      CircuitBreakerState
+}
+
+func (p *CircuitBreakerStateChanged) String() string {
+	return fmt.Sprintf("<CircuitBreakerStateChanged>: %s -> %s", p.From, p.To)
+}
+
+type CircuitBreaker struct {
+	eventStream akka.EventBus
+
+	maxFailures  int
+	callTimeout  time.Duration
+	resetTimeout time.Duration
+
+	state    CircuitBreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+
+	locker sync.Mutex
+}
+
+// NewCircuitBreaker creates a breaker that opens after maxFailures consecutive
+// failures or timeouts and half-opens after resetTimeout to let one probe call
+// through, eventStream may be nil if no transition events are wanted
+func NewCircuitBreaker(eventStream akka.EventBus, maxFailures int, callTimeout, resetTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		eventStream:  eventStream,
+		maxFailures:  maxFailures,
+		callTimeout:  callTimeout,
+		resetTimeout: resetTimeout,
+	}
+}
+
+func (p *CircuitBreaker) State() (state CircuitBreakerState) {
+	p.locker.Lock()
+	changed := p.tryHalfOpen()
+	state = p.state
+	p.locker.Unlock()
+
+	p.publish(changed)
+
+	return
+}
+
+func (p *CircuitBreaker) Call(body func() (result interface{}, err error)) (result interface{}, err error) {
+	if err = p.beforeCall(); err != nil {
+		return
+	}
+
+	result, err = p.callWithTimeout(body)
+
+	if err != nil {
+		p.onFailure()
+	} else {
+		p.onSuccess()
+	}
+
+	return
+}
+
+func (p *CircuitBreaker) callWithTimeout(body func() (interface{}, error)) (result interface{}, err error) {
+	if p.callTimeout <= 0 {
+		return body()
+	}
+
+	type callResult struct {
+		result interface{}
+		err    error
+	}
+
+	done := make(chan callResult, 1)
+
+	go func() {
+		r, e := body()
+		done <- callResult{r, e}
+	}()
+
+	timer := time.NewTimer(p.callTimeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-done:
+		return r.result, r.err
+	case <-timer.C:
+		return nil, ErrCircuitBreakerTimeout
+	}
+}
+
+func (p *CircuitBreaker) beforeCall() (err error) {
+	p.locker.Lock()
+	changed := p.tryHalfOpen()
+
+	switch p.state {
+	case Open:
+		{
+			err = ErrCircuitBreakerOpen
+		}
+	case HalfOpen:
+		{
+			if p.probing {
+				err = ErrCircuitBreakerOpen
+			} else {
+				p.probing = true
+			}
+		}
+	}
+	p.locker.Unlock()
+
+	p.publish(changed)
+
+	return
+}
+
+func (p *CircuitBreaker) onSuccess() {
+	var changed *CircuitBreakerStateChanged
+
+	p.locker.Lock()
+	p.failures = 0
+
+	if p.state == HalfOpen {
+		p.probing = false
+		changed = p.transition(Closed)
+	}
+	p.locker.Unlock()
+
+	p.publish(changed)
+}
+
+func (p *CircuitBreaker) onFailure() {
+	var changed *CircuitBreakerStateChanged
+
+	p.locker.Lock()
+	switch p.state {
+	case Closed:
+		{
+			p.failures++
+			if p.failures >= p.maxFailures {
+				changed = p.open()
+			}
+		}
+	case HalfOpen:
+		{
+			p.probing = false
+			changed = p.open()
+		}
+	}
+	p.locker.Unlock()
+
+	p.publish(changed)
+}
+
+func (p *CircuitBreaker) open() (changed *CircuitBreakerStateChanged) {
+	p.openedAt = time.Now()
+	return p.transition(Open)
+}
+
+func (p *CircuitBreaker) tryHalfOpen() (changed *CircuitBreakerStateChanged) {
+	if p.state == Open && time.Since(p.openedAt) >= p.resetTimeout {
+		changed = p.transition(HalfOpen)
+	}
+	return
+}
+
+// transition is called with the locker held, it returns the event of the
+// change which is published once the locker is released so subscribers may
+// call back into the breaker
+func (p *CircuitBreaker) transition(to CircuitBreakerState) (changed *CircuitBreakerStateChanged) {
+	from := p.state
+	if from == to {
+		return
+	}
+
+	p.state = to
+
+	if to == Closed {
+		p.failures = 0
+	}
+
+	changed = &CircuitBreakerStateChanged{Breaker: p, From: from, To: to}
+	return
+}
+
+func (p *CircuitBreaker) publish(changed *CircuitBreakerStateChanged) {
+	if changed != nil && p.eventStream != nil {
+		p.eventStream.Publish(changed)
+	}
+}
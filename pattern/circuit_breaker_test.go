@@ -0,0 +1,163 @@
+package pattern
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingBus struct {
+	events []*CircuitBreakerStateChanged
+	locker sync.Mutex
+}
+
+func (p *recordingBus) TSubscribe(subscriber interface{}, classifier interface{}) bool {
+	return true
+}
+
+func (p *recordingBus) TUnsubscribe(subscriber interface{}, classifiers ...interface{}) bool {
+	return true
+}
+
+func (p *recordingBus) Publish(event interface{}) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	if changed, ok := event.(*CircuitBreakerStateChanged); ok {
+		p.events = append(p.events, changed)
+	}
+}
+
+func (p *recordingBus) transitions() (states []CircuitBreakerState) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	for _, e := range p.events {
+		states = append(states, e.To)
+	}
+	return
+}
+
+var errFlaky = errors.New("flaky resource")
+
+func failingCall() (interface{}, error) {
+	return nil, errFlaky
+}
+
+func succeedingCall() (interface{}, error) {
+	return "ok", nil
+}
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	bus := &recordingBus{}
+	breaker := NewCircuitBreaker(bus, 2, time.Second, 100*time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		if _, err := breaker.Call(failingCall); err != errFlaky {
+			t.Fatalf("call %d should return the body error, but got %v", i, err)
+		}
+	}
+
+	if state := breaker.State(); state != Open {
+		t.Fatalf("breaker should be open after max failures, but is %s", state)
+	}
+
+	called := false
+	if _, err := breaker.Call(func() (interface{}, error) { called = true; return nil, nil }); err != ErrCircuitBreakerOpen || called {
+		t.Fatalf("open breaker should fail fast without calling, err: %v, called: %v", err, called)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	if state := breaker.State(); state != HalfOpen {
+		t.Fatalf("breaker should be half-open after reset timeout, but is %s", state)
+	}
+
+	result, err := breaker.Call(succeedingCall)
+	if err != nil || result != "ok" {
+		t.Fatalf("probe call should succeed, result: %v, err: %v", result, err)
+	}
+
+	if state := breaker.State(); state != Closed {
+		t.Fatalf("breaker should close after a successful probe, but is %s", state)
+	}
+
+	expected := []CircuitBreakerState{Open, HalfOpen, Closed}
+	got := bus.transitions()
+	if len(got) != len(expected) {
+		t.Fatalf("expected transitions %v, but got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected transitions %v, but got %v", expected, got)
+		}
+	}
+}
+
+func TestCircuitBreakerTimeoutCountsAsFailure(t *testing.T) {
+	breaker := NewCircuitBreaker(nil, 1, 20*time.Millisecond, time.Minute)
+
+	_, err := breaker.Call(func() (interface{}, error) {
+		time.Sleep(200 * time.Millisecond)
+		return nil, nil
+	})
+
+	if err != ErrCircuitBreakerTimeout {
+		t.Fatalf("slow call should time out, but got %v", err)
+	}
+
+	if state := breaker.State(); state != Open {
+		t.Fatalf("breaker should open after a timeout, but is %s", state)
+	}
+}
+
+func TestCircuitBreakerFailedProbeReopens(t *testing.T) {
+	breaker := NewCircuitBreaker(nil, 1, time.Second, 50*time.Millisecond)
+
+	breaker.Call(failingCall)
+	time.Sleep(80 * time.Millisecond)
+
+	if _, err := breaker.Call(failingCall); err != errFlaky {
+		t.Fatalf("probe should be let through, but got %v", err)
+	}
+
+	if state := breaker.State(); state != Open {
+		t.Fatalf("breaker should reopen after a failed probe, but is %s", state)
+	}
+}
+
+// reentrantBus reads the state of the breaker from Publish, as a subscriber
+// running on the publishing goroutine would
+type reentrantBus struct {
+	recordingBus
+
+	breaker *CircuitBreaker
+	states  []CircuitBreakerState
+}
+
+func (p *reentrantBus) Publish(event interface{}) {
+	p.states = append(p.states, p.breaker.State())
+	p.recordingBus.Publish(event)
+}
+
+func TestCircuitBreakerPublishesOutsideItsLock(t *testing.T) {
+	bus := &reentrantBus{}
+	bus.breaker = NewCircuitBreaker(bus, 1, time.Second, time.Hour)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		bus.breaker.Call(failingCall)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("publishing a transition should not hold the lock of the breaker")
+	}
+
+	if len(bus.states) != 1 || bus.states[0] != Open {
+		t.Fatalf("subscriber should see the breaker open, but got %v", bus.states)
+	}
+}
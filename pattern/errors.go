@@ -0,0 +1,13 @@
+package pattern
+
+import (
+	"errors"
+)
+
+var (
+	ErrCircuitBreakerOpen    = errors.New("circuit breaker is open, calls are failing fast")
+	ErrCircuitBreakerTimeout = errors.New("circuit breaker call timed out")
+	ErrAskTimeout            = errors.New("ask timed out before a reply arrived")
+	ErrThrottled             = errors.New("message dropped, the throttle rate is exceeded")
+	ErrBadThrottleOptions    = errors.New("throttle should have a scheduler, a ref and a positive rate")
+)
@@ -0,0 +1,125 @@
+package pattern
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-akka/akka"
+)
+
+type ThrottleMode int
+
+const (
+	// Shaping buffers the messages beyond the rate and delivers them later
+	Shaping ThrottleMode = iota
+	// Dropping drops the messages beyond the rate, Tell returns ErrThrottled
+	Dropping
+)
+
+type throttledMessage struct {
+	message interface{}
+	sender  []akka.ActorRef
+}
+
+// ThrottledRef is the ref returned by Throttle, it tells at most elements
+// messages per duration to the wrapped ref, everything else is forwarded as
+// it is
+type ThrottledRef struct {
+	akka.ActorRef
+
+	scheduler akka.Scheduler
+	mode      ThrottleMode
+	elements  int
+	interval  time.Duration
+
+	tokens  int
+	buffer  []throttledMessage
+	ticking bool
+	locker  sync.Mutex
+}
+
+// Throttle wraps ref so that at most elements messages are delivered to it
+// per duration, in bursts of up to elements. A token is regained every
+// per/elements by a tick of the scheduler, which is only scheduled while
+// tokens are missing, so an idle throttle costs nothing. With Shaping the
+// buffer is unbounded
+func Throttle(scheduler akka.Scheduler, ref akka.ActorRef, elements int, per time.Duration, mode ThrottleMode) (throttled *ThrottledRef, err error) {
+	if scheduler == nil || ref == nil || elements <= 0 || per <= 0 {
+		err = ErrBadThrottleOptions
+		return
+	}
+
+	throttled = &ThrottledRef{
+		ActorRef:  ref,
+		scheduler: scheduler,
+		mode:      mode,
+		elements:  elements,
+		interval:  per / time.Duration(elements),
+		tokens:    elements,
+	}
+
+	return
+}
+
+func (p *ThrottledRef) Tell(message interface{}, sender ...akka.ActorRef) (err error) {
+	p.locker.Lock()
+
+	if p.tokens > 0 && len(p.buffer) == 0 {
+		p.tokens--
+		p.scheduleTick()
+		p.locker.Unlock()
+
+		return p.ActorRef.Tell(message, sender...)
+	}
+
+	if p.mode == Dropping {
+		p.locker.Unlock()
+		return ErrThrottled
+	}
+
+	p.buffer = append(p.buffer, throttledMessage{message: message, sender: sender})
+	p.locker.Unlock()
+
+	return
+}
+
+// Buffered are the messages waiting for a token
+func (p *ThrottledRef) Buffered() int {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return len(p.buffer)
+}
+
+// scheduleTick has to be called with the locker held
+func (p *ThrottledRef) scheduleTick() {
+	if p.ticking || p.tokens >= p.elements {
+		return
+	}
+
+	p.ticking = true
+	p.scheduler.Advanced().ScheduleOnce(p.interval, akka.ActionFunc(p.tick), nil)
+}
+
+func (p *ThrottledRef) tick() {
+	p.locker.Lock()
+
+	p.ticking = false
+	p.tokens++
+
+	var next *throttledMessage
+	if len(p.buffer) > 0 {
+		head := p.buffer[0]
+		next = &head
+		p.buffer[0] = throttledMessage{}
+		p.buffer = p.buffer[1:]
+		p.tokens--
+	}
+
+	p.scheduleTick()
+	p.locker.Unlock()
+
+	if next != nil {
+		p.ActorRef.Tell(next.message, next.sender...)
+	}
+}
@@ -0,0 +1,147 @@
+package pattern
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+)
+
+// timerScheduler runs the actions on time.AfterFunc, the pattern package can
+// not use the scheduler of the actor package
+type timerScheduler struct{}
+
+type timerAdvancedScheduler struct{}
+
+func (p timerScheduler) ScheduleTellOnce(delay time.Duration, receiver akka.CanTell, message interface{}, sender akka.ActorRef, cancelable akka.Cancelable) {
+	time.AfterFunc(delay, func() { receiver.Tell(message, sender) })
+}
+
+func (p timerScheduler) ScheduleRepeatedly(delay time.Duration, interval time.Duration, receiver akka.CanTell, message interface{}, sender akka.ActorRef, cancelable akka.Cancelable) {
+}
+
+func (p timerScheduler) Advanced() akka.AdvancedScheduler {
+	return timerAdvancedScheduler{}
+}
+
+func (p timerAdvancedScheduler) ScheduleOnce(delay time.Duration, action akka.Action, cancelable akka.Cancelable) {
+	time.AfterFunc(delay, action.Action)
+}
+
+func (p timerAdvancedScheduler) ScheduleRepeatedly(initialDelay time.Duration, interval time.Duration, action akka.Action, cancelable akka.Cancelable) {
+}
+
+func (p timerAdvancedScheduler) ScheduleOnceWithJitter(delay time.Duration, jitter time.Duration, action akka.Action, cancelable akka.Cancelable) {
+	p.ScheduleOnce(delay, action, cancelable)
+}
+
+type timestampingRef struct {
+	*akka.MinimalActorRef
+
+	received []interface{}
+	at       []time.Time
+	locker   sync.Mutex
+}
+
+func newTimestampingRef() *timestampingRef {
+	return &timestampingRef{
+		MinimalActorRef: akka.NewMinimalActorRef(akka.NewRootActorPath(akka.NewAddress("akka", "test", "", 0), "/throttled"), nil),
+	}
+}
+
+func (p *timestampingRef) Tell(message interface{}, sender ...akka.ActorRef) (err error) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	p.received = append(p.received, message)
+	p.at = append(p.at, time.Now())
+	return
+}
+
+func (p *timestampingRef) snapshot() ([]interface{}, []time.Time) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return append([]interface{}(nil), p.received...), append([]time.Time(nil), p.at...)
+}
+
+func TestThrottleShapingKeepsTheRateWithinBounds(t *testing.T) {
+	target := newTimestampingRef()
+
+	throttled, err := Throttle(timerScheduler{}, target, 10, 100*time.Millisecond, Shaping)
+	if err != nil {
+		t.Fatalf("create throttle failure: %s", err.Error())
+	}
+
+	start := time.Now()
+	for i := 0; i < 40; i++ {
+		if err = throttled.Tell(i); err != nil {
+			t.Fatalf("shaping throttle should buffer, but got %s", err.Error())
+		}
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		if received, _ := target.snapshot(); len(received) == 40 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("buffered messages were not all delivered, %d are still buffered", throttled.Buffered())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	received, at := target.snapshot()
+	for i, msg := range received {
+		if msg != i {
+			t.Fatalf("message %d should be delivered in order, but got %v", i, msg)
+		}
+	}
+
+	// a burst of 10, then one every 10ms
+	if elapsed := at[len(at)-1].Sub(start); elapsed < 250*time.Millisecond {
+		t.Fatalf("40 messages at 10 per 100ms should take about 300ms, but took %s", elapsed)
+	}
+
+	// no window of 100ms gets more than the burst and the tokens regained in it
+	for i := range at {
+		inWindow := 0
+		for j := i; j < len(at) && at[j].Sub(at[i]) < 100*time.Millisecond; j++ {
+			inWindow++
+		}
+		if inWindow > 20 {
+			t.Fatalf("%d messages were delivered within 100ms of message %d", inWindow, i)
+		}
+	}
+}
+
+func TestThrottleDroppingRefusesTheExcess(t *testing.T) {
+	target := newTimestampingRef()
+
+	throttled, err := Throttle(timerScheduler{}, target, 5, time.Second, Dropping)
+	if err != nil {
+		t.Fatalf("create throttle failure: %s", err.Error())
+	}
+
+	dropped := 0
+	for i := 0; i < 20; i++ {
+		if err = throttled.Tell(i); err == ErrThrottled {
+			dropped++
+		} else if err != nil {
+			t.Fatalf("tell failure: %s", err.Error())
+		}
+	}
+
+	if received, _ := target.snapshot(); len(received) != 5 || dropped != 15 {
+		t.Fatalf("burst of 5 should be delivered and 15 dropped, but got %d and %d", len(received), dropped)
+	}
+
+	if throttled.Buffered() != 0 {
+		t.Fatalf("dropping throttle should not buffer, but buffered %d", throttled.Buffered())
+	}
+
+	if _, err = Throttle(timerScheduler{}, target, 0, time.Second, Dropping); err != ErrBadThrottleOptions {
+		t.Fatalf("throttle without rate should fail, but got %v", err)
+	}
+}
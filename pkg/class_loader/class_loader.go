@@ -19,6 +19,7 @@ type ClassLoader interface {
 	Register(v interface{}, name string)
 	ClassOf(v interface{}) (typ reflect.Type, exist bool)
 	ClassNameOf(name string) (typ reflect.Type, exist bool)
+	ClassPathOf(path string) (typ reflect.Type, exist bool)
 	Parent() (loader ClassLoader)
 }
 
@@ -104,6 +105,22 @@ func (p *ClassicClassLoader) ClassNameOf(name string) (typ reflect.Type, exist b
 	return
 }
 
+// ClassPathOf finds a registered type by its package path and name, the way
+// ClassOf keys it, whatever name it was registered with
+func (p *ClassicClassLoader) ClassPathOf(path string) (typ reflect.Type, exist bool) {
+	if t, ex := p.pathTypes[path]; !ex {
+		if p.parent != nil {
+			typ, exist = p.parent.ClassPathOf(path)
+			return
+		}
+	} else {
+		typ = t
+		exist = ex
+	}
+
+	return
+}
+
 func (p *ClassicClassLoader) Parent() (loader ClassLoader) {
 	loader = p.parent
 	return
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"github.com/go-akka/akka/pkg/class_loader"
 
+	"math"
 	"reflect"
+	"strings"
 )
 
 var (
@@ -17,6 +19,7 @@ var (
 	ErrBadActorInitFuncOutType   = errors.New("the actor init func return's should be void or error")
 	ErrCreateInstanceFailure     = errors.New("create instance failure")
 	ErrTypeNotExistInClassLoader = errors.New("type not in class loader")
+	ErrConstructArgsMismatch     = errors.New("construct args mismatch")
 )
 
 type DynamicAccess interface {
@@ -82,8 +85,9 @@ func (p *ReflectiveDynamicAccess) constructInstance(val reflect.Value, args ...i
 	}
 
 	var valArgs []reflect.Value
-	for _, arg := range args {
-		valArgs = append(valArgs, reflect.ValueOf(arg))
+	if valArgs, err = coerceArgs(methodVal.Type(), args); err != nil {
+		err = fmt.Errorf("%s: %s.Construct%s, got (%s)", ErrConstructArgsMismatch, val.Type().Elem(), signatureOf(methodVal.Type()), err.Error())
+		return
 	}
 
 	fnRetVals := methodVal.Call(valArgs)
@@ -97,3 +101,121 @@ func (p *ReflectiveDynamicAccess) constructInstance(val reflect.Value, args ...i
 
 	return
 }
+
+// coerceArgs converts args to the parameters of fnType, nil becomes the zero
+// value of a nillable parameter and numbers are converted between kinds as
+// long as they keep their value, any
+// other arg has to be assignable. The error lists the types of the args
+func coerceArgs(fnType reflect.Type, args []interface{}) (valArgs []reflect.Value, err error) {
+	numIn := fnType.NumIn()
+
+	mismatch := len(args) != numIn
+	if fnType.IsVariadic() {
+		mismatch = len(args) < numIn-1
+	}
+
+	for i := 0; i < len(args) && !mismatch; i++ {
+		var paramType reflect.Type
+		if fnType.IsVariadic() && i >= numIn-1 {
+			paramType = fnType.In(numIn - 1).Elem()
+		} else {
+			paramType = fnType.In(i)
+		}
+
+		argVal, ok := coerceArg(args[i], paramType)
+		if !ok {
+			mismatch = true
+			break
+		}
+		valArgs = append(valArgs, argVal)
+	}
+
+	if mismatch {
+		var argTypes []string
+		for _, arg := range args {
+			argTypes = append(argTypes, fmt.Sprintf("%T", arg))
+		}
+		err = errors.New(strings.Join(argTypes, ", "))
+		valArgs = nil
+	}
+
+	return
+}
+
+func coerceArg(arg interface{}, paramType reflect.Type) (argVal reflect.Value, ok bool) {
+	if arg == nil {
+		switch paramType.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			{
+				return reflect.Zero(paramType), true
+			}
+		}
+		return
+	}
+
+	argVal = reflect.ValueOf(arg)
+
+	if argVal.Type().AssignableTo(paramType) {
+		return argVal, true
+	}
+
+	if isNumber(argVal.Kind()) && isNumber(paramType.Kind()) {
+		if !signFits(argVal, paramType.Kind()) {
+			return reflect.Value{}, false
+		}
+
+		converted := argVal.Convert(paramType)
+		// a fraction or a value out of the range of the parameter does not
+		// convert back to the arg
+		if converted.Convert(argVal.Type()).Interface() != arg {
+			return reflect.Value{}, false
+		}
+		return converted, true
+	}
+
+	return reflect.Value{}, false
+}
+
+// signFits rejects a negative value for an unsigned parameter and an unsigned
+// value above the signed maximum for a signed one, their conversion wraps
+// around and converts back to the same value
+func signFits(argVal reflect.Value, paramKind reflect.Kind) bool {
+	switch {
+	case isUnsigned(paramKind) && isSigned(argVal.Kind()):
+		return argVal.Int() >= 0
+	case isUnsigned(paramKind) && isFloat(argVal.Kind()):
+		return argVal.Float() >= 0
+	case isSigned(paramKind) && isUnsigned(argVal.Kind()):
+		return argVal.Uint() <= math.MaxInt64
+	}
+	return true
+}
+
+func isNumber(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+func isSigned(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Int64
+}
+
+func isUnsigned(kind reflect.Kind) bool {
+	return kind >= reflect.Uint && kind <= reflect.Uintptr
+}
+
+func isFloat(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
+
+func signatureOf(fnType reflect.Type) string {
+	var params []string
+	for i := 0; i < fnType.NumIn(); i++ {
+		if fnType.IsVariadic() && i == fnType.NumIn()-1 {
+			params = append(params, "..."+fnType.In(i).Elem().String())
+		} else {
+			params = append(params, fnType.In(i).String())
+		}
+	}
+
+	return "(" + strings.Join(params, ", ") + ")"
+}
@@ -0,0 +1,118 @@
+package dynamic_access
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-akka/akka/pkg/class_loader"
+)
+
+type Named interface {
+	Name() string
+}
+
+type named string
+
+func (p named) Name() string {
+	return string(p)
+}
+
+type constructed struct {
+	name    string
+	retries int64
+	ratio   float64
+	tags    []string
+}
+
+func (p *constructed) Construct(name Named, retries int64, ratio float64, tags ...string) {
+	p.name = name.Name()
+	p.retries = retries
+	p.ratio = ratio
+	p.tags = tags
+}
+
+type sized struct {
+	count  uint
+	offset int64
+	small  int32
+}
+
+func (p *sized) Construct(count uint, offset int64, small int32) {
+	p.count = count
+	p.offset = offset
+	p.small = small
+}
+
+func newDynamicAccess() DynamicAccess {
+	loader := class_loader.NewClassicClassLoader(nil)
+	loader.Register((*constructed)(nil), "test.constructed")
+	loader.Register((*sized)(nil), "test.sized")
+	return NewReflectiveDynamicAccess(loader)
+}
+
+func TestConstructArgsAreCoerced(t *testing.T) {
+	ins, err := newDynamicAccess().CreateInstanceByName("test.constructed", named("worker"), 3, float32(0.5), "a", "b")
+	if err != nil {
+		t.Fatalf("create instance failure: %s", err.Error())
+	}
+
+	c := ins.(*constructed)
+	if c.name != "worker" || c.retries != 3 || c.ratio != 0.5 || !reflect.DeepEqual(c.tags, []string{"a", "b"}) {
+		t.Fatalf("args should be coerced to the Construct params, but got %#v", c)
+	}
+}
+
+func TestMismatchedConstructArgsListTheSignature(t *testing.T) {
+	access := newDynamicAccess()
+
+	mismatches := [][]interface{}{
+		{named("worker"), "3", 0.5},
+		{named("worker"), 1.5, 0.5},
+		{named("worker")},
+		{named("worker"), 3, []string{"half"}},
+	}
+
+	for _, args := range mismatches {
+		_, err := access.CreateInstanceByName("test.constructed", args...)
+		if err == nil || !strings.HasPrefix(err.Error(), ErrConstructArgsMismatch.Error()) {
+			t.Fatalf("args %v should fail with ErrConstructArgsMismatch, but got %v", args, err)
+		}
+
+		if !strings.Contains(err.Error(), "Construct(dynamic_access.Named, int64, float64, ...string)") {
+			t.Fatalf("error should list the expected signature, but got %s", err.Error())
+		}
+	}
+
+	_, err := access.CreateInstanceByName("test.constructed", named("worker"), "3", 0.5)
+	if !strings.HasSuffix(err.Error(), "got (dynamic_access.named, string, float64)") {
+		t.Fatalf("error should list the actual args, but got %s", err.Error())
+	}
+}
+
+func TestOutOfRangeConstructArgsAreRejected(t *testing.T) {
+	access := newDynamicAccess()
+
+	ins, err := access.CreateInstanceByName("test.sized", 1, uint64(2), int64(-3))
+	if err != nil {
+		t.Fatalf("args in range should be coerced, but got %s", err.Error())
+	}
+
+	if s := ins.(*sized); s.count != 1 || s.offset != 2 || s.small != -3 {
+		t.Fatalf("args should be coerced to the Construct params, but got %#v", s)
+	}
+
+	mismatches := [][]interface{}{
+		{-1, 0, 0},
+		{-1.0, 0, 0},
+		{0, uint64(math.MaxUint64), 0},
+		{0, 0, 1 << 40},
+	}
+
+	for _, args := range mismatches {
+		if _, err = access.CreateInstanceByName("test.sized", args...); err == nil || !strings.HasPrefix(err.Error(), ErrConstructArgsMismatch.Error()) {
+			t.Fatalf("args %v should fail with ErrConstructArgsMismatch, but got %v", args, err)
+		}
+	}
+}
@@ -84,6 +84,16 @@ func (lfq *LockfreeQueue) IsEmpty() bool {
 	return (atomic.LoadUint64(&lfq.queue) - atomic.LoadUint64(&lfq.dequeue)) == 0
 }
 
+// Snapshot returns the elements in the queue from front to back without
+// removing them. Concurrent pushes and pops may or may not be seen.
+func (lfq *LockfreeQueue) Snapshot() (vals []interface{}) {
+	rh := (*lfqNode)(atomic.LoadPointer(&lfq.head))
+	for n := (*lfqNode)(atomic.LoadPointer(&rh.next)); n != nil; n = (*lfqNode)(atomic.LoadPointer(&n.next)) {
+		vals = append(vals, n.val)
+	}
+	return
+}
+
 type lfqNode struct {
 	val  interface{}
 	next unsafe.Pointer
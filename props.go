@@ -6,8 +6,10 @@ import (
 
 type Props interface {
 	Type() reflect.Type
+	ProducerType() reflect.Type
 	NewActor() (actor Actor, err error)
 	Create(v interface{}, args ...interface{}) (props Props, err error)
+	Deploy() Deploy
 	Dispatcher() string
 	Mailbox() string
 	RouterConfig() RouterConfig
@@ -15,4 +17,10 @@ type Props interface {
 	WithDispatcher(dispatcher string) (props Props)
 	WithMailbox(mailbox string) (props Props)
 	WithRouter(config RouterConfig) (props Props)
+
+	// WithPooling makes the stopped instances reused by the next actors of
+	// the props, ReleaseActor hands a stopped instance back
+	WithPooling(pooling bool) (props Props)
+	Pooling() bool
+	ReleaseActor(actor Actor)
 }
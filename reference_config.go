@@ -0,0 +1,166 @@
+package akka
+
+import (
+	"github.com/go-akka/configuration"
+)
+
+// referenceConfig holds the defaults of every setting, the user config given
+// to NewSettings is merged on top of it
+const referenceConfig = `
+akka {
+	version = "0.1.0"
+
+	loggers = ["akka.event.default-logger"]
+	loggers-dispatcher = "akka.actor.default-dispatcher"
+
+	# minimum level of single loggers, e.g. "akka.event.default-logger" = DEBUG,
+	# loggers without an entry are subscribed at loglevel
+	logger-levels {
+	}
+	logger-startup-timeout = 5s
+	logger-flush-timeout = 5s
+
+	loglevel = "INFO"
+	stdout-loglevel = "WARN"
+
+	# record the file and line of the caller of PublishWithLogSource
+	log-caller-info = off
+
+	# the dead letters of DeadLetterSuppression messages are only counted, or
+	# with publish-suppressed-dead-letters on rolled up into one
+	# SuppressedDeadLetter every interval, 0 publishes each of them
+	publish-suppressed-dead-letters = off
+	suppressed-dead-letters-summary-interval = 10s
+
+	# the first log-dead-letters dead letters are logged one by one, the ones
+	# after that are counted and logged as a summary every interval, 0 disables
+	# the dead letter logging
+	log-dead-letters = 10
+	log-dead-letters-summary-interval = 5m
+
+	# publish a SystemStatus on the event stream every interval, 0s disables it
+	system-status-interval = 0s
+
+	scheduler {
+		# class name of an akka.Scheduler constructed with the config, without
+		# one the DefaultScheduler is used
+		implementation = ""
+	}
+
+	extensions = []
+	library-extensions = []
+
+	actor {
+		provider = "LocalActorRefProvider"
+
+		# class name of the akka.SupervisorStrategyConfigurator creating the
+		# strategy of the user guardian, for the top-level actors, by default
+		# they are stopped when they fail. "akka.actor.DefaultSupervisorStrategy"
+		# restarts them
+		guardian-supervisor-strategy = "akka.actor.StoppingSupervisorStrategy"
+
+		restart-resends-failed-message = off
+
+		# the headers of the message being received that Context().Tell carries
+		# on to the messages it tells, only context like the trace id
+		propagated-headers = ["trace-id"]
+
+		# fail creating an actor if constructor args are given but its type
+		# has no init func named after it, by default the args are ignored
+		strict-init-funcs = off
+
+		# Become without discardOld refuses to push beyond this depth, 0 for
+		# no limit
+		behavior-stack-max-depth = 100
+
+		# old dispatcher ids to the ids they are resolved to while the old
+		# ones are not configured, e.g.
+		# "akka.actor.my-old-dispatcher" = "akka.actor.my-new-dispatcher"
+		dispatcher-aliases {
+		}
+
+		default-dispatcher {
+			type = "dispatcher"
+			# default-executor, or the class name of an ExecutorService or an
+			# ExecutorServiceFactoryProvider
+			executor = "default-executor"
+			throughput = 5
+			throughput-deadline-time = 0ms
+			system-message-drain-interval = 1
+			# the most system messages processed in one go before the user
+			# messages get their turn, 0 for no quota, which lets a flood of
+			# watches or failures starve the user messages
+			system-message-quota = 100
+			# a user message handled for longer than this is warned about, as
+			# it starves the other actors of the dispatcher, 0s disables it
+			blocking-call-warning-threshold = 0s
+			# how long the termination waits for the running mailboxes, the
+			# ones still running then are closed and their messages dead-lettered
+			shutdown-timeout = 1s
+		}
+
+		# the dispatcher for actors blocking on I/O, falls back to the
+		# default-dispatcher for the settings not given here
+		default-blocking-io-dispatcher {
+			type = "dispatcher"
+			throughput = 1
+			blocking-call-warning-threshold = 0s
+		}
+
+		default-mailbox {
+			mailbox-type = "akka.dispatch.unbounded-mailbox"
+		}
+
+		# serialize and deserialize every message on tell, to catch the
+		# messages that can not be serialized early, only for testing
+		serialize-messages = off
+
+		# cache the actors that selections resolve to until they terminate,
+		# for the selections resolved again and again on hot paths
+		selection-cache = off
+
+		# start a span on every message told, carried in its trace-id, span-id
+		# and parent-span-id headers, and publish the SpanStarted and
+		# SpanFinished events for an exporter to build the traces
+		tracing = off
+
+		serializers {
+			json = "akka.serialization.json-serializer"
+			gob = "akka.serialization.gob-serializer"
+		}
+
+		# class name of a message type, or of an interface it implements, to
+		# the name of its serializer, unbound types use default-serializer
+		serialization-bindings {
+		}
+
+		default-serializer = json
+
+		router {
+			type-mapping {
+				round-robin-pool = "akka.routing.round-robin-pool"
+				tail-chopping-pool = "akka.routing.tail-chopping-pool"
+				work-pulling-pool = "akka.routing.work-pulling-pool"
+				affinity-pool = "akka.routing.affinity-pool"
+			}
+		}
+
+		debug {
+			autoreceive = off
+			lifecycle = off
+			unhandled = off
+			event-stream = off
+			# allows ActorSystem DumpMailbox to peek into the mailboxes
+			mailbox-dump = off
+
+			deadlock-detection = off
+			deadlock-detection-interval = 1s
+			deadlock-detection-threshold = 5s
+		}
+	}
+}
+`
+
+func ReferenceConfig() *configuration.Config {
+	return configuration.ParseString(referenceConfig)
+}
@@ -51,26 +51,27 @@ func (p *RootActorPath) Root() (root *RootActorPath) {
 }
 
 func (p *RootActorPath) CompareTo(other ActorPath) int {
-	return 0
+	return compareActorPaths(p, other)
 }
 
 func (p *RootActorPath) ToSerializationFormat() string {
-	return ""
+	return p.ToSerializationFormatWithAddress(p.address)
 }
 
+// ToSerializationFormatWithAddress is ToStringWithAddress, a root path has
+// no uid to append
 func (p *RootActorPath) ToSerializationFormatWithAddress(address Address) string {
-	return ""
+	return p.ToStringWithAddress(address)
 }
 
+// ToStringWithAddress renders the path with address, unless the path has a
+// host and port of its own
 func (p *RootActorPath) ToStringWithAddress(address Address) string {
-	if len(p.address.host) > 0 && p.address.port > 0 {
-		return p.address.String() + p.name
-	}
-	return address.String() + p.name
+	return renderedAddress(p.address, address).String() + p.name
 }
 
 func (p *RootActorPath) ToStringWithoutAddress() string {
-	return ""
+	return p.name
 }
 
 func (p *RootActorPath) Child(child string) (path ActorPath, err error) {
@@ -1,7 +1,7 @@
 package akka
 
 type RouterConfig interface {
-	CreateRouter(system ActorSystem)
+	CreateRoutingLogic(system ActorSystem) RoutingLogic
 	RouterDispatcher() string
 	IsManagementMessage(msg interface{}) bool
 	RoutingLogicController(routingLogic RoutingLogic) Props
@@ -10,11 +10,17 @@ type RouterConfig interface {
 	WithFallback(other RouterConfig) RouterConfig
 }
 
+// Pool is a RouterConfig whose routees are created and supervised by the router
+type Pool interface {
+	RouterConfig
+	NrOfInstances() int
+}
+
 type NoRouter struct {
 }
 
-func (p NoRouter) CreateRouter(system ActorSystem) {
-	return
+func (p NoRouter) CreateRoutingLogic(system ActorSystem) RoutingLogic {
+	return nil
 }
 
 func (p NoRouter) RouterDispatcher() string {
@@ -38,5 +44,5 @@ func (p NoRouter) VerifyConfig(path ActorPath) (err error) {
 }
 
 func (p NoRouter) WithFallback(other RouterConfig) RouterConfig {
-	return nil
+	return other
 }
@@ -0,0 +1,207 @@
+package routing
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+
+	. "github.com/go-akka/akka"
+	"github.com/go-akka/akka/dispatch"
+	"github.com/go-akka/akka/pkg/class_loader"
+	"github.com/go-akka/configuration"
+)
+
+func init() {
+	class_loader.Default.Register((*AffinityPool)(nil), "akka.routing.affinity-pool")
+}
+
+var (
+	_ RoutingLogic = (*AffinityRoutingLogic)(nil)
+	_ Pool         = (*AffinityPool)(nil)
+)
+
+const (
+	DefaultAffinityMaxKeys = 10000
+)
+
+type AffinityKeyed interface {
+	AffinityKey() interface{}
+}
+
+type AffinityKeyExtractor func(message interface{}) (key interface{}, ok bool)
+
+type affinityEntry struct {
+	key    interface{}
+	routee Routee
+}
+
+// AffinityRoutingLogic routes messages with the same affinity key to the same
+// routee, a new key is assigned to the routee holding the fewest keys and the
+// least recently used key is evicted once maxKeys are tracked
+type AffinityRoutingLogic struct {
+	maxKeys   int
+	extractor AffinityKeyExtractor
+
+	entries map[interface{}]*list.Element
+	lru     *list.List
+	loads   map[Routee]int
+	locker  sync.Mutex
+
+	next uint64
+}
+
+func NewAffinityRoutingLogic(maxKeys int, extractor AffinityKeyExtractor) *AffinityRoutingLogic {
+	if maxKeys <= 0 {
+		maxKeys = DefaultAffinityMaxKeys
+	}
+
+	if extractor == nil {
+		extractor = defaultAffinityKeyExtractor
+	}
+
+	return &AffinityRoutingLogic{
+		maxKeys:   maxKeys,
+		extractor: extractor,
+		entries:   make(map[interface{}]*list.Element),
+		lru:       list.New(),
+		loads:     make(map[Routee]int),
+	}
+}
+
+func defaultAffinityKeyExtractor(message interface{}) (key interface{}, ok bool) {
+	if keyed, isKeyed := message.(AffinityKeyed); isKeyed {
+		return keyed.AffinityKey(), true
+	}
+	return
+}
+
+func (p *AffinityRoutingLogic) Select(message interface{}, routees ...Routee) Routee {
+	if len(routees) == 0 {
+		return NoRoutee{}
+	}
+
+	key, ok := p.extractor(message)
+	if !ok || key == nil {
+		return routees[atomic.AddUint64(&p.next, 1)%uint64(len(routees))]
+	}
+
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	if elem, exist := p.entries[key]; exist {
+		entry := elem.Value.(*affinityEntry)
+		if containsRoutee(routees, entry.routee) {
+			p.lru.MoveToFront(elem)
+			return entry.routee
+		}
+		p.removeElement(elem)
+	}
+
+	routee := p.leastLoaded(routees)
+
+	p.entries[key] = p.lru.PushFront(&affinityEntry{key: key, routee: routee})
+	p.loads[routee]++
+
+	for p.lru.Len() > p.maxKeys {
+		p.removeElement(p.lru.Back())
+	}
+
+	return routee
+}
+
+func (p *AffinityRoutingLogic) NumberOfKeys() int {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return p.lru.Len()
+}
+
+func (p *AffinityRoutingLogic) leastLoaded(routees []Routee) Routee {
+	selected := routees[0]
+	for _, routee := range routees[1:] {
+		if p.loads[routee] < p.loads[selected] {
+			selected = routee
+		}
+	}
+	return selected
+}
+
+func (p *AffinityRoutingLogic) removeElement(elem *list.Element) {
+	entry := p.lru.Remove(elem).(*affinityEntry)
+	delete(p.entries, entry.key)
+
+	if p.loads[entry.routee]--; p.loads[entry.routee] <= 0 {
+		delete(p.loads, entry.routee)
+	}
+}
+
+func containsRoutee(routees []Routee, routee Routee) bool {
+	for _, r := range routees {
+		if r == routee {
+			return true
+		}
+	}
+	return false
+}
+
+// AffinityPool routes the messages with the same affinity key to the same
+// routee, the keys are taken by the extractor, from the config the keys of
+// AffinityKeyed messages
+type AffinityPool struct {
+	nrOfInstances    int
+	maxKeys          int
+	extractor        AffinityKeyExtractor
+	routerDispatcher string
+}
+
+func NewAffinityPool(nrOfInstances, maxKeys int, extractor AffinityKeyExtractor) *AffinityPool {
+	return &AffinityPool{
+		nrOfInstances:    nrOfInstances,
+		maxKeys:          maxKeys,
+		extractor:        extractor,
+		routerDispatcher: dispatch.DefaultDispatcherId,
+	}
+}
+
+func (p *AffinityPool) Construct(settings *Settings, config *configuration.Config) (err error) {
+	p.nrOfInstances = int(config.GetInt32("nr-of-instances", 1))
+	p.maxKeys = int(config.GetInt32("affinity-router.max-keys", DefaultAffinityMaxKeys))
+	p.routerDispatcher = config.GetString("router-dispatcher", dispatch.DefaultDispatcherId)
+	return
+}
+
+func (p *AffinityPool) NrOfInstances() int {
+	return p.nrOfInstances
+}
+
+func (p *AffinityPool) MaxKeys() int {
+	return p.maxKeys
+}
+
+func (p *AffinityPool) CreateRoutingLogic(system ActorSystem) RoutingLogic {
+	return NewAffinityRoutingLogic(p.maxKeys, p.extractor)
+}
+
+func (p *AffinityPool) RouterDispatcher() string {
+	return p.routerDispatcher
+}
+
+func (p *AffinityPool) IsManagementMessage(msg interface{}) bool {
+	return false
+}
+
+func (p *AffinityPool) RoutingLogicController(routingLogic RoutingLogic) Props {
+	return nil
+}
+
+func (p *AffinityPool) StopRouterWhenAllRouteesRemoved() bool {
+	return true
+}
+
+func (p *AffinityPool) VerifyConfig(path ActorPath) (err error) {
+	return
+}
+
+func (p *AffinityPool) WithFallback(other RouterConfig) RouterConfig {
+	return p
+}
@@ -0,0 +1,90 @@
+package routing
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/go-akka/akka"
+)
+
+type testRoutee struct {
+	name string
+}
+
+func (p *testRoutee) Send(message interface{}, sender ActorRef) {
+}
+
+type keyedMessage struct {
+	key string
+}
+
+func (p keyedMessage) AffinityKey() interface{} {
+	return p.key
+}
+
+func TestAffinityRoutingLogicIsSticky(t *testing.T) {
+	routees := []Routee{&testRoutee{"a"}, &testRoutee{"b"}, &testRoutee{"c"}}
+	logic := NewAffinityRoutingLogic(0, nil)
+
+	assigned := make(map[string]Routee)
+	for i := 0; i < 3000; i++ {
+		key := fmt.Sprintf("key-%d", i%30)
+		routee := logic.Select(keyedMessage{key}, routees...)
+
+		if first, exist := assigned[key]; !exist {
+			assigned[key] = routee
+		} else if first != routee {
+			t.Fatalf("message %d with key %s was routed to %s, expected %s", i, key, routee.(*testRoutee).name, first.(*testRoutee).name)
+		}
+	}
+
+	counts := make(map[Routee]int)
+	for _, routee := range assigned {
+		counts[routee]++
+	}
+
+	for _, routee := range routees {
+		if counts[routee] != 10 {
+			t.Fatalf("new keys should be spread by load, routee %s got %d keys", routee.(*testRoutee).name, counts[routee])
+		}
+	}
+}
+
+func TestAffinityRoutingLogicEvictsLeastRecentlyUsedKey(t *testing.T) {
+	routees := []Routee{&testRoutee{"a"}, &testRoutee{"b"}}
+	logic := NewAffinityRoutingLogic(2, nil)
+
+	logic.Select(keyedMessage{"x"}, routees...)
+	logic.Select(keyedMessage{"y"}, routees...)
+	logic.Select(keyedMessage{"x"}, routees...)
+	logic.Select(keyedMessage{"z"}, routees...)
+
+	if n := logic.NumberOfKeys(); n != 2 {
+		t.Fatalf("expected 2 tracked keys, but got %d", n)
+	}
+
+	logic.locker.Lock()
+	_, hasX := logic.entries["x"]
+	_, hasY := logic.entries["y"]
+	logic.locker.Unlock()
+
+	if !hasX || hasY {
+		t.Fatalf("least recently used key y should be evicted, x: %v, y: %v", hasX, hasY)
+	}
+}
+
+func TestAffinityRoutingLogicReassignsRemovedRoutee(t *testing.T) {
+	a, b := &testRoutee{"a"}, &testRoutee{"b"}
+	logic := NewAffinityRoutingLogic(0, nil)
+
+	first := logic.Select(keyedMessage{"k"}, a, b)
+
+	var remaining Routee = a
+	if first == a {
+		remaining = b
+	}
+
+	if routee := logic.Select(keyedMessage{"k"}, remaining); routee != remaining {
+		t.Fatalf("key should move to a live routee after its routee was removed")
+	}
+}
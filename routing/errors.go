@@ -0,0 +1,10 @@
+package routing
+
+import (
+	"errors"
+)
+
+var (
+	ErrNoRoutingLogic      = errors.New("router should have a routing logic")
+	ErrTailChoppingTimeout = errors.New("no routee replied within the tail-chopping timeout")
+)
@@ -0,0 +1,86 @@
+package routing
+
+import (
+	"sync/atomic"
+
+	. "github.com/go-akka/akka"
+	"github.com/go-akka/akka/dispatch"
+	"github.com/go-akka/akka/pkg/class_loader"
+	"github.com/go-akka/configuration"
+)
+
+func init() {
+	class_loader.Default.Register((*RoundRobinPool)(nil), "akka.routing.round-robin-pool")
+}
+
+var (
+	_ RoutingLogic = (*RoundRobinRoutingLogic)(nil)
+	_ Pool         = (*RoundRobinPool)(nil)
+)
+
+type RoundRobinRoutingLogic struct {
+	next uint64
+}
+
+func NewRoundRobinRoutingLogic() *RoundRobinRoutingLogic {
+	return &RoundRobinRoutingLogic{}
+}
+
+func (p *RoundRobinRoutingLogic) Select(message interface{}, routees ...Routee) Routee {
+	if len(routees) == 0 {
+		return NoRoutee{}
+	}
+
+	size := uint64(len(routees))
+	return routees[(atomic.AddUint64(&p.next, 1)-1)%size]
+}
+
+type RoundRobinPool struct {
+	nrOfInstances    int
+	routerDispatcher string
+}
+
+func NewRoundRobinPool(nrOfInstances int) *RoundRobinPool {
+	return &RoundRobinPool{
+		nrOfInstances:    nrOfInstances,
+		routerDispatcher: dispatch.DefaultDispatcherId,
+	}
+}
+
+func (p *RoundRobinPool) Construct(settings *Settings, config *configuration.Config) (err error) {
+	p.nrOfInstances = int(config.GetInt32("nr-of-instances", 1))
+	p.routerDispatcher = config.GetString("router-dispatcher", dispatch.DefaultDispatcherId)
+	return
+}
+
+func (p *RoundRobinPool) NrOfInstances() int {
+	return p.nrOfInstances
+}
+
+func (p *RoundRobinPool) CreateRoutingLogic(system ActorSystem) RoutingLogic {
+	return NewRoundRobinRoutingLogic()
+}
+
+func (p *RoundRobinPool) RouterDispatcher() string {
+	return p.routerDispatcher
+}
+
+func (p *RoundRobinPool) IsManagementMessage(msg interface{}) bool {
+	return false
+}
+
+func (p *RoundRobinPool) RoutingLogicController(routingLogic RoutingLogic) Props {
+	return nil
+}
+
+func (p *RoundRobinPool) StopRouterWhenAllRouteesRemoved() bool {
+	return true
+}
+
+func (p *RoundRobinPool) VerifyConfig(path ActorPath) (err error) {
+	return
+}
+
+func (p *RoundRobinPool) WithFallback(other RouterConfig) RouterConfig {
+	return p
+}
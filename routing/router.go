@@ -10,12 +10,81 @@ type NoRoutee struct {
 func (p NoRoutee) Send(message interface{}, sender ActorRef) {
 }
 
+type ActorRefRoutee struct {
+	Ref ActorRef
+}
+
+func (p ActorRefRoutee) Send(message interface{}, sender ActorRef) {
+	p.Ref.Tell(message, sender)
+}
+
+type ActorSelectionRoutee struct {
+	Selection *ActorSelection
+}
+
+func (p ActorSelectionRoutee) Send(message interface{}, sender ActorRef) {
+	p.Selection.Tell(message, sender)
+}
+
 type Router struct {
+	logic   RoutingLogic
+	routees []Routee
 }
 
 func NewRouter(logic RoutingLogic, routees ...Routee) (router Router, err error) {
+	if logic == nil {
+		err = ErrNoRoutingLogic
+		return
+	}
+
+	router = Router{logic: logic, routees: routees}
 	return
 }
 
-func (p Router) AddRoutee(sel ActorSelection) {
+func (p Router) Logic() RoutingLogic {
+	return p.logic
+}
+
+func (p Router) Routees() []Routee {
+	return p.routees
+}
+
+func (p Router) Route(message interface{}, sender ActorRef) {
+	if len(p.routees) == 0 {
+		return
+	}
+
+	routee := p.logic.Select(message, p.routees...)
+	if routee == nil {
+		return
+	}
+
+	routee.Send(message, sender)
+}
+
+func (p Router) WithRoutees(routees ...Routee) Router {
+	return Router{logic: p.logic, routees: routees}
+}
+
+func (p Router) AddRoutee(sel ActorSelection) Router {
+	return p.AddRoutees(ActorSelectionRoutee{Selection: &sel})
+}
+
+func (p Router) AddRoutees(routees ...Routee) Router {
+	newRoutees := make([]Routee, 0, len(p.routees)+len(routees))
+	newRoutees = append(newRoutees, p.routees...)
+	newRoutees = append(newRoutees, routees...)
+
+	return Router{logic: p.logic, routees: newRoutees}
+}
+
+func (p Router) RemoveRoutee(routee Routee) Router {
+	var newRoutees []Routee
+	for _, r := range p.routees {
+		if r != routee {
+			newRoutees = append(newRoutees, r)
+		}
+	}
+
+	return Router{logic: p.logic, routees: newRoutees}
 }
@@ -0,0 +1,170 @@
+package routing
+
+import (
+	"math/rand"
+	"time"
+
+	. "github.com/go-akka/akka"
+	"github.com/go-akka/akka/dispatch"
+	"github.com/go-akka/akka/pattern"
+	"github.com/go-akka/akka/pkg/class_loader"
+	"github.com/go-akka/configuration"
+)
+
+func init() {
+	class_loader.Default.Register((*TailChoppingPool)(nil), "akka.routing.tail-chopping-pool")
+}
+
+var (
+	_ RouterAwareRoutingLogic = (*TailChoppingRoutingLogic)(nil)
+	_ Routee                  = TailChoppingRoutees{}
+	_ Pool                    = (*TailChoppingPool)(nil)
+)
+
+// RouterAwareRoutingLogic is a RoutingLogic that tells on behalf of the
+// router, the router hands it its self before it routes the first message
+type RouterAwareRoutingLogic interface {
+	RoutingLogic
+	SetRouter(router ActorRef)
+}
+
+// TailChoppingRoutingLogic asks the routees of every message in a new random
+// order, as Akka does, so the first routee is not asked first every time
+type TailChoppingRoutingLogic struct {
+	scheduler Scheduler
+	within    time.Duration
+	interval  time.Duration
+	router    ActorRef
+}
+
+func NewTailChoppingRoutingLogic(scheduler Scheduler, within, interval time.Duration) *TailChoppingRoutingLogic {
+	return &TailChoppingRoutingLogic{
+		scheduler: scheduler,
+		within:    within,
+		interval:  interval,
+	}
+}
+
+func (p *TailChoppingRoutingLogic) Select(message interface{}, routees ...Routee) Routee {
+	if len(routees) == 0 {
+		return NoRoutee{}
+	}
+
+	shuffled := make([]Routee, len(routees))
+	copy(shuffled, routees)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	return TailChoppingRoutees{
+		scheduler: p.scheduler,
+		within:    p.within,
+		interval:  p.interval,
+		router:    p.router,
+		routees:   shuffled,
+	}
+}
+
+func (p *TailChoppingRoutingLogic) SetRouter(router ActorRef) {
+	p.router = router
+}
+
+// TailChoppingRoutees asks the routees in order, the next one every interval,
+// until one of them replies. The first reply is told to the sender from the
+// router, if none arrives within the timeout the sender is told
+// ErrTailChoppingTimeout
+type TailChoppingRoutees struct {
+	scheduler Scheduler
+	within    time.Duration
+	interval  time.Duration
+	router    ActorRef
+	routees   []Routee
+}
+
+func (p TailChoppingRoutees) Send(message interface{}, sender ActorRef) {
+	promise := pattern.NewPromiseActorRef()
+
+	for i, routee := range p.routees {
+		routee := routee
+		p.scheduler.Advanced().ScheduleOnce(time.Duration(i)*p.interval, ActionFunc(func() {
+			if !promise.IsCompleted() {
+				routee.Send(message, promise)
+			}
+		}), nil)
+	}
+
+	go func() {
+		reply, err := promise.Await(p.within)
+		if err != nil {
+			reply = ErrTailChoppingTimeout
+		}
+
+		if sender != nil {
+			sender.Tell(reply, p.router)
+		}
+	}()
+}
+
+type TailChoppingPool struct {
+	nrOfInstances    int
+	within           time.Duration
+	interval         time.Duration
+	routerDispatcher string
+}
+
+func NewTailChoppingPool(nrOfInstances int, within, interval time.Duration) *TailChoppingPool {
+	return &TailChoppingPool{
+		nrOfInstances:    nrOfInstances,
+		within:           within,
+		interval:         interval,
+		routerDispatcher: dispatch.DefaultDispatcherId,
+	}
+}
+
+func (p *TailChoppingPool) Construct(settings *Settings, config *configuration.Config) (err error) {
+	p.nrOfInstances = int(config.GetInt32("nr-of-instances", 1))
+	p.within = config.GetTimeDuration("within", 5*time.Second)
+	p.interval = config.GetTimeDuration("tail-chopping-router.interval", 10*time.Millisecond)
+	p.routerDispatcher = config.GetString("router-dispatcher", dispatch.DefaultDispatcherId)
+	return
+}
+
+func (p *TailChoppingPool) NrOfInstances() int {
+	return p.nrOfInstances
+}
+
+func (p *TailChoppingPool) Within() time.Duration {
+	return p.within
+}
+
+func (p *TailChoppingPool) Interval() time.Duration {
+	return p.interval
+}
+
+func (p *TailChoppingPool) CreateRoutingLogic(system ActorSystem) RoutingLogic {
+	return NewTailChoppingRoutingLogic(system.Scheduler(), p.within, p.interval)
+}
+
+func (p *TailChoppingPool) RouterDispatcher() string {
+	return p.routerDispatcher
+}
+
+func (p *TailChoppingPool) IsManagementMessage(msg interface{}) bool {
+	return false
+}
+
+func (p *TailChoppingPool) RoutingLogicController(routingLogic RoutingLogic) Props {
+	return nil
+}
+
+func (p *TailChoppingPool) StopRouterWhenAllRouteesRemoved() bool {
+	return true
+}
+
+func (p *TailChoppingPool) VerifyConfig(path ActorPath) (err error) {
+	return
+}
+
+func (p *TailChoppingPool) WithFallback(other RouterConfig) RouterConfig {
+	return p
+}
@@ -0,0 +1,37 @@
+package routing
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/go-akka/akka"
+)
+
+func TestTailChoppingShufflesTheRouteesOfEveryMessage(t *testing.T) {
+	routees := []Routee{&testRoutee{"a"}, &testRoutee{"b"}, &testRoutee{"c"}}
+	logic := NewTailChoppingRoutingLogic(nil, time.Second, 10*time.Millisecond)
+
+	first := map[Routee]bool{}
+	for i := 0; i < 100; i++ {
+		selected := logic.Select("question", routees...).(TailChoppingRoutees)
+
+		asked := map[Routee]bool{}
+		for _, routee := range selected.routees {
+			asked[routee] = true
+		}
+
+		if len(selected.routees) != len(routees) || len(asked) != len(routees) {
+			t.Fatalf("message %d should ask every routee once, but got %v", i, selected.routees)
+		}
+
+		first[selected.routees[0]] = true
+	}
+
+	if len(first) != len(routees) {
+		t.Fatalf("every routee should be asked first for some message, but only %d of %d were", len(first), len(routees))
+	}
+
+	if routees[0].(*testRoutee).name != "a" || routees[1].(*testRoutee).name != "b" || routees[2].(*testRoutee).name != "c" {
+		t.Fatalf("the routees of the router should not be shuffled in place")
+	}
+}
@@ -0,0 +1,182 @@
+package routing
+
+import (
+	"sync"
+
+	. "github.com/go-akka/akka"
+	"github.com/go-akka/akka/dispatch"
+	"github.com/go-akka/akka/pkg/class_loader"
+	"github.com/go-akka/configuration"
+)
+
+func init() {
+	class_loader.Default.Register((*WorkPullingPool)(nil), "akka.routing.work-pulling-pool")
+}
+
+var (
+	_ PullingRoutingLogic = (*WorkPullingRoutingLogic)(nil)
+	_ Routee              = workPullingRoutee{}
+	_ Pool                = (*WorkPullingPool)(nil)
+)
+
+// WorkReady is told by a routee of a work pulling pool to its parent, the
+// router, each time it can take the next message: once started and after
+// every message it handled
+type WorkReady struct{}
+
+// PullingRoutingLogic is a RoutingLogic whose routees ask for work, the router
+// reports the routees that are ready and the ones that are gone, and drains
+// the messages still buffered once it stops
+type PullingRoutingLogic interface {
+	RoutingLogic
+	RouteeReady(routee Routee)
+	RouteeRemoved(routee Routee)
+	DrainWork() []Envelope
+}
+
+type pulledWork struct {
+	message interface{}
+	sender  ActorRef
+}
+
+// WorkPullingRoutingLogic buffers the messages and hands each one to the
+// routee that asked for work first, a busy routee never gets a backlog
+type WorkPullingRoutingLogic struct {
+	work   []pulledWork
+	ready  []Routee
+	locker sync.Mutex
+}
+
+func NewWorkPullingRoutingLogic() *WorkPullingRoutingLogic {
+	return &WorkPullingRoutingLogic{}
+}
+
+func (p *WorkPullingRoutingLogic) Select(message interface{}, routees ...Routee) Routee {
+	return workPullingRoutee{logic: p}
+}
+
+func (p *WorkPullingRoutingLogic) RouteeReady(routee Routee) {
+	p.locker.Lock()
+	if len(p.work) == 0 {
+		p.ready = append(p.ready, routee)
+		p.locker.Unlock()
+		return
+	}
+
+	next := p.work[0]
+	p.work[0] = pulledWork{}
+	p.work = p.work[1:]
+	p.locker.Unlock()
+
+	routee.Send(next.message, next.sender)
+}
+
+func (p *WorkPullingRoutingLogic) RouteeRemoved(routee Routee) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	var ready []Routee
+	for _, r := range p.ready {
+		if r != routee {
+			ready = append(ready, r)
+		}
+	}
+	p.ready = ready
+}
+
+// NumberOfBufferedMessages are the messages waiting for a ready routee
+func (p *WorkPullingRoutingLogic) NumberOfBufferedMessages() int {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return len(p.work)
+}
+
+// DrainWork removes the buffered messages and returns them in the order they
+// were routed
+func (p *WorkPullingRoutingLogic) DrainWork() (envelopes []Envelope) {
+	p.locker.Lock()
+	work := p.work
+	p.work = nil
+	p.locker.Unlock()
+
+	for _, w := range work {
+		envelopes = append(envelopes, Envelope{Message: w.message, Sender: w.sender})
+	}
+	return
+}
+
+func (p *WorkPullingRoutingLogic) push(message interface{}, sender ActorRef) {
+	p.locker.Lock()
+	if len(p.ready) == 0 {
+		p.work = append(p.work, pulledWork{message: message, sender: sender})
+		p.locker.Unlock()
+		return
+	}
+
+	routee := p.ready[0]
+	p.ready = p.ready[1:]
+	p.locker.Unlock()
+
+	routee.Send(message, sender)
+}
+
+type workPullingRoutee struct {
+	logic *WorkPullingRoutingLogic
+}
+
+func (p workPullingRoutee) Send(message interface{}, sender ActorRef) {
+	p.logic.push(message, sender)
+}
+
+// WorkPullingPool routes each message to the next routee telling WorkReady,
+// the messages are buffered by the router while every routee is busy
+type WorkPullingPool struct {
+	nrOfInstances    int
+	routerDispatcher string
+}
+
+func NewWorkPullingPool(nrOfInstances int) *WorkPullingPool {
+	return &WorkPullingPool{
+		nrOfInstances:    nrOfInstances,
+		routerDispatcher: dispatch.DefaultDispatcherId,
+	}
+}
+
+func (p *WorkPullingPool) Construct(settings *Settings, config *configuration.Config) (err error) {
+	p.nrOfInstances = int(config.GetInt32("nr-of-instances", 1))
+	p.routerDispatcher = config.GetString("router-dispatcher", dispatch.DefaultDispatcherId)
+	return
+}
+
+func (p *WorkPullingPool) NrOfInstances() int {
+	return p.nrOfInstances
+}
+
+func (p *WorkPullingPool) CreateRoutingLogic(system ActorSystem) RoutingLogic {
+	return NewWorkPullingRoutingLogic()
+}
+
+func (p *WorkPullingPool) RouterDispatcher() string {
+	return p.routerDispatcher
+}
+
+func (p *WorkPullingPool) IsManagementMessage(msg interface{}) bool {
+	return false
+}
+
+func (p *WorkPullingPool) RoutingLogicController(routingLogic RoutingLogic) Props {
+	return nil
+}
+
+func (p *WorkPullingPool) StopRouterWhenAllRouteesRemoved() bool {
+	return true
+}
+
+func (p *WorkPullingPool) VerifyConfig(path ActorPath) (err error) {
+	return
+}
+
+func (p *WorkPullingPool) WithFallback(other RouterConfig) RouterConfig {
+	return p
+}
@@ -7,3 +7,9 @@ type Runnable interface {
 type Action interface {
 	Action()
 }
+
+type ActionFunc func()
+
+func (p ActionFunc) Action() {
+	p()
+}
@@ -32,8 +32,10 @@ type ActionScheduler interface {
 
 type AdvancedScheduler interface {
 	ActionScheduler
+	ScheduleOnceWithJitter(delay time.Duration, jitter time.Duration, action Action, cancelable Cancelable)
 }
 
 type Scheduler interface {
+	TellScheduler
 	Advanced() AdvancedScheduler
 }
@@ -6,7 +6,7 @@ import (
 
 type Serializer interface {
 	Identifier() int
-	ToBinary(v interface{}) []byte
+	ToBinary(v interface{}) (data []byte, err error)
 
 	// Returns whether this serializer needs a manifest in the fromBinary method
 	IncludeManifest() bool
@@ -0,0 +1,12 @@
+package serialization
+
+import (
+	"errors"
+)
+
+var (
+	ErrNotSerializer     = errors.New("configured serializer class does not implement akka.Serializer")
+	ErrUnknownSerializer = errors.New("serialization binding refers to an unknown serializer")
+	ErrUnknownManifest   = errors.New("manifest does not name a registered type")
+	ErrNilMessage        = errors.New("nil message can not be serialized")
+)
@@ -0,0 +1,52 @@
+package serialization
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/pkg/class_loader"
+)
+
+func init() {
+	class_loader.Default.Register((*GobSerializer)(nil), "akka.serialization.gob-serializer")
+}
+
+const (
+	GobSerializerId = 2
+)
+
+var (
+	_ akka.Serializer = (*GobSerializer)(nil)
+)
+
+// GobSerializer keeps the exported fields of a message, gob can not decode
+// into an empty interface without registered types, so it needs the manifest
+type GobSerializer struct{}
+
+func (p *GobSerializer) Identifier() int {
+	return GobSerializerId
+}
+
+func (p *GobSerializer) ToBinary(v interface{}) (data []byte, err error) {
+	var buf bytes.Buffer
+	if err = gob.NewEncoder(&buf).Encode(v); err != nil {
+		return
+	}
+	data = buf.Bytes()
+	return
+}
+
+func (p *GobSerializer) IncludeManifest() bool {
+	return true
+}
+
+func (p *GobSerializer) FromBinary(data []byte) (v interface{}, err error) {
+	err = gob.NewDecoder(bytes.NewReader(data)).Decode(&v)
+	return
+}
+
+func (p *GobSerializer) FromBinaryWithType(data []byte, typ reflect.Type) (v interface{}, err error) {
+	return decodeInto(typ, func(ptr interface{}) error { return gob.NewDecoder(bytes.NewReader(data)).Decode(ptr) })
+}
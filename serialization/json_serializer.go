@@ -0,0 +1,66 @@
+package serialization
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/pkg/class_loader"
+)
+
+func init() {
+	class_loader.Default.Register((*JSONSerializer)(nil), "akka.serialization.json-serializer")
+}
+
+const (
+	JSONSerializerId = 1
+)
+
+var (
+	_ akka.Serializer = (*JSONSerializer)(nil)
+)
+
+// JSONSerializer only keeps the exported fields of a message
+type JSONSerializer struct{}
+
+func (p *JSONSerializer) Identifier() int {
+	return JSONSerializerId
+}
+
+func (p *JSONSerializer) ToBinary(v interface{}) (data []byte, err error) {
+	return json.Marshal(v)
+}
+
+func (p *JSONSerializer) IncludeManifest() bool {
+	return true
+}
+
+func (p *JSONSerializer) FromBinary(data []byte) (v interface{}, err error) {
+	err = json.Unmarshal(data, &v)
+	return
+}
+
+func (p *JSONSerializer) FromBinaryWithType(data []byte, typ reflect.Type) (v interface{}, err error) {
+	return decodeInto(typ, func(ptr interface{}) error { return json.Unmarshal(data, ptr) })
+}
+
+// decodeInto decodes into a new value of typ, typ may be a pointer type
+func decodeInto(typ reflect.Type, decode func(ptr interface{}) error) (v interface{}, err error) {
+	elemType := typ
+	if typ.Kind() == reflect.Ptr {
+		elemType = typ.Elem()
+	}
+
+	ptr := reflect.New(elemType)
+	if err = decode(ptr.Interface()); err != nil {
+		return
+	}
+
+	if typ.Kind() == reflect.Ptr {
+		v = ptr.Interface()
+	} else {
+		v = ptr.Elem().Interface()
+	}
+
+	return
+}
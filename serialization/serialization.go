@@ -0,0 +1,209 @@
+package serialization
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/pkg/class_loader"
+	"github.com/go-akka/akka/pkg/dynamic_access"
+)
+
+type binding struct {
+	typ        reflect.Type
+	serializer akka.Serializer
+}
+
+// Serialization resolves the serializer of a message by its type from
+// akka.actor.serializers and akka.actor.serialization-bindings. A binding of
+// an interface type applies to every message implementing it, the messages
+// without a binding use akka.actor.default-serializer
+type Serialization struct {
+	classLoader class_loader.ClassLoader
+
+	serializers       map[string]akka.Serializer
+	bindings          []binding
+	defaultSerializer akka.Serializer
+
+	cache sync.Map
+}
+
+func NewSerialization(settings *akka.Settings, classLoader class_loader.ClassLoader, dynamicAccess dynamic_access.DynamicAccess) (serialization *Serialization, err error) {
+	s := &Serialization{
+		classLoader: classLoader,
+		serializers: make(map[string]akka.Serializer),
+	}
+
+	config := settings.Config()
+
+	if serializersConfig := config.GetConfig("akka.actor.serializers"); !serializersConfig.IsEmpty() {
+		for name, value := range serializersConfig.Root().GetObject().Items() {
+			var ins interface{}
+			if ins, err = dynamicAccess.CreateInstanceByName(value.GetString()); err != nil {
+				return
+			}
+
+			serializer, ok := ins.(akka.Serializer)
+			if !ok {
+				err = fmt.Errorf("%s: %s", ErrNotSerializer, value.GetString())
+				return
+			}
+
+			s.serializers[strings.Trim(name, "\"")] = serializer
+		}
+	}
+
+	if bindingsConfig := config.GetConfig("akka.actor.serialization-bindings"); !bindingsConfig.IsEmpty() {
+		var classNames []string
+		items := bindingsConfig.Root().GetObject().Items()
+		for className := range items {
+			classNames = append(classNames, className)
+		}
+
+		// sorted, so the binding picked for a message implementing several
+		// bound interfaces does not depend on the map order
+		sort.Strings(classNames)
+
+		for _, className := range classNames {
+			name := strings.Trim(className, "\"")
+
+			typ, exist := classLoader.ClassNameOf(name)
+			if !exist {
+				err = fmt.Errorf("%s: %s", ErrUnknownManifest, name)
+				return
+			}
+
+			var serializer akka.Serializer
+			if serializer, err = s.serializerByName(items[className].GetString()); err != nil {
+				return
+			}
+
+			s.bindings = append(s.bindings, binding{typ: typ, serializer: serializer})
+		}
+	}
+
+	if s.defaultSerializer, err = s.serializerByName(config.GetString("akka.actor.default-serializer", "json")); err != nil {
+		return
+	}
+
+	serialization = s
+	return
+}
+
+func (p *Serialization) serializerByName(name string) (serializer akka.Serializer, err error) {
+	serializer, exist := p.serializers[name]
+	if !exist {
+		err = fmt.Errorf("%s: %s", ErrUnknownSerializer, name)
+	}
+	return
+}
+
+// SerializerFor returns the serializer bound to typ, the pointer and the value
+// type of a struct share the binding of the registered struct
+func (p *Serialization) SerializerFor(typ reflect.Type) akka.Serializer {
+	if cached, exist := p.cache.Load(typ); exist {
+		return cached.(akka.Serializer)
+	}
+
+	serializer := p.defaultSerializer
+
+	elemType := typ
+	if typ.Kind() == reflect.Ptr {
+		elemType = typ.Elem()
+	}
+
+	found := false
+	for _, b := range p.bindings {
+		if b.typ == elemType {
+			serializer, found = b.serializer, true
+			break
+		}
+	}
+
+	if !found {
+		for _, b := range p.bindings {
+			if b.typ.Kind() == reflect.Interface && typ.Implements(b.typ) {
+				serializer = b.serializer
+				break
+			}
+		}
+	}
+
+	p.cache.Store(typ, serializer)
+
+	return serializer
+}
+
+func (p *Serialization) FindSerializerFor(message interface{}) (serializer akka.Serializer, err error) {
+	if message == nil {
+		err = ErrNilMessage
+		return
+	}
+
+	serializer = p.SerializerFor(reflect.TypeOf(message))
+	return
+}
+
+// Manifest names the type of message for Deserialize, it is the package path
+// and name of the type, prefixed with * for pointers
+func (p *Serialization) Manifest(message interface{}) (manifest string, err error) {
+	if message == nil {
+		err = ErrNilMessage
+		return
+	}
+
+	typ := reflect.TypeOf(message)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+		manifest = "*"
+	}
+
+	manifest += typ.PkgPath() + "." + typ.Name()
+	return
+}
+
+func (p *Serialization) Serialize(message interface{}) (data []byte, err error) {
+	var serializer akka.Serializer
+	if serializer, err = p.FindSerializerFor(message); err != nil {
+		return
+	}
+
+	return serializer.ToBinary(message)
+}
+
+// SerializeAndDeserialize round trips message through its serializer, the
+// copy is decoded into the type of message, so it needs no manifest
+func (p *Serialization) SerializeAndDeserialize(message interface{}) (copy interface{}, err error) {
+	var serializer akka.Serializer
+	if serializer, err = p.FindSerializerFor(message); err != nil {
+		return
+	}
+
+	var data []byte
+	if data, err = serializer.ToBinary(message); err != nil {
+		return
+	}
+
+	return serializer.FromBinaryWithType(data, reflect.TypeOf(message))
+}
+
+// Deserialize decodes data into the type named by manifest, the type has to be
+// registered with the class loader
+func (p *Serialization) Deserialize(manifest string, data []byte) (message interface{}, err error) {
+	path := strings.TrimPrefix(manifest, "*")
+
+	typ, exist := p.classLoader.ClassPathOf(path)
+	if !exist {
+		err = fmt.Errorf("%s: %s", ErrUnknownManifest, manifest)
+		return
+	}
+
+	if len(path) != len(manifest) {
+		typ = reflect.PtrTo(typ)
+	}
+
+	return p.SerializerFor(typ).FromBinaryWithType(data, typ)
+}
@@ -0,0 +1,133 @@
+package serialization
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/pkg/class_loader"
+	"github.com/go-akka/akka/pkg/dynamic_access"
+	"github.com/go-akka/configuration"
+)
+
+type Greeting struct {
+	Who   string
+	Times int
+}
+
+type Position struct {
+	X, Y float64
+}
+
+type Tagged interface {
+	Tag() string
+}
+
+type Label struct {
+	Name string
+}
+
+func (p Label) Tag() string {
+	return p.Name
+}
+
+func newTestSerialization(t *testing.T, config string) *Serialization {
+	loader := class_loader.NewClassicClassLoader(class_loader.Default)
+	loader.Register((*Greeting)(nil), "serialization.greeting")
+	loader.Register((*Position)(nil), "serialization.position")
+	loader.Register((*Tagged)(nil), "serialization.tagged")
+	loader.Register((*Label)(nil), "serialization.label")
+
+	settings, err := akka.NewSettings("SerializationTest", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create settings failure: %s", err.Error())
+	}
+
+	serialization, err := NewSerialization(settings, loader, dynamic_access.NewReflectiveDynamicAccess(loader))
+	if err != nil {
+		t.Fatalf("create serialization failure: %s", err.Error())
+	}
+
+	return serialization
+}
+
+func roundTrip(t *testing.T, serialization *Serialization, message interface{}) interface{} {
+	manifest, err := serialization.Manifest(message)
+	if err != nil {
+		t.Fatalf("manifest of %#v failure: %s", message, err.Error())
+	}
+
+	data, err := serialization.Serialize(message)
+	if err != nil {
+		t.Fatalf("serialize %#v failure: %s", message, err.Error())
+	}
+
+	decoded, err := serialization.Deserialize(manifest, data)
+	if err != nil {
+		t.Fatalf("deserialize %#v failure: %s", message, err.Error())
+	}
+
+	return decoded
+}
+
+func TestSerializationRoundTrip(t *testing.T) {
+	serialization := newTestSerialization(t, `
+akka.actor.serialization-bindings {
+	"serialization.position" = gob
+	"serialization.tagged" = gob
+}`)
+
+	messages := []interface{}{
+		&Greeting{Who: "akka", Times: 3},
+		Greeting{Who: "go", Times: 1},
+		&Position{X: 1.5, Y: -2},
+		Label{Name: "blue"},
+	}
+
+	for _, message := range messages {
+		if decoded := roundTrip(t, serialization, message); !reflect.DeepEqual(decoded, message) {
+			t.Fatalf("round trip of %#v should be equal, but got %#v", message, decoded)
+		}
+	}
+
+	expected := map[interface{}]int{
+		&Greeting{}: JSONSerializerId,
+		Position{}:  GobSerializerId,
+		Label{}:     GobSerializerId,
+	}
+
+	for message, id := range expected {
+		serializer, err := serialization.FindSerializerFor(message)
+		if err != nil {
+			t.Fatalf("find serializer for %#v failure: %s", message, err.Error())
+		}
+
+		if serializer.Identifier() != id {
+			t.Fatalf("serializer of %T should be %d, but got %d", message, id, serializer.Identifier())
+		}
+	}
+}
+
+func TestSerializationErrors(t *testing.T) {
+	serialization := newTestSerialization(t, "")
+
+	if _, err := serialization.Serialize(nil); err != ErrNilMessage {
+		t.Fatalf("serializing nil should fail with ErrNilMessage, but got %v", err)
+	}
+
+	if _, err := serialization.Deserialize("unknown.Message", []byte("{}")); err == nil {
+		t.Fatalf("deserializing an unknown manifest should fail")
+	}
+
+	settings, err := akka.NewSettings("SerializationTest", configuration.ParseString(`akka.actor.serialization-bindings { "serialization.greeting" = missing }`))
+	if err != nil {
+		t.Fatalf("create settings failure: %s", err.Error())
+	}
+
+	loader := class_loader.NewClassicClassLoader(class_loader.Default)
+	loader.Register((*Greeting)(nil), "serialization.greeting")
+
+	if _, err = NewSerialization(settings, loader, dynamic_access.NewReflectiveDynamicAccess(loader)); err == nil {
+		t.Fatalf("binding to an unknown serializer should fail")
+	}
+}
@@ -1,8 +1,11 @@
 package akka
 
 import (
-	"github.com/go-akka/configuration"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/go-akka/configuration"
 )
 
 type Settings struct {
@@ -20,47 +23,142 @@ type Settings struct {
 	LoggingFilter           string
 	SchedulerClass          string
 	StdoutLogLevel          string
+	LogCallerInfo           bool
 	LoggerStartTimeout      time.Duration
+	LoggerFlushTimeout      time.Duration
 
 	DebugUnhandledMessage bool
 	DebugEventStream      bool
 	DebugAutoReceive      bool
 	DebugLifecycle        bool
+	DebugMailboxDump      bool
+
+	SerializeMessages bool
+
+	// SelectionCache makes the selections of the system cache the actors
+	// they resolve to
+	SelectionCache bool
+
+	// Tracing sets span headers on every message told and publishes their
+	// start and finish to the event stream
+	Tracing bool
+
+	RestartResendsFailedMessage bool
+
+	// PropagatedHeaders are the headers Context().Tell carries from the
+	// message being received to the messages it tells
+	PropagatedHeaders []string
+
+	// StrictInitFuncs fails creating an actor given constructor args its
+	// type has no init func for
+	StrictInitFuncs bool
+
+	BehaviorStackMaxDepth int
+
+	// DispatcherAliases maps an old dispatcher id to the id it is resolved
+	// to while the old one is not configured anymore
+	DispatcherAliases map[string]string
+
+	PublishSuppressedDeadLetters         bool
+	SuppressedDeadLettersSummaryInterval time.Duration
+
+	LogDeadLetters                int
+	LogDeadLettersSummaryInterval time.Duration
+
+	DebugDeadlockDetection     bool
+	DeadlockDetectionInterval  time.Duration
+	DeadlockDetectionThreshold time.Duration
+
+	SystemStatusInterval time.Duration
 
 	LoggersDispatcher string
 
 	Loggers []string
+
+	// LoggerLevels maps a logger class name to the minimum level it is
+	// subscribed with, loggers without an entry use LogLevel
+	LoggerLevels map[string]string
 }
 
 func NewSettings(systemName string, config *configuration.Config) (settings *Settings, err error) {
 	s := &Settings{
-		userConfig: config,
-		name:       systemName,
+		userConfig:     config,
+		fallbackConfig: ReferenceConfig(),
+		name:           systemName,
 	}
 
 	s.rebuildConfig()
 
+	config = s.config
+
 	s.ProviderClass = config.GetString("akka.actor.provider")
+	s.SupervisorStrategyClass = config.GetString("akka.actor.guardian-supervisor-strategy", "akka.actor.StoppingSupervisorStrategy")
 	s.LogLevel = config.GetString("akka.loglevel")
 	s.SchedulerClass = config.GetString("akka.scheduler.implementation")
 
 	s.LogLevel = config.GetString("akka.loglevel")
 	s.StdoutLogLevel = config.GetString("akka.stdout-loglevel")
 	s.Loggers = config.GetStringList("akka.loggers")
+	s.LoggerLevels = make(map[string]string)
+	if levels := config.GetConfig("akka.logger-levels"); !levels.IsEmpty() {
+		for name, level := range levels.Root().GetObject().Items() {
+			s.LoggerLevels[strings.Trim(name, "\"")] = level.GetString()
+		}
+	}
 	s.LoggersDispatcher = config.GetString("akka.loggers-dispatcher")
 	s.LoggingFilter = config.GetString("akka.logging-filter")
 	s.LoggerStartTimeout = config.GetTimeDuration("akka.logger-startup-timeout")
+	s.LoggerFlushTimeout = config.GetTimeDuration("akka.logger-flush-timeout", 5*time.Second)
+	s.LogCallerInfo = config.GetBoolean("akka.log-caller-info", false)
 
 	s.DebugEventStream = config.GetBoolean("akka.actor.debug.event-stream", false)
 	s.DebugUnhandledMessage = config.GetBoolean("akka.actor.debug.unhandled")
 	s.DebugAutoReceive = config.GetBoolean("akka.actor.debug.autoreceive")
 	s.DebugLifecycle = config.GetBoolean("akka.actor.debug.lifecycle")
+	s.DebugMailboxDump = config.GetBoolean("akka.actor.debug.mailbox-dump", false)
+
+	s.SerializeMessages = config.GetBoolean("akka.actor.serialize-messages", false)
+
+	s.SelectionCache = config.GetBoolean("akka.actor.selection-cache", false)
+
+	s.Tracing = config.GetBoolean("akka.actor.tracing", false)
+
+	s.RestartResendsFailedMessage = config.GetBoolean("akka.actor.restart-resends-failed-message", false)
+
+	s.PropagatedHeaders = config.GetStringList("akka.actor.propagated-headers")
+
+	s.StrictInitFuncs = config.GetBoolean("akka.actor.strict-init-funcs", false)
+
+	s.BehaviorStackMaxDepth = int(config.GetInt32("akka.actor.behavior-stack-max-depth", 100))
+
+	s.DispatcherAliases = make(map[string]string)
+	if aliases := config.GetConfig("akka.actor.dispatcher-aliases"); !aliases.IsEmpty() {
+		for id, target := range aliases.Root().GetObject().Items() {
+			s.DispatcherAliases[strings.Trim(id, "\"")] = target.GetString()
+		}
+	}
+
+	s.PublishSuppressedDeadLetters = config.GetBoolean("akka.publish-suppressed-dead-letters", false)
+	s.SuppressedDeadLettersSummaryInterval = config.GetTimeDuration("akka.suppressed-dead-letters-summary-interval", 10*time.Second)
+
+	s.LogDeadLetters = int(config.GetInt32("akka.log-dead-letters", 10))
+	s.LogDeadLettersSummaryInterval = config.GetTimeDuration("akka.log-dead-letters-summary-interval", 5*time.Minute)
+
+	s.DebugDeadlockDetection = config.GetBoolean("akka.actor.debug.deadlock-detection", false)
+	s.DeadlockDetectionInterval = config.GetTimeDuration("akka.actor.debug.deadlock-detection-interval", time.Second)
+	s.DeadlockDetectionThreshold = config.GetTimeDuration("akka.actor.debug.deadlock-detection-threshold", 5*time.Second)
+
+	s.SystemStatusInterval = config.GetTimeDuration("akka.system-status-interval", 0)
 
 	settings = s
 	return
 }
 
 func (p *Settings) rebuildConfig() {
+	if p.userConfig == nil {
+		p.config = p.fallbackConfig
+		return
+	}
 	p.config = p.userConfig.WithFallback(p.fallbackConfig)
 }
 
@@ -71,3 +169,92 @@ func (p *Settings) String() string {
 func (p *Settings) Config() *configuration.Config {
 	return p.config
 }
+
+// The typed getters below return defaultVal when the path is absent or its
+// value can not be read as the requested type
+
+func (p *Settings) GetString(path string, defaultVal string) string {
+	if value, ok := p.leaf(path); ok {
+		return value
+	}
+	return defaultVal
+}
+
+func (p *Settings) GetInt(path string, defaultVal int) int {
+	value, ok := p.leaf(path)
+	if !ok {
+		return defaultVal
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return defaultVal
+	}
+
+	return i
+}
+
+func (p *Settings) GetFloat(path string, defaultVal float64) float64 {
+	value, ok := p.leaf(path)
+	if !ok {
+		return defaultVal
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return defaultVal
+	}
+
+	return f
+}
+
+func (p *Settings) GetBool(path string, defaultVal bool) (value bool) {
+	value = defaultVal
+	if _, ok := p.leaf(path); ok {
+		readOrKeepDefault(func() { value = p.config.GetBoolean(path, defaultVal) })
+	}
+	return
+}
+
+// GetDuration reads HOCON durations like 100ms, 5 seconds or 2m, a bare number
+// is taken as milliseconds
+func (p *Settings) GetDuration(path string, defaultVal time.Duration) (value time.Duration) {
+	value = defaultVal
+	if _, ok := p.leaf(path); ok {
+		readOrKeepDefault(func() { value = p.config.GetTimeDuration(path, defaultVal) })
+	}
+	return
+}
+
+func (p *Settings) GetStringList(path string, defaultVal []string) []string {
+	node := p.config.GetNode(path)
+	if node == nil || !node.IsArray() {
+		return defaultVal
+	}
+
+	var list []string
+	for _, item := range node.GetArray() {
+		if !item.IsString() {
+			return defaultVal
+		}
+		list = append(list, item.GetString())
+	}
+
+	return list
+}
+
+func (p *Settings) leaf(path string) (value string, ok bool) {
+	node := p.config.GetNode(path)
+	if node == nil || !node.IsString() {
+		return
+	}
+
+	return strings.TrimSpace(node.GetString()), true
+}
+
+// readOrKeepDefault runs read, the config panics on a value it can not read
+// as the requested type and the value keeps its default then
+func readOrKeepDefault(read func()) {
+	defer func() { recover() }()
+	read()
+}
@@ -0,0 +1,114 @@
+package akka
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-akka/configuration"
+)
+
+const settingsTestConfig = `
+my-actor {
+	name = "worker"
+	retries = 3
+	ratio = 0.5
+	enabled = on
+	timeout = 250ms
+	long-timeout = 2 minutes
+	bare-timeout = 1500
+	tags = ["a", "b"]
+
+	not-a-number = "three"
+	not-a-duration = "soon"
+	nested { value = 1 }
+}
+`
+
+func newTestSettings(t *testing.T) *Settings {
+	settings, err := NewSettings("SettingsTest", configuration.ParseString(settingsTestConfig))
+	if err != nil {
+		t.Fatalf("create settings failure: %s", err.Error())
+	}
+	return settings
+}
+
+func TestSettingsGettersReadPresentPaths(t *testing.T) {
+	settings := newTestSettings(t)
+
+	if v := settings.GetString("my-actor.name", ""); v != "worker" {
+		t.Fatalf("expected name worker, but got %q", v)
+	}
+
+	if v := settings.GetInt("my-actor.retries", 0); v != 3 {
+		t.Fatalf("expected retries 3, but got %d", v)
+	}
+
+	if v := settings.GetFloat("my-actor.ratio", 0); v != 0.5 {
+		t.Fatalf("expected ratio 0.5, but got %v", v)
+	}
+
+	if v := settings.GetBool("my-actor.enabled", false); !v {
+		t.Fatalf("expected enabled to be true")
+	}
+
+	durations := map[string]time.Duration{
+		"my-actor.timeout":      250 * time.Millisecond,
+		"my-actor.long-timeout": 2 * time.Minute,
+		"my-actor.bare-timeout": 1500 * time.Millisecond,
+	}
+
+	for path, expected := range durations {
+		if v := settings.GetDuration(path, 0); v != expected {
+			t.Fatalf("expected %s to be %s, but got %s", path, expected, v)
+		}
+	}
+
+	if v := settings.GetStringList("my-actor.tags", nil); !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Fatalf("expected tags [a b], but got %v", v)
+	}
+}
+
+func TestSettingsGettersFallBackForAbsentPaths(t *testing.T) {
+	settings := newTestSettings(t)
+
+	if v := settings.GetString("my-actor.absent", "default"); v != "default" {
+		t.Fatalf("absent string should fall back, but got %q", v)
+	}
+
+	if v := settings.GetInt("my-actor.absent", 7); v != 7 {
+		t.Fatalf("absent int should fall back, but got %d", v)
+	}
+
+	if v := settings.GetDuration("other-actor.timeout", time.Second); v != time.Second {
+		t.Fatalf("absent duration should fall back, but got %s", v)
+	}
+
+	if v := settings.GetStringList("my-actor.absent", []string{"x"}); !reflect.DeepEqual(v, []string{"x"}) {
+		t.Fatalf("absent list should fall back, but got %v", v)
+	}
+}
+
+func TestSettingsGettersFallBackOnTypeMismatch(t *testing.T) {
+	settings := newTestSettings(t)
+
+	if v := settings.GetInt("my-actor.not-a-number", 7); v != 7 {
+		t.Fatalf("non numeric int should fall back, but got %d", v)
+	}
+
+	if v := settings.GetDuration("my-actor.not-a-duration", time.Second); v != time.Second {
+		t.Fatalf("invalid duration should fall back, but got %s", v)
+	}
+
+	if v := settings.GetBool("my-actor.name", true); !v {
+		t.Fatalf("non boolean value should fall back")
+	}
+
+	if v := settings.GetString("my-actor.nested", "default"); v != "default" {
+		t.Fatalf("object value should not be read as string, but got %q", v)
+	}
+
+	if v := settings.GetStringList("my-actor.name", []string{"x"}); !reflect.DeepEqual(v, []string{"x"}) {
+		t.Fatalf("scalar value should not be read as list, but got %v", v)
+	}
+}
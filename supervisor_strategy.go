@@ -1,4 +1,46 @@
 package akka
 
+type Directive int
+
+const (
+	ResumeDirective Directive = iota
+	RestartDirective
+	StopDirective
+	EscalateDirective
+)
+
+func (p Directive) String() string {
+	switch p {
+	case ResumeDirective:
+		return "Resume"
+	case RestartDirective:
+		return "Restart"
+	case StopDirective:
+		return "Stop"
+	case EscalateDirective:
+		return "Escalate"
+	}
+	return "Unknown"
+}
+
+type Decider func(cause error) Directive
+
 type SupervisorStrategy interface {
+	Decide(cause error) Directive
+
+	// HandleFailure applies the decision for a failed child, it returns false
+	// when the failure could not be handled and must be escalated to the parent.
+	HandleFailure(context ActorContext, child ActorRef, cause error, stats ChildRestartStats, children []ChildRestartStats) (handled bool)
 }
+
+type SupervisorStrategyProvider interface {
+	SupervisorStrategy() SupervisorStrategy
+}
+
+// SupervisorStrategyConfigurator creates the strategy of the user guardian,
+// it is the class named by akka.actor.guardian-supervisor-strategy
+type SupervisorStrategyConfigurator interface {
+	Create() SupervisorStrategy
+}
+
+type UncaughtFailureHandler func(err error, path ActorPath)
@@ -1,4 +1,6 @@
 package akka
 
+// TerminationHook is told by the system guardian to the registered termination
+// hooks once the user guardian has stopped
 type TerminationHook struct {
 }
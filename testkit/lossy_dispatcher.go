@@ -0,0 +1,137 @@
+package testkit
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/dispatch"
+	"github.com/go-akka/akka/pkg/class_loader"
+	"github.com/go-akka/configuration"
+)
+
+func init() {
+	class_loader.Default.Register((*LossyDispatcherConfigurator)(nil), "akka.testkit.lossy-dispatcher")
+}
+
+// MessageLossSettings are the chances of a user message to be dropped or
+// delayed, the same Seed gives the same pattern of losses
+type MessageLossSettings struct {
+	DropRate  float64
+	DelayRate float64
+	Delay     time.Duration
+	Seed      int64
+
+	// DropToDeadLetters hands the dropped messages to the dead letters
+	// instead of losing them silently
+	DropToDeadLetters bool
+}
+
+// LossyDispatcher drops or delays the user messages dispatched by the
+// wrapped dispatcher, system messages are never touched. It is meant for
+// resilience tests only, the reference config does not use it anywhere
+type LossyDispatcher struct {
+	akka.MessageDispatcher
+
+	settings MessageLossSettings
+
+	random       *rand.Rand
+	randomLocker sync.Mutex
+}
+
+func NewLossyDispatcher(dispatcher akka.MessageDispatcher, settings MessageLossSettings) *LossyDispatcher {
+	return &LossyDispatcher{
+		MessageDispatcher: dispatcher,
+		settings:          settings,
+		random:            rand.New(rand.NewSource(settings.Seed)),
+	}
+}
+
+func (p *LossyDispatcher) Dispatch(receiver akka.ActorCell, invocation akka.Envelope) (err error) {
+	drop, delay := p.roll()
+
+	if drop {
+		if p.settings.DropToDeadLetters {
+			p.deadLetter(receiver, invocation)
+		}
+		return
+	}
+
+	if delay {
+		time.AfterFunc(p.settings.Delay, func() {
+			p.MessageDispatcher.Dispatch(receiver, invocation)
+		})
+		return
+	}
+
+	return p.MessageDispatcher.Dispatch(receiver, invocation)
+}
+
+func (p *LossyDispatcher) roll() (drop, delay bool) {
+	p.randomLocker.Lock()
+	defer p.randomLocker.Unlock()
+
+	if p.settings.DropRate > 0 && p.random.Float64() < p.settings.DropRate {
+		return true, false
+	}
+
+	if p.settings.DelayRate > 0 && p.random.Float64() < p.settings.DelayRate {
+		return false, true
+	}
+
+	return
+}
+
+func (p *LossyDispatcher) deadLetter(receiver akka.ActorCell, invocation akka.Envelope) {
+	cell, ok := receiver.(akka.Cell)
+	if !ok {
+		return
+	}
+
+	deadLetter := akka.NewDeadLetter(invocation.Message, invocation.Sender, receiver.Self())
+
+	if deadLetters := cell.System().DeadLetters(); deadLetters != nil {
+		deadLetters.Tell(deadLetter, invocation.Sender)
+		return
+	}
+
+	cell.System().EventStream().Publish(deadLetter)
+}
+
+// LossyDispatcherConfigurator creates a LossyDispatcher for the dispatchers
+// of type "akka.testkit.lossy-dispatcher", it reads the drop-rate,
+// delay-rate, delay, seed and drop-to-dead-letters of the dispatcher config
+type LossyDispatcherConfigurator struct {
+	instance akka.MessageDispatcher
+
+	config        *configuration.Config
+	prerequisites *akka.DispatcherPrerequisites
+}
+
+func (p *LossyDispatcherConfigurator) Construct(config *configuration.Config, prerequisites *akka.DispatcherPrerequisites) {
+	p.config = config
+	p.prerequisites = prerequisites
+
+	settings := MessageLossSettings{
+		DropRate:          config.GetFloat64("drop-rate", 0),
+		DelayRate:         config.GetFloat64("delay-rate", 0),
+		Delay:             config.GetTimeDuration("delay", 10*time.Millisecond),
+		Seed:              config.GetInt64("seed", 0),
+		DropToDeadLetters: config.GetBoolean("drop-to-dead-letters", false),
+	}
+
+	p.instance = NewLossyDispatcher(dispatch.NewDispatcherConfigurator(config, prerequisites).Dispatcher(), settings)
+}
+
+func (p *LossyDispatcherConfigurator) Config() *configuration.Config {
+	return p.config
+}
+
+func (p *LossyDispatcherConfigurator) DispatcherPrerequisites() *akka.DispatcherPrerequisites {
+	return p.prerequisites
+}
+
+func (p *LossyDispatcherConfigurator) Dispatcher() akka.MessageDispatcher {
+	return p.instance
+}
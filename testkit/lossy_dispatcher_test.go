@@ -0,0 +1,76 @@
+package testkit
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/configuration"
+)
+
+func TestLossyDispatcherDropsWithTheSeededPattern(t *testing.T) {
+	config := strings.Replace(testConfig, "loggers = []", `loggers = []
+
+	actor.lossy-dispatcher {
+		type = "akka.testkit.lossy-dispatcher"
+		drop-rate = 0.5
+		seed = 42
+		drop-to-dead-letters = on
+	}`, 1)
+
+	system, err := actor.NewActorSystem("LossyDispatcher", configuration.ParseString(config))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+	defer system.Terminate()
+
+	echoProps, err := props.Create((*echoActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	echo, err := system.ActorOf(echoProps.WithDispatcher("akka.actor.lossy-dispatcher"), "echo")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	deadLetters := NewTestKit(t, system)
+	defer deadLetters.Stop()
+	system.EventStream().Subscribe(deadLetters.TestActor(), reflect.TypeOf(akka.DeadLetter{}))
+
+	kit := NewTestKit(t, system)
+	defer kit.Stop()
+
+	random := rand.New(rand.NewSource(42))
+
+	var kept, dropped []int
+	for i := 0; i < 20; i++ {
+		if random.Float64() < 0.5 {
+			dropped = append(dropped, i)
+		} else {
+			kept = append(kept, i)
+		}
+		kit.Send(echo, i)
+	}
+
+	if len(kept) == 0 || len(dropped) == 0 {
+		t.Fatalf("seed should both keep and drop messages, but kept %v and dropped %v", kept, dropped)
+	}
+
+	for _, i := range kept {
+		kit.ExpectMsg(3*time.Second, i)
+	}
+	kit.ExpectNoMsg(50 * time.Millisecond)
+
+	for _, i := range dropped {
+		deadLetter := deadLetters.ExpectMsgType(3*time.Second, reflect.TypeOf(akka.DeadLetter{})).(akka.DeadLetter)
+		if deadLetter.Message() != i {
+			t.Fatalf("message %d should be dropped to the dead letters, but got %v", i, deadLetter.Message())
+		}
+	}
+}
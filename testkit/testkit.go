@@ -0,0 +1,148 @@
+package testkit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka"
+	"github.com/go-akka/akka/actor"
+)
+
+// TestKit owns a probe ref whose received messages are checked by the Expect
+// assertions, failures are reported with Fatalf of the test, so the
+// assertions have to be called from the test goroutine
+type TestKit struct {
+	t      testing.TB
+	system akka.ActorSystem
+	inbox  *actor.Inbox
+
+	deadline time.Time
+}
+
+func NewTestKit(t testing.TB, system akka.ActorSystem) *TestKit {
+	return &TestKit{
+		t:      t,
+		system: system,
+		inbox:  actor.NewInbox(system),
+	}
+}
+
+// TestActor is the probe ref, replies to it are queued for the assertions
+func (p *TestKit) TestActor() akka.ActorRef {
+	return p.inbox.Self()
+}
+
+func (p *TestKit) System() akka.ActorSystem {
+	return p.system
+}
+
+// Send tells message to target with the probe as sender
+func (p *TestKit) Send(target akka.ActorRef, message interface{}) {
+	if err := p.inbox.Send(target, message); err != nil {
+		p.t.Helper()
+		p.t.Fatalf("send %v to %s failure: %s", message, target, err.Error())
+	}
+}
+
+func (p *TestKit) Watch(target akka.ActorRef) {
+	if err := p.inbox.Watch(target); err != nil {
+		p.t.Helper()
+		p.t.Fatalf("watch %s failure: %s", target, err.Error())
+	}
+}
+
+func (p *TestKit) Stop() {
+	p.inbox.Stop()
+}
+
+// ExpectMsg waits for the next message and fails unless it deep equals
+// expected
+func (p *TestKit) ExpectMsg(timeout time.Duration, expected interface{}) (message interface{}) {
+	p.t.Helper()
+
+	message, ok := p.receive(timeout)
+	if !ok {
+		p.t.Fatalf("timeout (%s) while waiting for %v", p.remaining(timeout), expected)
+		return
+	}
+
+	if !reflect.DeepEqual(message, expected) {
+		p.t.Fatalf("expected %v, but got %v", expected, message)
+	}
+
+	return
+}
+
+// ExpectMsgType waits for the next message and fails unless it is assignable
+// to messageType
+func (p *TestKit) ExpectMsgType(timeout time.Duration, messageType reflect.Type) (message interface{}) {
+	p.t.Helper()
+
+	message, ok := p.receive(timeout)
+	if !ok {
+		p.t.Fatalf("timeout (%s) while waiting for a message of type %s", p.remaining(timeout), messageType)
+		return
+	}
+
+	if message == nil || !reflect.TypeOf(message).AssignableTo(messageType) {
+		p.t.Fatalf("expected a message of type %s, but got %T: %v", messageType, message, message)
+	}
+
+	return
+}
+
+// ExpectNoMsg fails if any message arrives within timeout
+func (p *TestKit) ExpectNoMsg(timeout time.Duration) {
+	p.t.Helper()
+
+	if message, ok := p.receive(timeout); ok {
+		p.t.Fatalf("expected no message, but got %v", message)
+	}
+}
+
+// Within fails unless fn takes between min and max, the Expect calls of fn
+// wait no longer than the time left until max
+func (p *TestKit) Within(min, max time.Duration, fn func()) {
+	p.t.Helper()
+
+	start := time.Now()
+
+	previous := p.deadline
+	p.deadline = start.Add(max)
+	defer func() { p.deadline = previous }()
+
+	fn()
+
+	elapsed := time.Since(start)
+	if elapsed < min {
+		p.t.Fatalf("block took %s, should at least take %s", elapsed, min)
+	}
+
+	if elapsed > max {
+		p.t.Fatalf("block took %s, exceeding %s", elapsed, max)
+	}
+}
+
+func (p *TestKit) receive(timeout time.Duration) (message interface{}, ok bool) {
+	message, err := p.inbox.Receive(p.remaining(timeout))
+	if err != nil {
+		return nil, false
+	}
+	return message, true
+}
+
+func (p *TestKit) remaining(timeout time.Duration) time.Duration {
+	if p.deadline.IsZero() {
+		return timeout
+	}
+
+	if left := time.Until(p.deadline); left < timeout {
+		if left < 0 {
+			return 0
+		}
+		return left
+	}
+
+	return timeout
+}
@@ -0,0 +1,95 @@
+package testkit
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-akka/akka/actor"
+	"github.com/go-akka/akka/actor/props"
+	"github.com/go-akka/configuration"
+)
+
+const testConfig = `
+akka {
+	loglevel = "ERROR"
+	stdout-loglevel = "ERROR"
+	loggers = []
+}
+`
+
+type echoActor struct {
+	*actor.UntypedActor
+}
+
+func (p *echoActor) Receive(message interface{}) (handled bool, err error) {
+	p.Sender().Tell(message, p.Self())
+	return true, nil
+}
+
+type echoed struct {
+	text string
+}
+
+// fatalRecorder keeps the failures instead of ending the test
+type fatalRecorder struct {
+	testing.TB
+
+	failures []string
+}
+
+func (p *fatalRecorder) Helper() {}
+
+func (p *fatalRecorder) Fatalf(format string, args ...interface{}) {
+	p.failures = append(p.failures, fmt.Sprintf(format, args...))
+}
+
+func TestTestKitAgainstEchoActor(t *testing.T) {
+	system, err := actor.NewActorSystem("TestKitEcho", configuration.ParseString(testConfig))
+	if err != nil {
+		t.Fatalf("create actor system failure: %s", err.Error())
+	}
+	defer system.Terminate()
+
+	echoProps, err := props.Create((*echoActor)(nil))
+	if err != nil {
+		t.Fatalf("create props failure: %s", err.Error())
+	}
+
+	echo, err := system.ActorOf(echoProps, "echo")
+	if err != nil {
+		t.Fatalf("create actor failure: %s", err.Error())
+	}
+
+	kit := NewTestKit(t, system)
+	defer kit.Stop()
+
+	kit.Send(echo, "hello")
+	kit.ExpectMsg(3*time.Second, "hello")
+
+	kit.Send(echo, &echoed{text: "typed"})
+	if msg := kit.ExpectMsgType(3*time.Second, reflect.TypeOf(&echoed{})); msg.(*echoed).text != "typed" {
+		t.Fatalf("expected the typed echo, but got %v", msg)
+	}
+
+	kit.Within(0, time.Second, func() {
+		kit.Send(echo, 42)
+		kit.ExpectMsg(3*time.Second, 42)
+	})
+
+	kit.ExpectNoMsg(50 * time.Millisecond)
+
+	recorder := &fatalRecorder{TB: t}
+	failing := NewTestKit(recorder, system)
+	defer failing.Stop()
+
+	failing.Send(echo, "unexpected")
+	failing.ExpectMsg(3*time.Second, "expected")
+	failing.ExpectNoMsg(20 * time.Millisecond)
+	failing.Within(20*time.Millisecond, time.Second, func() {})
+
+	if len(recorder.failures) != 2 {
+		t.Fatalf("mismatch and a too fast block should be reported, but got %v", recorder.failures)
+	}
+}